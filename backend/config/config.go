@@ -1,12 +1,20 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 type Config struct {
 	JWTSecret      string
 	JWTExpiration  int
 	ServerPort     string
 	AllowedOrigins []string
+	// Comment edits made later than this after posting are marked as edited
+	CommentEditWindow time.Duration
+	// Lets a user run timers on several issues at once
+	AllowConcurrentTimers bool
 }
 
 func GetConfig() *Config {
@@ -25,10 +33,17 @@ func GetConfig() *Config {
 		allowedOrigins = "http://localhost:5173"
 	}
 
+	editWindowMinutes, err := strconv.Atoi(os.Getenv("COMMENT_EDIT_WINDOW_MINUTES"))
+	if err != nil || editWindowMinutes < 0 {
+		editWindowMinutes = 5
+	}
+
 	return &Config{
-		JWTSecret:      jwtSecret,
-		JWTExpiration:  24, // hours
-		ServerPort:     serverPort,
-		AllowedOrigins: []string{allowedOrigins},
+		JWTSecret:             jwtSecret,
+		JWTExpiration:         24, // hours
+		ServerPort:            serverPort,
+		AllowedOrigins:        []string{allowedOrigins},
+		CommentEditWindow:     time.Duration(editWindowMinutes) * time.Minute,
+		AllowConcurrentTimers: os.Getenv("ALLOW_CONCURRENT_TIMERS") == "true",
 	}
 }
@@ -41,6 +41,8 @@ func InitDB() *gorm.DB {
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Constraint violations are returned as gorm errors, e.g. ErrDuplicatedKey
+		TranslateError: true,
 	})
 
 	if err != nil {
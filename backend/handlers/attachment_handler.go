@@ -145,7 +145,7 @@ func (h *AttachmentHandler) Download(c *gin.Context) {
 	}
 
 	ctx := context.Background()
-	url, err := h.storageService.GetPresignedURL(ctx, attachment.StorageKey)
+	url, err := h.storageService.GetPresignedURL(ctx, attachment.StorageKey, attachment.OriginalFilename, attachment.MimeType)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate download URL"})
 		return
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AutoAssignHandler struct {
+	autoAssignService *services.AutoAssignService
+	issueService      *services.IssueService
+	permissionService *services.PermissionService
+}
+
+func NewAutoAssignHandler(
+	autoAssignService *services.AutoAssignService,
+	issueService *services.IssueService,
+	permissionService *services.PermissionService,
+) *AutoAssignHandler {
+	return &AutoAssignHandler{
+		autoAssignService: autoAssignService,
+		issueService:      issueService,
+		permissionService: permissionService,
+	}
+}
+
+// UpdateSettings sets how the team's issues are assigned (manager only)
+func (h *AutoAssignHandler) UpdateSettings(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if ok, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), uint(teamID), string(models.RoleManager)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can change assignment settings"})
+		return
+	}
+
+	var req struct {
+		SingleAssignee bool                      `json:"single_assignee"`
+		Strategy       models.AutoAssignStrategy `json:"auto_assign_strategy" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := h.autoAssignService.UpdateSettings(uint(teamID), req.SingleAssignee, req.Strategy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// AutoAssign runs the team's strategy for an issue that has no owner yet, e.g.
+// after its labels were set
+func (h *AutoAssignHandler) AutoAssign(c *gin.Context) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	issue, err := h.issueService.GetByID(uint(issueID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, issue.TeamID, string(models.RoleMember)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	assignment, err := h.autoAssignService.AutoAssign(issue.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if assignment == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "No assignee picked: the team does not auto-assign, the issue already has an owner or nobody is available"})
+		return
+	}
+	c.JSON(http.StatusCreated, assignment)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BoardHandler struct {
+	boardService      *services.BoardService
+	permissionService *services.PermissionService
+}
+
+func NewBoardHandler(boardService *services.BoardService, permissionService *services.PermissionService) *BoardHandler {
+	return &BoardHandler{
+		boardService:      boardService,
+		permissionService: permissionService,
+	}
+}
+
+// GetBoard returns the team's Kanban board with per-column counts
+func (h *BoardHandler) GetBoard(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	board, err := h.boardService.GetBoard(uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+func (h *BoardHandler) GetWIPLimits(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	limits, err := h.boardService.GetWIPLimits(uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// SetWIPLimit creates or updates a WIP limit (managers only)
+func (h *BoardHandler) SetWIPLimit(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleManager)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can configure WIP limits"})
+		return
+	}
+
+	var req services.WIPLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := h.boardService.SetWIPLimit(uint(teamID), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, limit)
+}
+
+// DeleteWIPLimit removes the WIP limit of a status (managers only)
+func (h *BoardHandler) DeleteWIPLimit(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	statusID, _ := strconv.ParseUint(c.Param("statusId"), 10, 32)
+
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleManager)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can configure WIP limits"})
+		return
+	}
+
+	if err := h.boardService.DeleteWIPLimit(uint(teamID), uint(statusID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "WIP limit removed"})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"task-management/middleware"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarFeedHandler struct {
+	feedService *services.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(feedService *services.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{feedService: feedService}
+}
+
+func (h *CalendarFeedHandler) List(c *gin.Context) {
+	feeds, err := h.feedService.GetForUser(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, feeds)
+}
+
+// Create returns the new feed with its subscription URL, which contains the
+// secret token and is only shown once
+func (h *CalendarFeedHandler) Create(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, token, err := h.feedService.Create(middleware.GetUserID(c), req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"feed":  feed,
+		"token": token,
+		"path":  "/ics/" + token + ".ics",
+	})
+}
+
+func (h *CalendarFeedHandler) Revoke(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	feed, err := h.feedService.GetByID(uint(id))
+	if err != nil || feed.UserID != middleware.GetUserID(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar feed not found"})
+		return
+	}
+	if err := h.feedService.Revoke(feed.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar feed revoked"})
+}
+
+// Serve returns the iCalendar feed of a token. It is public: the token is the
+// credential, so calendar apps can poll it.
+func (h *CalendarFeedHandler) Serve(c *gin.Context) {
+	feed, err := h.feedService.Resolve(strings.TrimSuffix(c.Param("token"), ".ics"))
+	if err != nil {
+		c.String(http.StatusNotFound, "Calendar feed not found")
+		return
+	}
+
+	body, err := h.feedService.Render(feed.UserID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to build calendar")
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(body))
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"task-management/middleware"
+	"task-management/models"
 	"task-management/services"
 	"time"
 
@@ -28,6 +30,12 @@ func (h *CalendarHandler) GetCalendar(c *gin.Context) {
 		id, _ := strconv.ParseUint(teamIDStr, 10, 32)
 		teamIDVal := uint(id)
 		teamID = &teamIDVal
+
+		hasAccess, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), teamIDVal, string(models.RoleStakeholder))
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			return
+		}
 	}
 
 	var userID *uint
@@ -59,7 +67,7 @@ func (h *CalendarHandler) GetCalendar(c *gin.Context) {
 	}
 
 	// Get calendar events
-	events, err := h.calendarService.GetCalendarEvents(teamID, userID, startDate, endDate)
+	events, err := h.calendarService.GetCalendarEvents(middleware.GetUserID(c), teamID, userID, startDate, endDate)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
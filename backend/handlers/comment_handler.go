@@ -1,24 +1,62 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 	"task-management/middleware"
 	"task-management/models"
-	"task-management/repositories"
+	"task-management/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 type CommentHandler struct {
-	commentRepo *repositories.CommentRepository
+	commentService    *services.CommentService
+	mentionService    *services.MentionService
+	permissionService *services.PermissionService
 }
 
-func NewCommentHandler(commentRepo *repositories.CommentRepository) *CommentHandler {
-	return &CommentHandler{commentRepo: commentRepo}
+func NewCommentHandler(
+	commentService *services.CommentService,
+	mentionService *services.MentionService,
+	permissionService *services.PermissionService,
+) *CommentHandler {
+	return &CommentHandler{
+		commentService:    commentService,
+		mentionService:    mentionService,
+		permissionService: permissionService,
+	}
+}
+
+// isModerator reports whether the caller manages the team of the issue
+func (h *CommentHandler) isModerator(c *gin.Context, issueID uint) bool {
+	teamID, err := h.commentService.GetIssueTeamID(issueID)
+	if err != nil {
+		return false
+	}
+	ok, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), teamID, string(models.RoleManager))
+	return ok
+}
+
+// findComment loads the comment from the :commentId param, scoped to the :id issue
+func (h *CommentHandler) findComment(c *gin.Context) (*models.Comment, bool) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return nil, false
+	}
+
+	comment, err := h.commentService.GetByID(uint(commentID))
+	if err != nil || comment.IssueID != uint(issueID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return nil, false
+	}
+	return comment, true
 }
 
-// Create adds a new comment to an issue
+// Create adds a new comment to an issue, or a reply when parent_id is set
 func (h *CommentHandler) Create(c *gin.Context) {
 	issueID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -27,7 +65,8 @@ func (h *CommentHandler) Create(c *gin.Context) {
 	}
 
 	var input struct {
-		Content string `json:"content" binding:"required"`
+		Content  string `json:"content" binding:"required"`
+		ParentID *uint  `json:"parent_id"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -38,18 +77,24 @@ func (h *CommentHandler) Create(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 
 	comment := &models.Comment{
-		IssueID: uint(issueID),
-		UserID:  userID,
-		Content: input.Content,
+		IssueID:  uint(issueID),
+		UserID:   userID,
+		ParentID: input.ParentID,
+		Content:  input.Content,
 	}
 
-	if err := h.commentRepo.Create(comment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+	if err := h.commentService.Create(comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// The comment is saved either way; mentions are synced again on its next edit
+	if _, err := h.mentionService.SyncMentions(comment.IssueID, &comment.ID, comment.Content, userID); err != nil {
+		log.Printf("Failed to process mentions of comment %d: %v", comment.ID, err)
+	}
+
 	// Fetch with user info
-	created, _ := h.commentRepo.FindByID(comment.ID)
+	created, _ := h.commentService.GetByID(comment.ID)
 	if created != nil {
 		c.JSON(http.StatusCreated, created)
 	} else {
@@ -57,7 +102,7 @@ func (h *CommentHandler) Create(c *gin.Context) {
 	}
 }
 
-// List returns all comments for an issue
+// List returns the comment threads of an issue with replies and reaction counts
 func (h *CommentHandler) List(c *gin.Context) {
 	issueID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -65,7 +110,8 @@ func (h *CommentHandler) List(c *gin.Context) {
 		return
 	}
 
-	comments, err := h.commentRepo.FindByIssue(uint(issueID))
+	userID := middleware.GetUserID(c)
+	comments, err := h.commentService.GetThreads(uint(issueID), userID, h.isModerator(c, uint(issueID)))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
 		return
@@ -74,17 +120,10 @@ func (h *CommentHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, comments)
 }
 
-// Update modifies a comment (only by owner)
+// Update modifies a comment (only by owner), keeping the previous content as a revision
 func (h *CommentHandler) Update(c *gin.Context) {
-	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
-		return
-	}
-
-	comment, err := h.commentRepo.FindByID(uint(commentID))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+	comment, ok := h.findComment(c)
+	if !ok {
 		return
 	}
 
@@ -104,40 +143,172 @@ func (h *CommentHandler) Update(c *gin.Context) {
 		return
 	}
 
-	comment.Content = input.Content
-	if err := h.commentRepo.Update(comment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+	if err := h.commentService.Edit(comment, input.Content, userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Newly mentioned users are notified, removed mentions are dropped
+	mentions, err := h.mentionService.SyncMentions(comment.IssueID, &comment.ID, comment.Content, userID)
+	if err != nil {
+		log.Printf("Failed to process mentions of comment %d: %v", comment.ID, err)
+	} else {
+		comment.Mentions = mentions
+	}
+
 	c.JSON(http.StatusOK, comment)
 }
 
 // Delete removes a comment (only by owner)
 func (h *CommentHandler) Delete(c *gin.Context) {
-	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	comment, ok := h.findComment(c)
+	if !ok {
+		return
+	}
+
+	// Check ownership
+	userID := middleware.GetUserID(c)
+	if comment.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own comments"})
+		return
+	}
+
+	if err := h.commentService.Delete(comment.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+}
+
+// React toggles the caller's emoji reaction on a comment
+func (h *CommentHandler) React(c *gin.Context) {
+	comment, ok := h.findComment(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Emoji string `json:"emoji" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Emoji is required"})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	reactions, err := h.commentService.ToggleReaction(comment.ID, userID, input.Emoji)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reactions": reactions})
+}
+
+// Resolve marks a comment thread as resolved by the caller
+func (h *CommentHandler) Resolve(c *gin.Context) {
+	comment, ok := h.findComment(c)
+	if !ok {
 		return
 	}
 
-	comment, err := h.commentRepo.FindByID(uint(commentID))
+	userID := middleware.GetUserID(c)
+	if err := h.commentService.Resolve(comment, userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resolved, _ := h.commentService.GetByID(comment.ID)
+	c.JSON(http.StatusOK, resolved)
+}
+
+// Reopen clears the resolution of a comment thread
+func (h *CommentHandler) Reopen(c *gin.Context) {
+	comment, ok := h.findComment(c)
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.Reopen(comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// GetRevisions lists the earlier versions of a comment, newest first.
+// Revisions of hidden comments are only visible to moderators.
+func (h *CommentHandler) GetRevisions(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	comment, err := h.commentService.GetByID(uint(id))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
 		return
 	}
 
-	// Check ownership
+	if comment.Moderation != nil && !h.isModerator(c, comment.IssueID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This comment has been moderated"})
+		return
+	}
+
+	revisions, err := h.commentService.GetRevisions(comment.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// Moderate lets a team manager hide or remove someone's comment with a reason
+func (h *CommentHandler) Moderate(c *gin.Context) {
+	comment, ok := h.findComment(c)
+	if !ok {
+		return
+	}
+	if !h.isModerator(c, comment.IssueID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can moderate comments"})
+		return
+	}
+
+	var req services.ModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	userID := middleware.GetUserID(c)
-	if comment.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own comments"})
+	if err := h.commentService.Moderate(comment, userID, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.commentRepo.Delete(uint(commentID)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+	// Mentions in removed content no longer apply
+	if req.Action == models.CommentRemoved {
+		if _, err := h.mentionService.SyncMentions(comment.IssueID, &comment.ID, "", userID); err != nil {
+			log.Printf("Failed to process mentions of comment %d: %v", comment.ID, err)
+		}
+	}
+
+	moderated, _ := h.commentService.GetByID(comment.ID)
+	c.JSON(http.StatusOK, moderated)
+}
+
+// Unhide restores a hidden comment
+func (h *CommentHandler) Unhide(c *gin.Context) {
+	comment, ok := h.findComment(c)
+	if !ok {
+		return
+	}
+	if !h.isModerator(c, comment.IssueID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can moderate comments"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+	if err := h.commentService.Unhide(comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, comment)
 }
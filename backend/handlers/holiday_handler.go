@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HolidayHandler struct {
+	holidayService    *services.HolidayService
+	permissionService *services.PermissionService
+}
+
+func NewHolidayHandler(holidayService *services.HolidayService, permissionService *services.PermissionService) *HolidayHandler {
+	return &HolidayHandler{
+		holidayService:    holidayService,
+		permissionService: permissionService,
+	}
+}
+
+// requireManager only lets team managers change holiday calendars, since they
+// apply to the whole organization
+func (h *HolidayHandler) requireManager(c *gin.Context) bool {
+	teamIDs, err := h.permissionService.ManagedTeamIDs(middleware.GetUserID(c))
+	if err != nil || len(teamIDs) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can manage holiday calendars"})
+		return false
+	}
+	return true
+}
+
+// findCalendar loads the calendar from the :id param if it belongs to the
+// caller's organization
+func (h *HolidayHandler) findCalendar(c *gin.Context) (*models.HolidayCalendar, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	calendar, err := h.holidayService.GetCalendar(uint(id))
+	if err != nil || calendar.OrganizationID != middleware.GetOrganizationID(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Holiday calendar not found"})
+		return nil, false
+	}
+	return calendar, true
+}
+
+func (h *HolidayHandler) List(c *gin.Context) {
+	calendars, err := h.holidayService.GetCalendars(middleware.GetOrganizationID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, calendars)
+}
+
+func (h *HolidayHandler) Create(c *gin.Context) {
+	if !h.requireManager(c) {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	calendar, err := h.holidayService.CreateCalendar(middleware.GetOrganizationID(c), middleware.GetUserID(c), req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, calendar)
+}
+
+// GetByID returns a calendar with its holidays
+func (h *HolidayHandler) GetByID(c *gin.Context) {
+	calendar, ok := h.findCalendar(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, calendar)
+}
+
+func (h *HolidayHandler) Delete(c *gin.Context) {
+	if !h.requireManager(c) {
+		return
+	}
+	calendar, ok := h.findCalendar(c)
+	if !ok {
+		return
+	}
+	if err := h.holidayService.DeleteCalendar(calendar.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday calendar deleted"})
+}
+
+func (h *HolidayHandler) AddHoliday(c *gin.Context) {
+	if !h.requireManager(c) {
+		return
+	}
+	calendar, ok := h.findCalendar(c)
+	if !ok {
+		return
+	}
+
+	var input services.HolidayInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	holiday, err := h.holidayService.AddHoliday(calendar, &input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, holiday)
+}
+
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	if !h.requireManager(c) {
+		return
+	}
+	calendar, ok := h.findCalendar(c)
+	if !ok {
+		return
+	}
+
+	holidayID, _ := strconv.ParseUint(c.Param("holidayId"), 10, 32)
+	holiday, err := h.holidayService.GetHoliday(uint(holidayID))
+	if err != nil || holiday.CalendarID != calendar.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Holiday not found"})
+		return
+	}
+	if err := h.holidayService.DeleteHoliday(holiday.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday deleted"})
+}
+
+// Import adds the events of an uploaded ICS file (multipart field "file") to
+// the calendar
+func (h *HolidayHandler) Import(c *gin.Context) {
+	if !h.requireManager(c) {
+		return
+	}
+	calendar, ok := h.findCalendar(c)
+	if !ok {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		return
+	}
+	defer file.Close()
+
+	const maxSize = 1 << 20 // 1MB
+	if header.Size > maxSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File too large. Maximum size is 1MB"})
+		return
+	}
+
+	imported, err := h.holidayService.Import(calendar, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Holidays imported", "imported": imported})
+}
+
+// AddBusinessDays answers "what date is N business days from here" for the
+// caller's organization (?days=5, optional ?from=YYYY-MM-DD, default today)
+func (h *HolidayHandler) AddBusinessDays(c *gin.Context) {
+	days, err := strconv.Atoi(c.Query("days"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a whole number"})
+		return
+	}
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := c.Query("from"); value != "" {
+		from, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+	}
+
+	date, err := h.holidayService.AddBusinessDays(middleware.GetOrganizationID(c), from, days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"from": from.Format("2006-01-02"),
+		"days": days,
+		"date": date.Format("2006-01-02"),
+	})
+}
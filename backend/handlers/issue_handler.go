@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"task-management/middleware"
 	"task-management/models"
 	"task-management/services"
@@ -14,17 +17,23 @@ type IssueHandler struct {
 	issueService      *services.IssueService
 	assignmentService *services.AssignmentService
 	permissionService *services.PermissionService
+	mentionService    *services.MentionService
+	autoAssignService *services.AutoAssignService
 }
 
 func NewIssueHandler(
 	issueService *services.IssueService,
 	assignmentService *services.AssignmentService,
 	permissionService *services.PermissionService,
+	mentionService *services.MentionService,
+	autoAssignService *services.AutoAssignService,
 ) *IssueHandler {
 	return &IssueHandler{
 		issueService:      issueService,
 		assignmentService: assignmentService,
 		permissionService: permissionService,
+		mentionService:    mentionService,
+		autoAssignService: autoAssignService,
 	}
 }
 
@@ -36,28 +45,135 @@ func (h *IssueHandler) List(c *gin.Context) {
 	}
 
 	teamID, _ := strconv.ParseUint(teamIDStr, 10, 32)
-	issues, err := h.issueService.GetByTeam(uint(teamID))
+	filter := parseIssueFilter(c)
+	issues, err := h.issueService.Search(uint(teamID), filter, c.Query("sort"), c.Query("order"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, issues)
 }
 
-func (h *IssueHandler) Create(c *gin.Context) {
-	var issue models.Issue
-	if err := c.ShouldBindJSON(&issue); err != nil {
+// parseIssueFilter reads issue filters from the query string. List parameters
+// take comma-separated values, e.g. ?status_id=1,2&priority=HIGH,URGENT
+func parseIssueFilter(c *gin.Context) *models.IssueFilter {
+	filter := &models.IssueFilter{
+		StatusIDs:    parseIDList(c.Query("status_id")),
+		AssigneeIDs:  parseIDList(c.Query("assignee_id")),
+		LabelIDs:     parseIDList(c.Query("label_id")),
+		SprintID:     parseOptionalID(c.Query("sprint_id")),
+		MilestoneID:  parseOptionalID(c.Query("milestone_id")),
+		ProjectID:    parseOptionalID(c.Query("project_id")),
+		Unassigned:   c.Query("unassigned") == "true",
+		DeadlineFrom: c.Query("deadline_from"),
+		DeadlineTo:   c.Query("deadline_to"),
+		Search:       c.Query("q"),
+	}
+	for _, priority := range strings.Split(c.Query("priority"), ",") {
+		if priority != "" {
+			filter.Priorities = append(filter.Priorities, models.IssuePriority(strings.ToUpper(priority)))
+		}
+	}
+	return filter
+}
+
+func parseIDList(value string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(value, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+func parseOptionalID(value string) *uint {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil
+	}
+	result := uint(id)
+	return &result
+}
+
+// issueInput is an issue as sent by clients. DeadlineBusinessDays sets the
+// deadline that many business days from today instead of a fixed date.
+type issueInput struct {
+	models.Issue
+	DeadlineBusinessDays *int `json:"deadline_business_days"`
+	// LabelIDs sets the labels of a new issue; existing issues change them
+	// through their own endpoint
+	LabelIDs []uint `json:"label_ids"`
+}
+
+// IssueResponse is an issue with a warning when it was saved but a follow-up
+// step failed
+type IssueResponse struct {
+	*models.Issue
+	Warning string `json:"warning,omitempty"`
+}
+
+// bindIssue reads an issue from the request body, resolving a deadline given
+// in business days against the calendar of the organization of the team of
+// the existing issue, or of the new one when creating
+func (h *IssueHandler) bindIssue(c *gin.Context, existing *models.Issue) (*issueInput, bool) {
+	var input issueInput
+	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	issue := &input.Issue
+
+	if input.DeadlineBusinessDays != nil {
+		if *input.DeadlineBusinessDays < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "deadline_business_days must not be negative"})
+			return nil, false
+		}
+		teamID := issue.TeamID
+		if existing != nil {
+			teamID = existing.TeamID
+		}
+		deadline, err := h.issueService.DeadlineInBusinessDays(teamID, *input.DeadlineBusinessDays)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		issue.Deadline = &deadline
+	}
+	return &input, true
+}
+
+func (h *IssueHandler) Create(c *gin.Context) {
+	input, ok := h.bindIssue(c, nil)
+	if !ok {
 		return
 	}
+	issue := &input.Issue
 
 	userID := middleware.GetUserID(c)
-	if err := h.issueService.Create(&issue, userID); err != nil {
+	if err := h.issueService.Create(issue, input.LabelIDs, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// The issue is saved either way; mentions are synced again on its next edit
+	if _, err := h.mentionService.SyncMentions(issue.ID, nil, issue.Description, userID); err != nil {
+		log.Printf("Failed to process mentions of issue %d: %v", issue.ID, err)
+	}
+
+	// Teams with an auto assignment strategy get an owner right away, picked
+	// with the labels set above
+	assignment, err := h.autoAssignService.AutoAssign(issue.ID, userID)
+	if err != nil {
+		log.Printf("Failed to auto-assign issue %d: %v", issue.ID, err)
+		c.JSON(http.StatusCreated, IssueResponse{Issue: issue, Warning: "The issue was created but could not be auto-assigned"})
+		return
+	}
+	if assignment != nil {
+		issue.Assignments = []models.IssueAssignment{*assignment}
+	}
+
 	c.JSON(http.StatusCreated, issue)
 }
 
@@ -73,21 +189,43 @@ func (h *IssueHandler) GetByID(c *gin.Context) {
 
 func (h *IssueHandler) Update(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	var issue models.Issue
-	if err := c.ShouldBindJSON(&issue); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	existing, err := h.issueService.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	input, ok := h.bindIssue(c, existing)
+	if !ok {
 		return
 	}
 
+	issue := &input.Issue
 	issue.ID = uint(id)
-	if err := h.issueService.Update(&issue); err != nil {
+	if err := h.issueService.Update(issue); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	userID := middleware.GetUserID(c)
+	// The issue is saved either way; mentions are synced again on its next edit
+	if _, err := h.mentionService.SyncMentions(issue.ID, nil, issue.Description, userID); err != nil {
+		log.Printf("Failed to process mentions of issue %d: %v", issue.ID, err)
+	}
+
 	c.JSON(http.StatusOK, issue)
 }
 
+// GetMentions lists the users mentioned in the issue description and its comments
+func (h *IssueHandler) GetMentions(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	mentions, err := h.mentionService.GetByIssue(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mentions)
+}
+
 func (h *IssueHandler) Delete(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err := h.issueService.Delete(uint(id)); err != nil {
@@ -98,25 +236,142 @@ func (h *IssueHandler) Delete(c *gin.Context) {
 }
 
 func (h *IssueHandler) Assign(c *gin.Context) {
-	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	issue, ok := h.requireAssigner(c)
+	if !ok {
+		return
+	}
 
 	var req services.AssignmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !h.requireAssignee(c, issue, req.UserID) {
+		return
+	}
 
-	req.IssueID = uint(issueID)
+	req.IssueID = issue.ID
 	userID := middleware.GetUserID(c)
 
-	if err := h.assignmentService.Assign(&req, userID); err != nil {
+	_, warning, err := h.assignmentService.Assign(&req, userID)
+	if errors.Is(err, services.ErrOverCapacity) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "capacity": warning})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if warning != nil {
+		c.JSON(http.StatusCreated, gin.H{"message": "Issue assigned", "warning": warning.Warning(), "capacity": warning})
+		return
+	}
 	c.JSON(http.StatusCreated, gin.H{"message": "Issue assigned"})
 }
 
+// requireAssigner loads the issue in :id and checks that the caller is a
+// manager or assistant of its team, who manage its assignments
+func (h *IssueHandler) requireAssigner(c *gin.Context) (*models.Issue, bool) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	issue, err := h.issueService.GetByID(uint(issueID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return nil, false
+	}
+	hasAccess, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), issue.TeamID, string(models.RoleAssistant))
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return nil, false
+	}
+	return issue, true
+}
+
+// requireAssignee checks that the user can be assigned the issue, as a member
+// of its team other than a stakeholder
+func (h *IssueHandler) requireAssignee(c *gin.Context, issue *models.Issue, userID uint) bool {
+	isMember, _ := h.permissionService.HasTeamAccess(userID, issue.TeamID, string(models.RoleMember))
+	if !isMember {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee must be a member of the issue's team"})
+		return false
+	}
+	return true
+}
+
+// findAssignment loads the issue in :id and its assignment from the
+// :assignmentId param if the caller manages its assignments
+func (h *IssueHandler) findAssignment(c *gin.Context) (*models.Issue, *models.IssueAssignment, bool) {
+	issue, ok := h.requireAssigner(c)
+	if !ok {
+		return nil, nil, false
+	}
+	assignmentID, _ := strconv.ParseUint(c.Param("assignmentId"), 10, 32)
+	assignment, err := h.assignmentService.GetByID(uint(assignmentID))
+	if err != nil || assignment.IssueID != issue.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+		return nil, nil, false
+	}
+	return issue, assignment, true
+}
+
+// ListAssignments returns the assignment timeline of the issue
+func (h *IssueHandler) ListAssignments(c *gin.Context) {
+	issue, ok := h.requireAssigner(c)
+	if !ok {
+		return
+	}
+	timeline, err := h.assignmentService.GetTimeline(issue.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, timeline)
+}
+
+func (h *IssueHandler) Unassign(c *gin.Context) {
+	_, assignment, ok := h.findAssignment(c)
+	if !ok {
+		return
+	}
+	if err := h.assignmentService.Unassign(assignment, middleware.GetUserID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Assignment ended"})
+}
+
+func (h *IssueHandler) Reassign(c *gin.Context) {
+	issue, assignment, ok := h.findAssignment(c)
+	if !ok {
+		return
+	}
+
+	var req services.ReassignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.requireAssignee(c, issue, req.UserID) {
+		return
+	}
+
+	replacement, warning, err := h.assignmentService.Reassign(assignment, &req, middleware.GetUserID(c))
+	if errors.Is(err, services.ErrOverCapacity) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "capacity": warning})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if warning != nil {
+		c.JSON(http.StatusCreated, gin.H{"assignment": replacement, "warning": warning.Warning(), "capacity": warning})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"assignment": replacement})
+}
+
 func (h *IssueHandler) UpdateStatus(c *gin.Context) {
 	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
@@ -130,14 +385,56 @@ func (h *IssueHandler) UpdateStatus(c *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(c)
-	if err := h.issueService.UpdateStatus(uint(issueID), req.StatusID, userID); err != nil {
+	warning, err := h.issueService.UpdateStatus(uint(issueID), req.StatusID, userID)
+	if errors.Is(err, services.ErrWIPLimitExceeded) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "wip_limit": warning})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if warning != nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Status updated", "warning": "WIP limit exceeded", "wip_limit": warning})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Status updated"})
 }
 
+// Move reorders an issue in the backlog and optionally changes its column
+func (h *IssueHandler) Move(c *gin.Context) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req services.MoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	warning, err := h.issueService.Move(uint(issueID), userID, &req)
+	if errors.Is(err, services.ErrWIPLimitExceeded) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "wip_limit": warning})
+		return
+	}
+	if errors.Is(err, services.ErrRankTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	issue, _ := h.issueService.GetByID(uint(issueID))
+	if warning != nil {
+		c.JSON(http.StatusOK, gin.H{"issue": issue, "warning": "WIP limit exceeded", "wip_limit": warning})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"issue": issue})
+}
+
 func (h *IssueHandler) Hold(c *gin.Context) {
 	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
@@ -180,21 +477,3 @@ func (h *IssueHandler) GetActivities(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, activities)
 }
-
-func (h *IssueHandler) LogWork(c *gin.Context) {
-	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-
-	var log models.IssueWorkLog
-	if err := c.ShouldBindJSON(&log); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	userID := middleware.GetUserID(c)
-	if err := h.issueService.LogWork(uint(issueID), userID, &log); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, gin.H{"message": "Work logged"})
-}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/repositories"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabelHandler struct {
+	labelRepo         *repositories.LabelRepository
+	issueService      *services.IssueService
+	permissionService *services.PermissionService
+}
+
+func NewLabelHandler(
+	labelRepo *repositories.LabelRepository,
+	issueService *services.IssueService,
+	permissionService *services.PermissionService,
+) *LabelHandler {
+	return &LabelHandler{
+		labelRepo:         labelRepo,
+		issueService:      issueService,
+		permissionService: permissionService,
+	}
+}
+
+func (h *LabelHandler) List(c *gin.Context) {
+	orgID := middleware.GetOrganizationID(c)
+	labels, err := h.labelRepo.FindByOrganization(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, labels)
+}
+
+func (h *LabelHandler) Create(c *gin.Context) {
+	var label models.Label
+	if err := c.ShouldBindJSON(&label); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if label.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+
+	label.OrganizationID = middleware.GetOrganizationID(c)
+	if err := h.labelRepo.Create(&label); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, label)
+}
+
+func (h *LabelHandler) Update(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	label, err := h.labelRepo.FindByID(uint(id))
+	if err != nil || label.OrganizationID != middleware.GetOrganizationID(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
+		return
+	}
+
+	var req struct {
+		Name  string `json:"name" binding:"required"`
+		Color string `json:"color"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label.Name = req.Name
+	if req.Color != "" {
+		label.Color = req.Color
+	}
+	if err := h.labelRepo.Update(label); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, label)
+}
+
+func (h *LabelHandler) Delete(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	label, err := h.labelRepo.FindByID(uint(id))
+	if err != nil || label.OrganizationID != middleware.GetOrganizationID(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
+		return
+	}
+
+	if err := h.labelRepo.Delete(label.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Label deleted"})
+}
+
+// requireIssueAccess loads the :id issue of the caller's organization, which
+// the caller must be a member of the team of
+func (h *LabelHandler) requireIssueAccess(c *gin.Context) (*models.Issue, bool) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	issue, err := h.issueService.GetByID(uint(issueID))
+	if err != nil || issue.Team.OrganizationID != middleware.GetOrganizationID(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return nil, false
+	}
+	hasAccess, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), issue.TeamID, string(models.RoleMember))
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return nil, false
+	}
+	return issue, true
+}
+
+// SetIssueLabels replaces the labels of an issue
+func (h *LabelHandler) SetIssueLabels(c *gin.Context) {
+	issue, ok := h.requireIssueAccess(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		LabelIDs []uint `json:"label_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	labels := []models.Label{}
+	if len(req.LabelIDs) > 0 {
+		var err error
+		labels, err = h.labelRepo.FindByIDs(middleware.GetOrganizationID(c), req.LabelIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(labels) != len(req.LabelIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown label"})
+			return
+		}
+	}
+
+	if err := h.labelRepo.ReplaceIssueLabels(issue.ID, labels); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, labels)
+}
+
+func (h *LabelHandler) GetMySkills(c *gin.Context) {
+	skills, err := h.labelRepo.FindUserSkills(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, skills)
+}
+
+// SetMySkills replaces the caller's skills, which are organization labels
+// matched against issue labels by skills-based auto assignment
+func (h *LabelHandler) SetMySkills(c *gin.Context) {
+	var req struct {
+		LabelIDs []uint `json:"label_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	labels := []models.Label{}
+	if len(req.LabelIDs) > 0 {
+		var err error
+		labels, err = h.labelRepo.FindByIDs(middleware.GetOrganizationID(c), req.LabelIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(labels) != len(req.LabelIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown label"})
+			return
+		}
+	}
+
+	if err := h.labelRepo.ReplaceUserSkills(middleware.GetUserID(c), labels); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, labels)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveHandler struct {
+	leaveService      *services.LeaveService
+	permissionService *services.PermissionService
+}
+
+func NewLeaveHandler(leaveService *services.LeaveService, permissionService *services.PermissionService) *LeaveHandler {
+	return &LeaveHandler{
+		leaveService:      leaveService,
+		permissionService: permissionService,
+	}
+}
+
+// List returns the caller's leave requests
+func (h *LeaveHandler) List(c *gin.Context) {
+	leaves, err := h.leaveService.GetForUser(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, leaves)
+}
+
+// Create requests leave for the caller
+func (h *LeaveHandler) Create(c *gin.Context) {
+	var input services.LeaveInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	leave, err := h.leaveService.Request(middleware.GetUserID(c), &input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, leave)
+}
+
+// findVisible loads the leave from the :id param if the caller owns or can
+// review it
+func (h *LeaveHandler) findVisible(c *gin.Context) (*models.LeaveRequest, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	leave, err := h.leaveService.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave not found"})
+		return nil, false
+	}
+
+	userID := middleware.GetUserID(c)
+	if leave.UserID != userID && !h.leaveService.CanReview(userID, leave.UserID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave not found"})
+		return nil, false
+	}
+	return leave, true
+}
+
+func (h *LeaveHandler) GetByID(c *gin.Context) {
+	leave, ok := h.findVisible(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, leave)
+}
+
+func (h *LeaveHandler) review(c *gin.Context, status models.LeaveStatus) {
+	leave, ok := h.findVisible(c)
+	if !ok {
+		return
+	}
+	userID := middleware.GetUserID(c)
+	if !h.leaveService.CanReview(userID, leave.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can review leave"})
+		return
+	}
+
+	var req services.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.leaveService.Review(leave, userID, status, req.Comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reviewed, _ := h.leaveService.GetByID(leave.ID)
+	c.JSON(http.StatusOK, reviewed)
+}
+
+func (h *LeaveHandler) Approve(c *gin.Context) {
+	h.review(c, models.LeaveApproved)
+}
+
+// Reject declines pending leave; a comment is required
+func (h *LeaveHandler) Reject(c *gin.Context) {
+	h.review(c, models.LeaveRejected)
+}
+
+// Cancel withdraws the caller's own leave
+func (h *LeaveHandler) Cancel(c *gin.Context) {
+	leave, ok := h.findVisible(c)
+	if !ok {
+		return
+	}
+	if leave.UserID != middleware.GetUserID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the requester can cancel leave"})
+		return
+	}
+
+	if err := h.leaveService.Cancel(leave); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, leave)
+}
+
+// ListForTeam returns the pending and approved leave of a team's members for
+// its managers (?from and ?to as YYYY-MM-DD, defaults to the next four weeks)
+func (h *LeaveHandler) ListForTeam(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	userID := middleware.GetUserID(c)
+	hasAccess, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleManager))
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can view absences"})
+		return
+	}
+
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, 27)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return
+		}
+		to = parsed
+	}
+
+	absences, err := h.leaveService.GetTeamAbsences(uint(teamID), from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, absences)
+}
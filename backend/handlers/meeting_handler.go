@@ -1,35 +1,128 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"task-management/middleware"
 	"task-management/models"
 	"task-management/repositories"
+	"task-management/services"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type MeetingHandler struct {
-	meetingRepo *repositories.MeetingRepository
+	meetingRepo          *repositories.MeetingRepository
+	meetingService       *services.MeetingService
+	schedulingService    *services.SchedulingService
+	meetingImportService *services.MeetingImportService
+	permissionService    *services.PermissionService
 }
 
-func NewMeetingHandler(meetingRepo *repositories.MeetingRepository) *MeetingHandler {
-	return &MeetingHandler{meetingRepo: meetingRepo}
+func NewMeetingHandler(
+	meetingRepo *repositories.MeetingRepository,
+	meetingService *services.MeetingService,
+	schedulingService *services.SchedulingService,
+	meetingImportService *services.MeetingImportService,
+	permissionService *services.PermissionService,
+) *MeetingHandler {
+	return &MeetingHandler{
+		meetingRepo:          meetingRepo,
+		meetingService:       meetingService,
+		schedulingService:    schedulingService,
+		meetingImportService: meetingImportService,
+		permissionService:    permissionService,
+	}
+}
+
+// MeetingResponse is a meeting with a warning when attendees are already
+// booked or on leave
+type MeetingResponse struct {
+	*models.Meeting
+	Warning   string                     `json:"warning,omitempty"`
+	Conflicts *services.MeetingConflicts `json:"conflicts,omitempty"`
+}
+
+// attendeeIDs returns the users invited to the meeting who have not declined
+func attendeeIDs(meeting *models.Meeting) []uint {
+	var userIDs []uint
+	for _, attendee := range meeting.Attendees {
+		if attendee.Status != models.AttendeeStatusDeclined {
+			userIDs = append(userIDs, attendee.UserID)
+		}
+	}
+	return userIDs
+}
+
+// checkConflicts reports the attendees' conflicts with the meeting, responding
+// with 409 when the team blocks them
+func (h *MeetingHandler) checkConflicts(c *gin.Context, meeting *models.Meeting, userIDs []uint) (*services.MeetingConflicts, bool) {
+	conflicts, err := h.schedulingService.CheckConflicts(meeting, userIDs, middleware.GetUserID(c))
+	if errors.Is(err, services.ErrMeetingConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicts": conflicts})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return conflicts, true
+}
+
+// viewerLocation is the timezone of the user making the request, which
+// meeting times are shown in
+func (h *MeetingHandler) viewerLocation(c *gin.Context) *time.Location {
+	return h.meetingService.UserLocation(middleware.GetUserID(c))
+}
+
+func (h *MeetingHandler) respond(c *gin.Context, code int, meeting *models.Meeting, conflicts *services.MeetingConflicts) {
+	if meeting != nil {
+		meeting.InZone(h.viewerLocation(c))
+	}
+	response := MeetingResponse{Meeting: meeting, Conflicts: conflicts}
+	if conflicts != nil {
+		response.Warning = conflicts.Warning()
+	}
+	c.JSON(code, response)
+}
+
+// MeetingDetails are the fields of a meeting that are given when creating it
+// and when changing it
+type MeetingDetails struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	services.MeetingTimesInput
+	Location string `json:"location"`
+	services.RecurrenceInput
+	AttendeeIDs []uint `json:"attendee_ids"`
 }
 
 type CreateMeetingRequest struct {
-	TeamID           uint                     `json:"team_id" binding:"required"`
-	Title            string                   `json:"title" binding:"required"`
-	Description      string                   `json:"description"`
-	MeetingDate      string                   `json:"meeting_date" binding:"required"`
-	StartTime        string                   `json:"start_time" binding:"required"`
-	EndTime          string                   `json:"end_time" binding:"required"`
-	Location         string                   `json:"location"`
-	IsRecurring      bool                     `json:"is_recurring"`
-	RecurringPattern *models.RecurringPattern `json:"recurring_pattern"`
-	AttendeeIDs      []uint                   `json:"attendee_ids"`
+	TeamID uint `json:"team_id" binding:"required"`
+	MeetingDetails
+}
+
+// UpdateMeetingRequest changes a meeting, or with a scope of this or following
+// the occurrence of a recurring meeting on OccurrenceDate (YYYY-MM-DD) alone
+// or with the ones after it. A meeting stays in its team.
+type UpdateMeetingRequest struct {
+	MeetingDetails
+	Scope          string `json:"scope"`
+	OccurrenceDate string `json:"occurrence_date"`
+}
+
+// parseOccurrenceDate parses an optional occurrence date
+func parseOccurrenceDate(value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, false
+	}
+	return &date, true
 }
 
 func (h *MeetingHandler) Create(c *gin.Context) {
@@ -39,25 +132,26 @@ func (h *MeetingHandler) Create(c *gin.Context) {
 		return
 	}
 
-	meetingDate, err := time.Parse("2006-01-02", req.MeetingDate)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting date format"})
-		return
-	}
-
 	userID := middleware.GetUserID(c)
 
 	meeting := &models.Meeting{
-		TeamID:           req.TeamID,
-		Title:            req.Title,
-		Description:      req.Description,
-		MeetingDate:      meetingDate,
-		StartTime:        req.StartTime,
-		EndTime:          req.EndTime,
-		Location:         req.Location,
-		IsRecurring:      req.IsRecurring,
-		RecurringPattern: req.RecurringPattern,
-		CreatedBy:        userID,
+		TeamID:      req.TeamID,
+		Title:       req.Title,
+		Description: req.Description,
+		Location:    req.Location,
+		CreatedBy:   userID,
+	}
+	if err := req.MeetingTimesInput.Apply(meeting, h.meetingService.UserLocation(userID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.RecurrenceInput.Apply(meeting); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	conflicts, ok := h.checkConflicts(c, meeting, req.AttendeeIDs)
+	if !ok {
+		return
 	}
 
 	if err := h.meetingRepo.Create(meeting); err != nil {
@@ -77,7 +171,7 @@ func (h *MeetingHandler) Create(c *gin.Context) {
 
 	// Fetch complete meeting with attendees
 	created, _ := h.meetingRepo.FindByID(meeting.ID)
-	c.JSON(http.StatusCreated, created)
+	h.respond(c, http.StatusCreated, created, conflicts)
 }
 
 func (h *MeetingHandler) List(c *gin.Context) {
@@ -91,22 +185,43 @@ func (h *MeetingHandler) List(c *gin.Context) {
 	startDateStr := c.Query("start_date")
 	endDateStr := c.Query("end_date")
 
-	var meetings []models.Meeting
-	var err error
-
+	// With a date range, recurring meetings are expanded into their
+	// occurrences
 	if startDateStr != "" && endDateStr != "" {
-		startDate, _ := time.Parse("2006-01-02", startDateStr)
-		endDate, _ := time.Parse("2006-01-02", endDateStr)
-		meetings, err = h.meetingRepo.FindByDateRange(uint(teamID), startDate, endDate)
-	} else {
-		meetings, err = h.meetingRepo.FindByTeam(uint(teamID))
+		startDate, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format"})
+			return
+		}
+		endDate, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format"})
+			return
+		}
+		// The dates are days in the viewer's timezone
+		loc := h.viewerLocation(c)
+		occurrences, err := h.meetingService.GetOccurrences(uint(teamID), startDate, endDate, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for i := range occurrences {
+			occurrences[i].InZone(loc)
+		}
+		c.JSON(http.StatusOK, occurrences)
+		return
 	}
 
+	meetings, err := h.meetingRepo.FindByTeam(uint(teamID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings"})
 		return
 	}
 
+	loc := h.viewerLocation(c)
+	for i := range meetings {
+		meetings[i].InZone(loc)
+	}
 	c.JSON(http.StatusOK, meetings)
 }
 
@@ -117,6 +232,7 @@ func (h *MeetingHandler) GetByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
 		return
 	}
+	meeting.InZone(h.viewerLocation(c))
 	c.JSON(http.StatusOK, meeting)
 }
 
@@ -129,35 +245,72 @@ func (h *MeetingHandler) Update(c *gin.Context) {
 		return
 	}
 
-	var req CreateMeetingRequest
+	var req UpdateMeetingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	meetingDate, _ := time.Parse("2006-01-02", req.MeetingDate)
+	occurrenceDate, ok := parseOccurrenceDate(req.OccurrenceDate)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid occurrence_date format"})
+		return
+	}
+
+	changes := &models.Meeting{
+		Title:       req.Title,
+		Description: req.Description,
+		Location:    req.Location,
+	}
+	// Without a timezone the meeting keeps its own
+	if err := req.MeetingTimesInput.Apply(changes, existing.Zone()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.RecurrenceInput.Apply(changes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	existing.Title = req.Title
-	existing.Description = req.Description
-	existing.MeetingDate = meetingDate
-	existing.StartTime = req.StartTime
-	existing.EndTime = req.EndTime
-	existing.Location = req.Location
-	existing.IsRecurring = req.IsRecurring
-	existing.RecurringPattern = req.RecurringPattern
+	// A single changed occurrence does not repeat
+	candidate := *changes
+	candidate.ID, candidate.TeamID = existing.ID, existing.TeamID
+	candidate.CreatedBy, candidate.Creator = existing.CreatedBy, existing.Creator
+	if req.Scope == services.MeetingScopeThis && existing.IsRecurring {
+		candidate.IsRecurring = false
+	}
+	conflicts, ok := h.checkConflicts(c, &candidate, attendeeIDs(existing))
+	if !ok {
+		return
+	}
 
-	if err := h.meetingRepo.Update(existing); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update meeting"})
+	updated, err := h.meetingService.Update(existing, changes, req.Scope, occurrenceDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, existing)
+	h.respond(c, http.StatusOK, updated, conflicts)
 }
 
+// Delete deletes a meeting. With ?scope=this or ?scope=following and
+// ?occurrence_date=YYYY-MM-DD it cancels one occurrence of a recurring
+// meeting, or ends the series before it.
 func (h *MeetingHandler) Delete(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err := h.meetingRepo.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete meeting"})
+	meeting, err := h.meetingRepo.FindByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+	occurrenceDate, ok := parseOccurrenceDate(c.Query("occurrence_date"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid occurrence_date format"})
+		return
+	}
+
+	if err := h.meetingService.Delete(meeting, c.Query("scope"), occurrenceDate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Meeting deleted"})
@@ -175,8 +328,19 @@ func (h *MeetingHandler) AddAttendee(c *gin.Context) {
 		return
 	}
 
+	meeting, err := h.meetingRepo.FindByID(uint(meetingID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	conflicts, ok := h.checkConflicts(c, meeting, []uint{req.UserID})
+	if !ok {
+		return
+	}
+
 	attendee := &models.MeetingAttendee{
-		MeetingID: uint(meetingID),
+		MeetingID: meeting.ID,
 		UserID:    req.UserID,
 		Status:    models.AttendeeStatusPending,
 	}
@@ -186,6 +350,10 @@ func (h *MeetingHandler) AddAttendee(c *gin.Context) {
 		return
 	}
 
+	if conflicts != nil {
+		c.JSON(http.StatusCreated, gin.H{"message": "Attendee added", "warning": conflicts.Warning(), "conflicts": conflicts})
+		return
+	}
 	c.JSON(http.StatusCreated, gin.H{"message": "Attendee added"})
 }
 
@@ -219,3 +387,80 @@ func (h *MeetingHandler) RespondToMeeting(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Response recorded"})
 }
+
+// Import creates or updates the team's meetings from an uploaded ICS file
+// (multipart fields "file" and "team_id")
+func (h *MeetingHandler) Import(c *gin.Context) {
+	teamID, err := strconv.ParseUint(c.PostForm("team_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_id is required"})
+		return
+	}
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleMember)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		return
+	}
+	defer file.Close()
+
+	const maxSize = 1 << 20 // 1MB
+	if header.Size > maxSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File too large. Maximum size is 1MB"})
+		return
+	}
+
+	result, err := h.meetingImportService.Import(uint(teamID), userID, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// FreeSlots finds times when all of the attendees are free for a meeting of
+// the given duration
+func (h *MeetingHandler) FreeSlots(c *gin.Context) {
+	var req services.FreeSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slots, err := h.schedulingService.FindFreeSlots(&req, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"slots": slots})
+}
+
+// UpdateSettings sets whether the team warns about or blocks meetings whose
+// attendees are already booked or on leave
+func (h *MeetingHandler) UpdateSettings(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if ok, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), uint(teamID), string(models.RoleManager)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can change meeting settings"})
+		return
+	}
+
+	var req struct {
+		Policy models.MeetingConflictPolicy `json:"meeting_conflict_policy" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := h.schedulingService.UpdateConflictPolicy(uint(teamID), req.Policy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
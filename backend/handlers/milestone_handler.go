@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MilestoneHandler struct {
+	milestoneService *services.MilestoneService
+}
+
+func NewMilestoneHandler(milestoneService *services.MilestoneService) *MilestoneHandler {
+	return &MilestoneHandler{milestoneService: milestoneService}
+}
+
+// findMilestone loads the milestone from the :id param, scoped to the caller's organization
+func (h *MilestoneHandler) findMilestone(c *gin.Context) (*models.Milestone, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	milestone, err := h.milestoneService.GetByID(uint(id))
+	if err != nil || milestone.OrganizationID != middleware.GetOrganizationID(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+		return nil, false
+	}
+	return milestone, true
+}
+
+func (h *MilestoneHandler) List(c *gin.Context) {
+	var teamID *uint
+	if teamIDStr := c.Query("team_id"); teamIDStr != "" {
+		id, _ := strconv.ParseUint(teamIDStr, 10, 32)
+		teamIDVal := uint(id)
+		teamID = &teamIDVal
+	}
+
+	orgID := middleware.GetOrganizationID(c)
+	milestones, err := h.milestoneService.GetByOrganization(orgID, teamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, milestones)
+}
+
+func (h *MilestoneHandler) Create(c *gin.Context) {
+	var req services.MilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orgID := middleware.GetOrganizationID(c)
+	userID := middleware.GetUserID(c)
+	milestone, err := h.milestoneService.Create(&req, orgID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, milestone)
+}
+
+func (h *MilestoneHandler) GetByID(c *gin.Context) {
+	milestone, ok := h.findMilestone(c)
+	if !ok {
+		return
+	}
+
+	issues, err := h.milestoneService.GetIssues(milestone.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	milestone.Issues = issues
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+func (h *MilestoneHandler) Update(c *gin.Context) {
+	existing, ok := h.findMilestone(c)
+	if !ok {
+		return
+	}
+
+	var req services.MilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	milestone, err := h.milestoneService.Update(existing.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+func (h *MilestoneHandler) Delete(c *gin.Context) {
+	existing, ok := h.findMilestone(c)
+	if !ok {
+		return
+	}
+
+	if err := h.milestoneService.Delete(existing.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Milestone deleted"})
+}
+
+func (h *MilestoneHandler) GetProgress(c *gin.Context) {
+	milestone, ok := h.findMilestone(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.milestoneService.GetProgress(milestone.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetReleaseNotes returns the generated release notes as a Markdown document
+func (h *MilestoneHandler) GetReleaseNotes(c *gin.Context) {
+	milestone, ok := h.findMilestone(c)
+	if !ok {
+		return
+	}
+
+	notes, err := h.milestoneService.GetReleaseNotes(milestone.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("download") == "true" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="release-notes-%d.md"`, milestone.ID))
+	}
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(notes))
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OffboardingHandler struct {
+	offboardingService *services.OffboardingService
+	permissionService  *services.PermissionService
+}
+
+func NewOffboardingHandler(offboardingService *services.OffboardingService, permissionService *services.PermissionService) *OffboardingHandler {
+	return &OffboardingHandler{
+		offboardingService: offboardingService,
+		permissionService:  permissionService,
+	}
+}
+
+// requireManager reads the :id and :userId params and checks the caller
+// manages the team
+func (h *OffboardingHandler) requireManager(c *gin.Context) (uint, uint, bool) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	userID, _ := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if ok, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), uint(teamID), string(models.RoleManager)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can offboard members"})
+		return 0, 0, false
+	}
+	return uint(teamID), uint(userID), true
+}
+
+// Preview lists the open work the member would hand over
+func (h *OffboardingHandler) Preview(c *gin.Context) {
+	teamID, userID, ok := h.requireManager(c)
+	if !ok {
+		return
+	}
+	preview, err := h.offboardingService.Preview(teamID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// Offboard hands over the member's work and removes them from the team
+func (h *OffboardingHandler) Offboard(c *gin.Context) {
+	teamID, userID, ok := h.requireManager(c)
+	if !ok {
+		return
+	}
+
+	var req services.OffboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.offboardingService.Offboard(teamID, userID, &req, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member offboarded", "result": result})
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService     *services.ReportService
+	rateService       *services.RateService
+	permissionService *services.PermissionService
+}
+
+func NewReportHandler(
+	reportService *services.ReportService,
+	rateService *services.RateService,
+	permissionService *services.PermissionService,
+) *ReportHandler {
+	return &ReportHandler{
+		reportService:     reportService,
+		rateService:       rateService,
+		permissionService: permissionService,
+	}
+}
+
+// requireManager only lets team managers through, since reports and rates
+// show what people cost
+func (h *ReportHandler) requireManager(c *gin.Context) ([]uint, bool) {
+	teamIDs, err := h.permissionService.ManagedTeamIDs(middleware.GetUserID(c))
+	if err != nil || len(teamIDs) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can access time reports and rates"})
+		return nil, false
+	}
+	return teamIDs, true
+}
+
+// TimeReport aggregates logged time and cost over the teams the caller
+// manages, or one of them with ?team_id. ?format=csv|xlsx streams a download.
+func (h *ReportHandler) TimeReport(c *gin.Context) {
+	teamIDs, ok := h.requireManager(c)
+	if !ok {
+		return
+	}
+	if teamID := parseOptionalID(c.Query("team_id")); teamID != nil {
+		hasAccess, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), *teamID, string(models.RoleManager))
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can access time reports and rates"})
+			return
+		}
+		teamIDs = []uint{*teamID}
+	}
+
+	req := &services.TimeReportRequest{
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+		GroupBy:   c.Query("group_by"),
+		Period:    c.Query("period"),
+		UserID:    parseOptionalID(c.Query("user_id")),
+		ProjectID: parseOptionalID(c.Query("project_id")),
+		LabelID:   parseOptionalID(c.Query("label_id")),
+	}
+	if value := c.Query("billable"); value != "" {
+		billable, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "billable must be true or false"})
+			return
+		}
+		req.Billable = &billable
+	}
+
+	query, err := services.NewTimeReportQuery(req, teamIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := services.ReportFormat(c.DefaultQuery("format", string(services.ReportJSON)))
+	var contentType string
+	switch format {
+	case services.ReportJSON:
+		report, err := h.reportService.GetTimeReport(query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+		return
+	case services.ReportCSV:
+		contentType = "text/csv; charset=utf-8"
+	case services.ReportXLSX:
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, csv or xlsx"})
+		return
+	}
+
+	filename := fmt.Sprintf("time-report-%s-%s.%s", req.From, req.To, format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	// The status is already sent, so a failure can only cut the download short
+	if err := h.reportService.StreamTimeReport(c.Writer, query, format); err != nil {
+		log.Printf("Time report export failed: %v", err)
+	}
+}
+
+func (h *ReportHandler) ListRates(c *gin.Context) {
+	if _, ok := h.requireManager(c); !ok {
+		return
+	}
+	rates, err := h.rateService.GetByOrganization(middleware.GetOrganizationID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *ReportHandler) CreateRate(c *gin.Context) {
+	if _, ok := h.requireManager(c); !ok {
+		return
+	}
+
+	var req services.RateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rate, err := h.rateService.Create(&req, middleware.GetOrganizationID(c), middleware.GetUserID(c))
+	if errors.Is(err, services.ErrRateNotAllowed) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rate)
+}
+
+func (h *ReportHandler) DeleteRate(c *gin.Context) {
+	if _, ok := h.requireManager(c); !ok {
+		return
+	}
+
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	rate, err := h.rateService.GetByID(uint(id))
+	if err != nil || rate.OrganizationID != middleware.GetOrganizationID(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rate not found"})
+		return
+	}
+	if err := h.rateService.CheckWriter(rate.UserID, rate.OrganizationID, middleware.GetUserID(c)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrRateNotAllowed) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.rateService.Delete(rate.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Rate deleted"})
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoadmapHandler struct {
+	roadmapService    *services.RoadmapService
+	permissionService *services.PermissionService
+}
+
+func NewRoadmapHandler(roadmapService *services.RoadmapService, permissionService *services.PermissionService) *RoadmapHandler {
+	return &RoadmapHandler{
+		roadmapService:    roadmapService,
+		permissionService: permissionService,
+	}
+}
+
+// Get returns the timeline of a team (?team_id=) or a project (?project_id=)
+// with critical path and schedule conflicts
+func (h *RoadmapHandler) Get(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	if projectIDStr := c.Query("project_id"); projectIDStr != "" {
+		projectID, _ := strconv.ParseUint(projectIDStr, 10, 32)
+		orgID := middleware.GetOrganizationID(c)
+		roadmap, err := h.roadmapService.GetProjectRoadmap(uint(projectID), orgID, userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, roadmap)
+		return
+	}
+
+	teamIDStr := c.Query("team_id")
+	if teamIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_id or project_id required"})
+		return
+	}
+
+	teamID, _ := strconv.ParseUint(teamIDStr, 10, 32)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleMember)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	roadmap, err := h.roadmapService.GetTeamRoadmap(uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, roadmap)
+}
+
+func (h *RoadmapHandler) ListDependencies(c *gin.Context) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	dependencies, err := h.roadmapService.GetDependencies(uint(issueID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dependencies)
+}
+
+func (h *RoadmapHandler) AddDependency(c *gin.Context) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req struct {
+		DependsOnIssueID uint `json:"depends_on_issue_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if err := h.roadmapService.AddDependency(uint(issueID), req.DependsOnIssueID, userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Dependency added"})
+}
+
+func (h *RoadmapHandler) RemoveDependency(c *gin.Context) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	dependsOnID, _ := strconv.ParseUint(c.Param("dependsOnId"), 10, 32)
+
+	if err := h.roadmapService.RemoveDependency(uint(issueID), uint(dependsOnID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dependency removed"})
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SavedViewHandler struct {
+	viewService       *services.SavedViewService
+	permissionService *services.PermissionService
+}
+
+func NewSavedViewHandler(viewService *services.SavedViewService, permissionService *services.PermissionService) *SavedViewHandler {
+	return &SavedViewHandler{
+		viewService:       viewService,
+		permissionService: permissionService,
+	}
+}
+
+// findView loads the view from the :id param. Private views are only visible
+// to their owner, shared views to members of their team.
+func (h *SavedViewHandler) findView(c *gin.Context) (*models.SavedView, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	view, err := h.viewService.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+		return nil, false
+	}
+
+	userID := middleware.GetUserID(c)
+	if view.Visibility == models.ViewPrivate && view.OwnerID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+		return nil, false
+	}
+	if ok, _ := h.permissionService.HasTeamAccess(userID, view.TeamID, string(models.RoleStakeholder)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return nil, false
+	}
+	return view, true
+}
+
+// canModify allows the owner to change a view; shared views can also be changed by team managers
+func (h *SavedViewHandler) canModify(c *gin.Context, view *models.SavedView) bool {
+	userID := middleware.GetUserID(c)
+	if view.OwnerID == userID {
+		return true
+	}
+	if view.Visibility == models.ViewTeam {
+		if ok, _ := h.permissionService.HasTeamAccess(userID, view.TeamID, string(models.RoleManager)); ok {
+			return true
+		}
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Only the view owner can change this view"})
+	return false
+}
+
+func (h *SavedViewHandler) List(c *gin.Context) {
+	teamIDStr := c.Query("team_id")
+	if teamIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_id required"})
+		return
+	}
+
+	teamID, _ := strconv.ParseUint(teamIDStr, 10, 32)
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleStakeholder)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	views, err := h.viewService.GetVisible(uint(teamID), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, views)
+}
+
+func (h *SavedViewHandler) Create(c *gin.Context) {
+	var req services.SavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, req.TeamID, string(models.RoleStakeholder)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	view, err := h.viewService.Create(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, view)
+}
+
+func (h *SavedViewHandler) GetByID(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *SavedViewHandler) Update(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok || !h.canModify(c, view) {
+		return
+	}
+
+	var req services.SavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.viewService.Update(view.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *SavedViewHandler) Delete(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok || !h.canModify(c, view) {
+		return
+	}
+
+	if err := h.viewService.Delete(view.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "View deleted"})
+}
+
+// Execute runs the saved query and returns the matching issues
+func (h *SavedViewHandler) Execute(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.viewService.Execute(view)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SavedViewHandler) SetDefault(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, view.TeamID, string(models.RoleManager)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only managers can set the default view"})
+		return
+	}
+
+	if err := h.viewService.SetDefault(view); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Default view updated"})
+}
+
+func (h *SavedViewHandler) ClearDefault(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, view.TeamID, string(models.RoleManager)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only managers can set the default view"})
+		return
+	}
+
+	if err := h.viewService.ClearDefault(view); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Default view cleared"})
+}
+
+// GetTeamDefault returns the team's default view
+func (h *SavedViewHandler) GetTeamDefault(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleStakeholder)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	view, err := h.viewService.GetTeamDefault(uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No default view configured"})
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
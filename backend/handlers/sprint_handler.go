@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SprintHandler struct {
+	sprintService     *services.SprintService
+	permissionService *services.PermissionService
+}
+
+func NewSprintHandler(sprintService *services.SprintService, permissionService *services.PermissionService) *SprintHandler {
+	return &SprintHandler{
+		sprintService:     sprintService,
+		permissionService: permissionService,
+	}
+}
+
+// requireSprintRole loads the sprint from the :id param and checks the caller's team role
+func (h *SprintHandler) requireSprintRole(c *gin.Context, role models.TeamRole) (*models.Sprint, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	sprint, err := h.sprintService.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sprint not found"})
+		return nil, false
+	}
+
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, sprint.TeamID, string(role)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return nil, false
+	}
+	return sprint, true
+}
+
+func (h *SprintHandler) List(c *gin.Context) {
+	teamIDStr := c.Query("team_id")
+	if teamIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_id required"})
+		return
+	}
+
+	teamID, _ := strconv.ParseUint(teamIDStr, 10, 32)
+	sprints, err := h.sprintService.GetByTeam(uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sprints)
+}
+
+func (h *SprintHandler) Create(c *gin.Context) {
+	var req services.SprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, req.TeamID, string(models.RoleAssistant)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	sprint, err := h.sprintService.Create(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sprint)
+}
+
+func (h *SprintHandler) GetByID(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	sprint, err := h.sprintService.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sprint not found"})
+		return
+	}
+	c.JSON(http.StatusOK, sprint)
+}
+
+func (h *SprintHandler) Update(c *gin.Context) {
+	sprint, ok := h.requireSprintRole(c, models.RoleAssistant)
+	if !ok {
+		return
+	}
+
+	var req services.SprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.sprintService.Update(sprint.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *SprintHandler) Delete(c *gin.Context) {
+	sprint, ok := h.requireSprintRole(c, models.RoleAssistant)
+	if !ok {
+		return
+	}
+
+	if err := h.sprintService.Delete(sprint.ID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sprint deleted"})
+}
+
+func (h *SprintHandler) Start(c *gin.Context) {
+	sprint, ok := h.requireSprintRole(c, models.RoleAssistant)
+	if !ok {
+		return
+	}
+
+	started, err := h.sprintService.Start(sprint.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, started)
+}
+
+func (h *SprintHandler) Close(c *gin.Context) {
+	sprint, ok := h.requireSprintRole(c, models.RoleAssistant)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		RolloverSprintID *uint `json:"rollover_sprint_id"`
+	}
+	// Body is optional, but must be valid when given
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	result, err := h.sprintService.Close(sprint.ID, userID, req.RolloverSprintID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SprintHandler) AddIssue(c *gin.Context) {
+	sprint, ok := h.requireSprintRole(c, models.RoleMember)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		IssueID uint `json:"issue_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if err := h.sprintService.AddIssue(sprint.ID, req.IssueID, userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Issue added to sprint"})
+}
+
+func (h *SprintHandler) RemoveIssue(c *gin.Context) {
+	sprint, ok := h.requireSprintRole(c, models.RoleMember)
+	if !ok {
+		return
+	}
+
+	issueID, _ := strconv.ParseUint(c.Param("issueId"), 10, 32)
+	userID := middleware.GetUserID(c)
+	if err := h.sprintService.RemoveIssue(sprint.ID, uint(issueID), userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Issue removed from sprint"})
+}
+
+func (h *SprintHandler) GetScopeChanges(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	changes, err := h.sprintService.GetScopeChanges(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+// GetBurndown returns daily burndown and burnup series for the sprint
+func (h *SprintHandler) GetBurndown(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	burndown, err := h.sprintService.GetBurndown(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, burndown)
+}
@@ -40,6 +40,13 @@ func (h *StatusHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if status.Category == "" {
+		status.Category = models.CategoryInProgress
+		if status.IsFinal {
+			status.Category = models.CategoryDone
+		}
+	}
+
 	if err := h.statusRepo.Create(&status); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -57,6 +64,12 @@ func (h *StatusHandler) Update(c *gin.Context) {
 	}
 
 	status.ID = uint(id)
+	if status.Category == "" {
+		status.Category = models.CategoryInProgress
+		if status.IsFinal {
+			status.Category = models.CategoryDone
+		}
+	}
 	if err := h.statusRepo.Update(&status); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimerHandler struct {
+	timerService      *services.TimerService
+	issueService      *services.IssueService
+	permissionService *services.PermissionService
+}
+
+func NewTimerHandler(
+	timerService *services.TimerService,
+	issueService *services.IssueService,
+	permissionService *services.PermissionService,
+) *TimerHandler {
+	return &TimerHandler{
+		timerService:      timerService,
+		issueService:      issueService,
+		permissionService: permissionService,
+	}
+}
+
+// findTimer loads one of the caller's own timers from the :id param
+func (h *TimerHandler) findTimer(c *gin.Context) (*models.WorkTimer, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	timer, err := h.timerService.GetByID(uint(id))
+	if err != nil || timer.UserID != middleware.GetUserID(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Timer not found"})
+		return nil, false
+	}
+	return timer, true
+}
+
+// List returns the caller's running and paused timers
+func (h *TimerHandler) List(c *gin.Context) {
+	timers, err := h.timerService.GetForUser(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, timers)
+}
+
+// Start starts a timer on an issue of one of the caller's teams
+func (h *TimerHandler) Start(c *gin.Context) {
+	var input struct {
+		IssueID  uint   `json:"issue_id" binding:"required"`
+		Notes    string `json:"notes"`
+		Billable *bool  `json:"billable"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	issue, err := h.issueService.GetByID(input.IssueID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return
+	}
+	hasAccess, _ := h.permissionService.HasTeamAccess(userID, issue.TeamID, string(models.RoleMember))
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to log work on this issue"})
+		return
+	}
+
+	billable := input.Billable == nil || *input.Billable
+	timer, err := h.timerService.Start(userID, issue.ID, input.Notes, billable)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrTimerAlreadyRunning) || errors.Is(err, services.ErrWeekLocked) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, timer)
+}
+
+func (h *TimerHandler) Pause(c *gin.Context) {
+	timer, ok := h.findTimer(c)
+	if !ok {
+		return
+	}
+	if err := h.timerService.Pause(timer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, timer)
+}
+
+func (h *TimerHandler) Resume(c *gin.Context) {
+	timer, ok := h.findTimer(c)
+	if !ok {
+		return
+	}
+	if err := h.timerService.Resume(timer); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrTimerAlreadyRunning) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, timer)
+}
+
+// Stop ends the timer and returns the work log it produced
+func (h *TimerHandler) Stop(c *gin.Context) {
+	timer, ok := h.findTimer(c)
+	if !ok {
+		return
+	}
+	workLog, err := h.timerService.Stop(timer)
+	if errors.Is(err, services.ErrDailyWorkLimit) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, workLog)
+}
+
+// Discard deletes a timer without logging work
+func (h *TimerHandler) Discard(c *gin.Context) {
+	timer, ok := h.findTimer(c)
+	if !ok {
+		return
+	}
+	if err := h.timerService.Discard(timer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Timer discarded"})
+}
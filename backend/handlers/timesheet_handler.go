@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimesheetHandler struct {
+	timesheetService  *services.TimesheetService
+	permissionService *services.PermissionService
+}
+
+func NewTimesheetHandler(timesheetService *services.TimesheetService, permissionService *services.PermissionService) *TimesheetHandler {
+	return &TimesheetHandler{
+		timesheetService:  timesheetService,
+		permissionService: permissionService,
+	}
+}
+
+// GetWeek returns a weekly timesheet (?week=YYYY-MM-DD, any day of the week).
+// Managers can view their team members' timesheets with ?user_id.
+func (h *TimesheetHandler) GetWeek(c *gin.Context) {
+	callerID := middleware.GetUserID(c)
+	userID := callerID
+	if value := c.Query("user_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return
+		}
+		userID = uint(id)
+	}
+	if userID != callerID && !h.timesheetService.CanReview(callerID, userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can view other users' timesheets"})
+		return
+	}
+
+	start, err := h.timesheetService.ParseWeek(c.Query("week"), userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	week, err := h.timesheetService.GetWeek(userID, start)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, week)
+}
+
+// Submit hands in the caller's week for approval
+func (h *TimesheetHandler) Submit(c *gin.Context) {
+	var input struct {
+		Week string `json:"week"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	start, err := h.timesheetService.ParseWeek(input.Week, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	week, err := h.timesheetService.Submit(userID, start)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, week)
+}
+
+// findReviewable loads the timesheet from the :id param if the caller can review it
+func (h *TimesheetHandler) findReviewable(c *gin.Context) (*models.Timesheet, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	timesheet, err := h.timesheetService.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Timesheet not found"})
+		return nil, false
+	}
+
+	userID := middleware.GetUserID(c)
+	if timesheet.UserID != userID && !h.timesheetService.CanReview(userID, timesheet.UserID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Timesheet not found"})
+		return nil, false
+	}
+	return timesheet, true
+}
+
+func (h *TimesheetHandler) GetByID(c *gin.Context) {
+	timesheet, ok := h.findReviewable(c)
+	if !ok {
+		return
+	}
+	week, err := h.timesheetService.GetWeek(timesheet.UserID, timesheet.WeekStart)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, week)
+}
+
+func (h *TimesheetHandler) review(c *gin.Context, status models.TimesheetStatus) {
+	timesheet, ok := h.findReviewable(c)
+	if !ok {
+		return
+	}
+	userID := middleware.GetUserID(c)
+	if !h.timesheetService.CanReview(userID, timesheet.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can review timesheets"})
+		return
+	}
+
+	var req services.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.timesheetService.Review(timesheet, userID, status, req.Comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reviewed, _ := h.timesheetService.GetByID(timesheet.ID)
+	c.JSON(http.StatusOK, reviewed)
+}
+
+// Approve approves a submitted timesheet; its work logs stay locked
+func (h *TimesheetHandler) Approve(c *gin.Context) {
+	h.review(c, models.TimesheetApproved)
+}
+
+// Reject sends a submitted timesheet back with a comment and unlocks it
+func (h *TimesheetHandler) Reject(c *gin.Context) {
+	h.review(c, models.TimesheetRejected)
+}
+
+// ListForTeam lists a team's timesheets for its managers
+// (?status=submitted|approved|rejected, ?from and ?to as YYYY-MM-DD)
+func (h *TimesheetHandler) ListForTeam(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	userID := middleware.GetUserID(c)
+	hasAccess, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleManager))
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only team managers can list timesheets"})
+		return
+	}
+
+	status := models.TimesheetStatus(c.DefaultQuery("status", string(models.TimesheetSubmitted)))
+	switch status {
+	case models.TimesheetSubmitted, models.TimesheetApproved, models.TimesheetRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be submitted, approved or rejected"})
+		return
+	}
+
+	// Defaults to the last twelve weeks
+	to := time.Now()
+	from := to.AddDate(0, 0, -7*12)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return
+		}
+		to = parsed
+	}
+
+	timesheets, err := h.timesheetService.GetForTeam(uint(teamID), status, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, timesheets)
+}
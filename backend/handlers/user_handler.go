@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"task-management/middleware"
 	"task-management/models"
+	"task-management/services"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -89,6 +91,78 @@ func (h *UserHandler) GetMyPermissions(c *gin.Context) {
 	c.JSON(http.StatusOK, permissions)
 }
 
+type WorkingHours struct {
+	Timezone     string `json:"timezone"`
+	WorkdayStart string `json:"workday_start"`
+	WorkdayEnd   string `json:"workday_end"`
+	// WeeklyCapacityMinutes overrides the capacity derived from the working hours
+	WeeklyCapacityMinutes *int `json:"weekly_capacity_minutes"`
+}
+
+func (h *UserHandler) GetMyWorkingHours(c *gin.Context) {
+	var user models.User
+	if err := h.db.First(&user, middleware.GetUserID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, WorkingHours{
+		Timezone:              user.Timezone,
+		WorkdayStart:          user.WorkdayStart,
+		WorkdayEnd:            user.WorkdayEnd,
+		WeeklyCapacityMinutes: user.WeeklyCapacity,
+	})
+}
+
+// UpdateMyWorkingHours sets the caller's working hours (HH:MM in their timezone)
+// and optionally the timezone itself
+func (h *UserHandler) UpdateMyWorkingHours(c *gin.Context) {
+	var input WorkingHours
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.ValidateWorkingHours(input.WorkdayStart, input.WorkdayEnd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.WeeklyCapacityMinutes != nil && (*input.WeeklyCapacityMinutes <= 0 || *input.WeeklyCapacityMinutes > 7*24*60) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "weekly_capacity_minutes must be between 1 and 10080"})
+		return
+	}
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+	}
+
+	var user models.User
+	if err := h.db.First(&user, middleware.GetUserID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	updates := map[string]interface{}{
+		"workday_start":           input.WorkdayStart,
+		"workday_end":             input.WorkdayEnd,
+		"weekly_capacity_minutes": input.WeeklyCapacityMinutes,
+	}
+	if input.Timezone != "" {
+		updates["timezone"] = input.Timezone
+	}
+	if err := h.db.Model(&user).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.db.First(&user, user.ID)
+
+	c.JSON(http.StatusOK, WorkingHours{
+		Timezone:              user.Timezone,
+		WorkdayStart:          user.WorkdayStart,
+		WorkdayEnd:            user.WorkdayEnd,
+		WeeklyCapacityMinutes: user.WeeklyCapacity,
+	})
+}
+
 func (h *UserHandler) GetTeamRole(c *gin.Context, teamID uint) *models.TeamMember {
 	userID := middleware.GetUserID(c)
 
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkloadHandler struct {
+	capacityService   *services.CapacityService
+	permissionService *services.PermissionService
+}
+
+func NewWorkloadHandler(capacityService *services.CapacityService, permissionService *services.PermissionService) *WorkloadHandler {
+	return &WorkloadHandler{
+		capacityService:   capacityService,
+		permissionService: permissionService,
+	}
+}
+
+// GetTeamWorkload returns the planned load of each team member against their
+// capacity (?from=&to=&granularity=day|week). Defaults to the next four weeks.
+func (h *WorkloadHandler) GetTeamWorkload(c *gin.Context) {
+	teamID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	userID := middleware.GetUserID(c)
+	if ok, _ := h.permissionService.HasTeamAccess(userID, uint(teamID), string(models.RoleMember)); !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, 27)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return
+		}
+		to = parsed
+	}
+
+	workload, err := h.capacityService.GetTeamWorkload(uint(teamID), from, to, c.DefaultQuery("granularity", "day"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, workload)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"task-management/middleware"
+	"task-management/models"
+	"task-management/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkLogHandler struct {
+	workLogService    *services.WorkLogService
+	issueService      *services.IssueService
+	permissionService *services.PermissionService
+}
+
+func NewWorkLogHandler(
+	workLogService *services.WorkLogService,
+	issueService *services.IssueService,
+	permissionService *services.PermissionService,
+) *WorkLogHandler {
+	return &WorkLogHandler{
+		workLogService:    workLogService,
+		issueService:      issueService,
+		permissionService: permissionService,
+	}
+}
+
+// findWorkLog loads the work log from the :logId param, scoped to the :id issue
+func (h *WorkLogHandler) findWorkLog(c *gin.Context) (*models.IssueWorkLog, bool) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	logID, err := strconv.ParseUint(c.Param("logId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid work log ID"})
+		return nil, false
+	}
+
+	log, err := h.workLogService.GetByID(uint(logID))
+	if err != nil || log.IssueID != uint(issueID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Work log not found"})
+		return nil, false
+	}
+	return log, true
+}
+
+// canModify allows the author of a work log and managers of the issue's team
+func (h *WorkLogHandler) canModify(c *gin.Context, log *models.IssueWorkLog) bool {
+	userID := middleware.GetUserID(c)
+	if log.UserID == userID {
+		return true
+	}
+	issue, err := h.issueService.GetByID(log.IssueID)
+	if err != nil {
+		return false
+	}
+	ok, _ := h.permissionService.HasTeamAccess(userID, issue.TeamID, string(models.RoleManager))
+	return ok
+}
+
+// requireIssueAccess loads the :id issue, which the caller must be a member
+// of the team of
+func (h *WorkLogHandler) requireIssueAccess(c *gin.Context) (*models.Issue, bool) {
+	issueID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	issue, err := h.issueService.GetByID(uint(issueID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		return nil, false
+	}
+	hasAccess, _ := h.permissionService.HasTeamAccess(middleware.GetUserID(c), issue.TeamID, string(models.RoleMember))
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view or log work on this issue"})
+		return nil, false
+	}
+	return issue, true
+}
+
+// workLogErrorStatus reports locked weeks as conflicts and other errors as bad requests
+func workLogErrorStatus(err error) int {
+	if errors.Is(err, services.ErrWeekLocked) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+func (h *WorkLogHandler) List(c *gin.Context) {
+	issue, ok := h.requireIssueAccess(c)
+	if !ok {
+		return
+	}
+	logs, err := h.workLogService.GetByIssue(issue.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, logs)
+}
+
+// Create logs work by the caller on an issue
+func (h *WorkLogHandler) Create(c *gin.Context) {
+	issue, ok := h.requireIssueAccess(c)
+	if !ok {
+		return
+	}
+
+	var req services.WorkLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	log, err := h.workLogService.Create(issue.ID, userID, &req)
+	if err != nil {
+		c.JSON(workLogErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+func (h *WorkLogHandler) Update(c *gin.Context) {
+	log, ok := h.findWorkLog(c)
+	if !ok {
+		return
+	}
+	if !h.canModify(c, log) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author or a team manager can edit this work log"})
+		return
+	}
+
+	var req services.WorkLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.workLogService.Update(log, middleware.GetUserID(c), &req); err != nil {
+		c.JSON(workLogErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, log)
+}
+
+func (h *WorkLogHandler) Delete(c *gin.Context) {
+	log, ok := h.findWorkLog(c)
+	if !ok {
+		return
+	}
+	if !h.canModify(c, log) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author or a team manager can delete this work log"})
+		return
+	}
+
+	if err := h.workLogService.Delete(log, middleware.GetUserID(c)); err != nil {
+		c.JSON(workLogErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Work log deleted"})
+}
@@ -8,6 +8,7 @@ import (
 	"task-management/middleware"
 	"task-management/repositories"
 	"task-management/services"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -36,26 +37,89 @@ func main() {
 	attachmentRepo := repositories.NewAttachmentRepository(db)
 	commentRepo := repositories.NewCommentRepository(db)
 	meetingRepo := repositories.NewMeetingRepository(db)
+	wipLimitRepo := repositories.NewWIPLimitRepository(db)
+	sprintRepo := repositories.NewSprintRepository(db)
+	labelRepo := repositories.NewLabelRepository(db)
+	milestoneRepo := repositories.NewMilestoneRepository(db)
+	projectRepo := repositories.NewProjectRepository(db)
+	dependencyRepo := repositories.NewDependencyRepository(db)
+	savedViewRepo := repositories.NewSavedViewRepository(db)
+	mentionRepo := repositories.NewMentionRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	timerRepo := repositories.NewTimerRepository(db)
+	workLogRepo := repositories.NewWorkLogRepository(db)
+	timesheetRepo := repositories.NewTimesheetRepository(db)
+	rateRepo := repositories.NewRateRepository(db)
+	leaveRepo := repositories.NewLeaveRepository(db)
+	holidayRepo := repositories.NewHolidayRepository(db)
+	offboardingRepo := repositories.NewOffboardingRepository(db)
+	calendarFeedRepo := repositories.NewCalendarFeedRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo)
 	orgService := services.NewOrganizationService(orgRepo)
 	teamService := services.NewTeamService(teamRepo, userRepo)
-	issueService := services.NewIssueService(issueRepo, statusRepo)
-	assignmentService := services.NewAssignmentService(assignmentRepo, issueRepo, userRepo)
-	calendarService := services.NewCalendarService(calendarRepo)
+	renderService := services.NewRenderService(issueRepo, userRepo, teamRepo)
+	holidayService := services.NewHolidayService(holidayRepo, orgRepo, teamRepo)
+	issueService := services.NewIssueService(issueRepo, statusRepo, wipLimitRepo, sprintRepo, teamRepo, milestoneRepo, projectRepo, labelRepo, renderService, holidayService)
+	calendarService := services.NewCalendarService(calendarRepo, teamRepo)
+	calendarFeedService := services.NewCalendarFeedService(calendarFeedRepo, calendarRepo, meetingRepo, userRepo)
 	permissionService := services.NewPermissionService(teamRepo)
+	boardService := services.NewBoardService(issueRepo, statusRepo, teamRepo, wipLimitRepo)
+	sprintService := services.NewSprintService(sprintRepo, issueRepo, statusRepo, teamRepo)
+	milestoneService := services.NewMilestoneService(milestoneRepo, teamRepo)
+	projectService := services.NewProjectService(projectRepo, teamRepo, userRepo)
+	roadmapService := services.NewRoadmapService(issueRepo, projectRepo, teamRepo, dependencyRepo)
+	savedViewService := services.NewSavedViewService(savedViewRepo, issueService)
+	notificationService := services.NewNotificationService(notificationRepo)
+	mentionService := services.NewMentionService(mentionRepo, issueRepo, userRepo, teamRepo, notificationService)
+	leaveService := services.NewLeaveService(leaveRepo, teamRepo, userRepo, notificationService, holidayService)
+	capacityService := services.NewCapacityService(assignmentRepo, userRepo, teamRepo, leaveService, holidayService)
+	assignmentService := services.NewAssignmentService(assignmentRepo, issueRepo, userRepo, capacityService)
+	meetingService := services.NewMeetingService(meetingRepo, userRepo)
+	schedulingService := services.NewSchedulingService(meetingRepo, userRepo, teamRepo, leaveService, holidayService)
+	meetingImportService := services.NewMeetingImportService(meetingRepo, userRepo, teamRepo)
+	offboardingService := services.NewOffboardingService(offboardingRepo, teamRepo, assignmentRepo, timerRepo, meetingRepo, userRepo)
+	autoAssignService := services.NewAutoAssignService(issueRepo, teamRepo, assignmentRepo, labelRepo, leaveService, holidayService, assignmentService)
+	timesheetService := services.NewTimesheetService(timesheetRepo, workLogRepo, timerRepo, teamRepo, userRepo, notificationService)
+	workLogService := services.NewWorkLogService(workLogRepo, timesheetService)
+	timerService := services.NewTimerService(timerRepo, workLogRepo, userRepo, notificationService, timesheetService, config.GetConfig().AllowConcurrentTimers)
+	rateService := services.NewRateService(rateRepo, userRepo, teamRepo)
+	reportService := services.NewReportService(reportRepo)
+	commentService := services.NewCommentService(commentRepo, issueRepo, config.GetConfig().CommentEditWindow, renderService)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	orgHandler := handlers.NewOrganizationHandler(orgService, permissionService)
 	teamHandler := handlers.NewTeamHandler(teamService, permissionService)
-	issueHandler := handlers.NewIssueHandler(issueService, assignmentService, permissionService)
+	issueHandler := handlers.NewIssueHandler(issueService, assignmentService, permissionService, mentionService, autoAssignService)
 	calendarHandler := handlers.NewCalendarHandler(calendarService, permissionService)
 	statusHandler := handlers.NewStatusHandler(statusRepo, permissionService)
-	commentHandler := handlers.NewCommentHandler(commentRepo)
-	meetingHandler := handlers.NewMeetingHandler(meetingRepo)
+	commentHandler := handlers.NewCommentHandler(commentService, mentionService, permissionService)
+	meetingHandler := handlers.NewMeetingHandler(meetingRepo, meetingService, schedulingService, meetingImportService, permissionService)
 	analyticsHandler := handlers.NewAnalyticsHandler(db)
+	boardHandler := handlers.NewBoardHandler(boardService, permissionService)
+	sprintHandler := handlers.NewSprintHandler(sprintService, permissionService)
+	labelHandler := handlers.NewLabelHandler(labelRepo, issueService, permissionService)
+	milestoneHandler := handlers.NewMilestoneHandler(milestoneService)
+	projectHandler := handlers.NewProjectHandler(projectService)
+	roadmapHandler := handlers.NewRoadmapHandler(roadmapService, permissionService)
+	savedViewHandler := handlers.NewSavedViewHandler(savedViewService, permissionService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	workLogHandler := handlers.NewWorkLogHandler(workLogService, issueService, permissionService)
+	timesheetHandler := handlers.NewTimesheetHandler(timesheetService, permissionService)
+	reportHandler := handlers.NewReportHandler(reportService, rateService, permissionService)
+	timerHandler := handlers.NewTimerHandler(timerService, issueService, permissionService)
+	workloadHandler := handlers.NewWorkloadHandler(capacityService, permissionService)
+	leaveHandler := handlers.NewLeaveHandler(leaveService, permissionService)
+	holidayHandler := handlers.NewHolidayHandler(holidayService, permissionService)
+	autoAssignHandler := handlers.NewAutoAssignHandler(autoAssignService, issueService, permissionService)
+	offboardingHandler := handlers.NewOffboardingHandler(offboardingService, permissionService)
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(calendarFeedService)
+
+	// Stop timers left running overnight
+	timerService.StartAutoStop(5 * time.Minute)
 
 	// Initialize storage service (optional - for file attachments)
 	var attachmentHandler *handlers.AttachmentHandler
@@ -82,6 +146,9 @@ func main() {
 		auth.POST("/logout", authHandler.Logout)
 	}
 
+	// Calendar subscriptions authenticate with the token in the URL
+	router.GET("/ics/:token", calendarFeedHandler.Serve)
+
 	// Protected routes
 	api := router.Group("/api")
 	api.Use(middleware.AuthMiddleware())
@@ -89,6 +156,10 @@ func main() {
 	// User routes
 	userHandler := handlers.NewUserHandler(db)
 	api.GET("/users/me/permissions", userHandler.GetMyPermissions)
+	api.GET("/users/me/working-hours", userHandler.GetMyWorkingHours)
+	api.PUT("/users/me/working-hours", userHandler.UpdateMyWorkingHours)
+	api.GET("/users/me/skills", labelHandler.GetMySkills)
+	api.PUT("/users/me/skills", labelHandler.SetMySkills)
 
 	// Change password (needs auth)
 	api.POST("/auth/change-password", authHandler.ChangePassword)
@@ -103,6 +174,19 @@ func main() {
 			orgs.DELETE("/:id", orgHandler.Delete)
 		}
 
+		// Holiday calendars
+		holidays := api.Group("/holiday-calendars")
+		{
+			holidays.GET("", holidayHandler.List)
+			holidays.POST("", holidayHandler.Create)
+			holidays.GET("/:id", holidayHandler.GetByID)
+			holidays.DELETE("/:id", holidayHandler.Delete)
+			holidays.POST("/:id/holidays", holidayHandler.AddHoliday)
+			holidays.DELETE("/:id/holidays/:holidayId", holidayHandler.DeleteHoliday)
+			holidays.POST("/:id/import", holidayHandler.Import)
+		}
+		api.GET("/business-days/add", holidayHandler.AddBusinessDays)
+
 		// Teams
 		teams := api.Group("/teams")
 		{
@@ -114,6 +198,18 @@ func main() {
 			teams.GET("/:id/members", teamHandler.GetMembers)
 			teams.POST("/:id/members", teamHandler.AddMember)
 			teams.DELETE("/:id/members/:userId", teamHandler.RemoveMember)
+			teams.GET("/:id/members/:userId/offboarding", offboardingHandler.Preview)
+			teams.POST("/:id/members/:userId/offboard", offboardingHandler.Offboard)
+			teams.GET("/:id/board", boardHandler.GetBoard)
+			teams.GET("/:id/wip-limits", boardHandler.GetWIPLimits)
+			teams.PUT("/:id/wip-limits", boardHandler.SetWIPLimit)
+			teams.DELETE("/:id/wip-limits/:statusId", boardHandler.DeleteWIPLimit)
+			teams.GET("/:id/default-view", savedViewHandler.GetTeamDefault)
+			teams.GET("/:id/timesheets", timesheetHandler.ListForTeam)
+			teams.GET("/:id/workload", workloadHandler.GetTeamWorkload)
+			teams.GET("/:id/absences", leaveHandler.ListForTeam)
+			teams.PUT("/:id/assignment-settings", autoAssignHandler.UpdateSettings)
+			teams.PUT("/:id/meeting-settings", meetingHandler.UpdateSettings)
 		}
 
 		// Issue Statuses
@@ -125,6 +221,15 @@ func main() {
 			statuses.DELETE("/:id", statusHandler.Delete)
 		}
 
+		// Labels
+		labels := api.Group("/labels")
+		{
+			labels.GET("", labelHandler.List)
+			labels.POST("", labelHandler.Create)
+			labels.PUT("/:id", labelHandler.Update)
+			labels.DELETE("/:id", labelHandler.Delete)
+		}
+
 		// Issues
 		issues := api.Group("/issues")
 		{
@@ -134,11 +239,24 @@ func main() {
 			issues.PUT("/:id", issueHandler.Update)
 			issues.DELETE("/:id", issueHandler.Delete)
 			issues.POST("/:id/assign", issueHandler.Assign)
+			issues.POST("/:id/auto-assign", autoAssignHandler.AutoAssign)
+			issues.GET("/:id/assignments", issueHandler.ListAssignments)
+			issues.DELETE("/:id/assignments/:assignmentId", issueHandler.Unassign)
+			issues.POST("/:id/assignments/:assignmentId/reassign", issueHandler.Reassign)
 			issues.POST("/:id/status", issueHandler.UpdateStatus)
+			issues.POST("/:id/move", issueHandler.Move)
+			issues.PUT("/:id/labels", labelHandler.SetIssueLabels)
+			issues.GET("/:id/dependencies", roadmapHandler.ListDependencies)
+			issues.POST("/:id/dependencies", roadmapHandler.AddDependency)
+			issues.DELETE("/:id/dependencies/:dependsOnId", roadmapHandler.RemoveDependency)
 			issues.POST("/:id/hold", issueHandler.Hold)
 			issues.POST("/:id/resume", issueHandler.Resume)
 			issues.GET("/:id/activities", issueHandler.GetActivities)
-			issues.POST("/:id/worklog", issueHandler.LogWork)
+			issues.GET("/:id/mentions", issueHandler.GetMentions)
+			issues.GET("/:id/worklog", workLogHandler.List)
+			issues.POST("/:id/worklog", workLogHandler.Create)
+			issues.PUT("/:id/worklog/:logId", workLogHandler.Update)
+			issues.DELETE("/:id/worklog/:logId", workLogHandler.Delete)
 
 			// Attachments (if storage service is configured)
 			if attachmentHandler != nil {
@@ -151,8 +269,121 @@ func main() {
 			issues.POST("/:id/comments", commentHandler.Create)
 			issues.PUT("/:id/comments/:commentId", commentHandler.Update)
 			issues.DELETE("/:id/comments/:commentId", commentHandler.Delete)
+			issues.POST("/:id/comments/:commentId/reactions", commentHandler.React)
+			issues.POST("/:id/comments/:commentId/resolve", commentHandler.Resolve)
+			issues.DELETE("/:id/comments/:commentId/resolve", commentHandler.Reopen)
+			issues.POST("/:id/comments/:commentId/moderate", commentHandler.Moderate)
+			issues.DELETE("/:id/comments/:commentId/moderate", commentHandler.Unhide)
+		}
+
+		// Comments (standalone routes)
+		api.GET("/comments/:id/revisions", commentHandler.GetRevisions)
+
+		// Sprints
+		sprints := api.Group("/sprints")
+		{
+			sprints.GET("", sprintHandler.List)
+			sprints.POST("", sprintHandler.Create)
+			sprints.GET("/:id", sprintHandler.GetByID)
+			sprints.PUT("/:id", sprintHandler.Update)
+			sprints.DELETE("/:id", sprintHandler.Delete)
+			sprints.POST("/:id/start", sprintHandler.Start)
+			sprints.POST("/:id/close", sprintHandler.Close)
+			sprints.POST("/:id/issues", sprintHandler.AddIssue)
+			sprints.DELETE("/:id/issues/:issueId", sprintHandler.RemoveIssue)
+			sprints.GET("/:id/scope-changes", sprintHandler.GetScopeChanges)
+			sprints.GET("/:id/burndown", sprintHandler.GetBurndown)
+		}
+
+		// Milestones
+		milestones := api.Group("/milestones")
+		{
+			milestones.GET("", milestoneHandler.List)
+			milestones.POST("", milestoneHandler.Create)
+			milestones.GET("/:id", milestoneHandler.GetByID)
+			milestones.PUT("/:id", milestoneHandler.Update)
+			milestones.DELETE("/:id", milestoneHandler.Delete)
+			milestones.GET("/:id/progress", milestoneHandler.GetProgress)
+			milestones.GET("/:id/release-notes", milestoneHandler.GetReleaseNotes)
+		}
+
+		// Projects
+		projects := api.Group("/projects")
+		{
+			projects.GET("", projectHandler.List)
+			projects.POST("", projectHandler.Create)
+			projects.GET("/:id", projectHandler.GetByID)
+			projects.PUT("/:id", projectHandler.Update)
+			projects.DELETE("/:id", projectHandler.Delete)
+			projects.GET("/:id/issues", projectHandler.GetIssues)
+			projects.GET("/:id/rollup", projectHandler.GetRollup)
+		}
+
+		// Saved views
+		views := api.Group("/views")
+		{
+			views.GET("", savedViewHandler.List)
+			views.POST("", savedViewHandler.Create)
+			views.GET("/:id", savedViewHandler.GetByID)
+			views.PUT("/:id", savedViewHandler.Update)
+			views.DELETE("/:id", savedViewHandler.Delete)
+			views.GET("/:id/issues", savedViewHandler.Execute)
+			views.PUT("/:id/default", savedViewHandler.SetDefault)
+			views.DELETE("/:id/default", savedViewHandler.ClearDefault)
+		}
+
+		// Notifications
+		notifications := api.Group("/notifications")
+		{
+			notifications.GET("", notificationHandler.List)
+			notifications.POST("/read-all", notificationHandler.MarkAllRead)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+		}
+
+		// Timers
+		timers := api.Group("/timers")
+		{
+			timers.GET("", timerHandler.List)
+			timers.POST("", timerHandler.Start)
+			timers.POST("/:id/pause", timerHandler.Pause)
+			timers.POST("/:id/resume", timerHandler.Resume)
+			timers.POST("/:id/stop", timerHandler.Stop)
+			timers.DELETE("/:id", timerHandler.Discard)
+		}
+
+		// Timesheets
+		timesheets := api.Group("/timesheets")
+		{
+			timesheets.GET("", timesheetHandler.GetWeek)
+			timesheets.POST("/submit", timesheetHandler.Submit)
+			timesheets.GET("/:id", timesheetHandler.GetByID)
+			timesheets.POST("/:id/approve", timesheetHandler.Approve)
+			timesheets.POST("/:id/reject", timesheetHandler.Reject)
 		}
 
+		// Leave
+		leave := api.Group("/leave")
+		{
+			leave.GET("", leaveHandler.List)
+			leave.POST("", leaveHandler.Create)
+			leave.GET("/:id", leaveHandler.GetByID)
+			leave.POST("/:id/approve", leaveHandler.Approve)
+			leave.POST("/:id/reject", leaveHandler.Reject)
+			leave.POST("/:id/cancel", leaveHandler.Cancel)
+		}
+
+		// Reports
+		api.GET("/reports/time", reportHandler.TimeReport)
+		rates := api.Group("/rates")
+		{
+			rates.GET("", reportHandler.ListRates)
+			rates.POST("", reportHandler.CreateRate)
+			rates.DELETE("/:id", reportHandler.DeleteRate)
+		}
+
+		// Roadmap
+		api.GET("/roadmap", roadmapHandler.Get)
+
 		// Attachments (standalone routes)
 		if attachmentHandler != nil {
 			attachments := api.Group("/attachments")
@@ -168,11 +399,21 @@ func main() {
 			calendar.GET("", calendarHandler.GetCalendar)
 		}
 
+		// Calendar feeds
+		feeds := api.Group("/calendar-feeds")
+		{
+			feeds.GET("", calendarFeedHandler.List)
+			feeds.POST("", calendarFeedHandler.Create)
+			feeds.DELETE("/:id", calendarFeedHandler.Revoke)
+		}
+
 		// Meetings
 		meetings := api.Group("/meetings")
 		{
 			meetings.GET("", meetingHandler.List)
 			meetings.POST("", meetingHandler.Create)
+			meetings.POST("/import", meetingHandler.Import)
+			meetings.POST("/free-slots", meetingHandler.FreeSlots)
 			meetings.GET("/:id", meetingHandler.GetByID)
 			meetings.PUT("/:id", meetingHandler.Update)
 			meetings.DELETE("/:id", meetingHandler.Delete)
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TeamWIPLimit caps how many issues a team may have in a single status.
+// Strict limits reject moves into a full column, others only warn.
+type TeamWIPLimit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TeamID    uint      `gorm:"not null" json:"team_id"`
+	StatusID  uint      `gorm:"not null" json:"status_id"`
+	MaxIssues int       `gorm:"not null" json:"max_issues"`
+	IsStrict  bool      `gorm:"default:false" json:"is_strict"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Status *IssueStatus `gorm:"foreignKey:StatusID" json:"status,omitempty"`
+}
+
+func (TeamWIPLimit) TableName() string {
+	return "team_wip_limits"
+}
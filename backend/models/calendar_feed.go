@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// CalendarFeed is a secret URL serving a user's calendar as iCalendar, for
+// calendar apps that cannot log in. Deleting it revokes the URL.
+type CalendarFeed struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	UserID         uint       `gorm:"not null" json:"user_id"`
+	Name           string     `gorm:"size:255;not null" json:"name"`
+	TokenHash      string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -2,18 +2,71 @@ package models
 
 import "time"
 
+type CommentModeration string
+
+const (
+	CommentHidden  CommentModeration = "hidden"
+	CommentRemoved CommentModeration = "removed"
+)
+
 type Comment struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	IssueID   uint      `gorm:"not null" json:"issue_id"`
-	UserID    uint      `gorm:"not null" json:"user_id"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	IssueID     uint       `gorm:"not null" json:"issue_id"`
+	UserID      uint       `gorm:"not null" json:"user_id"`
+	ParentID    *uint      `json:"parent_id,omitempty"`
+	Content     string     `gorm:"type:text;not null" json:"content"`
+	ContentHTML string     `gorm:"column:content_html;type:text" json:"content_html"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy  *uint      `json:"resolved_by,omitempty"`
+	EditedAt    *time.Time `json:"edited_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Moderation by a team manager leaves a tombstone in place of the content
+	Moderation       *CommentModeration `gorm:"type:comment_moderation" json:"moderation,omitempty"`
+	ModeratedBy      *uint              `json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time         `json:"moderated_at,omitempty"`
+	ModerationReason string             `gorm:"type:text" json:"moderation_reason,omitempty"`
 
 	// Relationships
-	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	User            User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	ResolvedByUser  *User     `gorm:"foreignKey:ResolvedBy" json:"resolved_by_user,omitempty"`
+	ModeratedByUser *User     `gorm:"foreignKey:ModeratedBy" json:"moderated_by_user,omitempty"`
+	Mentions        []Mention `gorm:"foreignKey:CommentID" json:"mentions,omitempty"`
+	Replies         []Comment `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
+
+	// Reactions is aggregated per emoji when comments are listed
+	Reactions []ReactionSummary `gorm:"-" json:"reactions"`
 }
 
 func (Comment) TableName() string {
 	return "issue_comments"
 }
+
+// CommentRevision keeps the content a comment had before an edit
+type CommentRevision struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CommentID uint      `gorm:"not null" json:"comment_id"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	EditedBy  uint      `gorm:"not null" json:"edited_by"`
+	EditedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"edited_at"`
+
+	// Relationships
+	Editor User `gorm:"foreignKey:EditedBy" json:"editor,omitempty"`
+}
+
+type CommentReaction struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CommentID uint      `gorm:"not null" json:"comment_id"`
+	UserID    uint      `gorm:"not null" json:"user_id"`
+	Emoji     string    `gorm:"size:32;not null" json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReactionSummary counts the reactions of one emoji on a comment
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	UserIDs     []uint `json:"user_ids"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
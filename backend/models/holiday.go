@@ -0,0 +1,46 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Weekdays lists days of the week, 0 being Sunday as in time.Weekday
+type Weekdays []int
+
+func (w Weekdays) Value() (driver.Value, error) {
+	if w == nil {
+		return "[]", nil
+	}
+	return json.Marshal(w)
+}
+
+func (w *Weekdays) Scan(value interface{}) error {
+	return scanJSON(value, w)
+}
+
+// HolidayCalendar is a named set of public holidays. Every calendar of an
+// organization applies to all of its members.
+type HolidayCalendar struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID uint      `gorm:"not null" json:"organization_id"`
+	Name           string    `gorm:"size:255;not null" json:"name"`
+	CreatedBy      uint      `gorm:"not null" json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Relationships
+	Holidays []Holiday `gorm:"foreignKey:CalendarID" json:"holidays,omitempty"`
+}
+
+// Holiday is a day off in a holiday calendar. UID identifies holidays
+// imported from an ICS file.
+type Holiday struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CalendarID uint      `gorm:"not null" json:"calendar_id"`
+	Date       time.Time `gorm:"type:date;not null" json:"date"`
+	Name       string    `gorm:"size:255;not null" json:"name"`
+	UID        string    `gorm:"size:255" json:"uid,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -16,17 +16,23 @@ const (
 )
 
 type Issue struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	TeamID      uint           `gorm:"not null" json:"team_id"`
-	StatusID    *uint          `json:"status_id,omitempty"`
-	Title       string         `gorm:"size:500;not null" json:"title"`
-	Description string         `gorm:"type:text" json:"description"`
-	Priority    IssuePriority  `gorm:"type:issue_priority;default:NORMAL" json:"priority"`
-	Deadline    *time.Time     `gorm:"type:date" json:"deadline,omitempty"`
-	CreatedBy   uint           `json:"created_by"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	TeamID          uint           `gorm:"not null" json:"team_id"`
+	StatusID        *uint          `json:"status_id,omitempty"`
+	Title           string         `gorm:"size:500;not null" json:"title"`
+	Description     string         `gorm:"type:text" json:"description"`
+	DescriptionHTML string         `gorm:"column:description_html;type:text" json:"description_html"`
+	Priority        IssuePriority  `gorm:"type:issue_priority;default:NORMAL" json:"priority"`
+	Deadline        *time.Time     `gorm:"type:date" json:"deadline,omitempty"`
+	Rank            string         `gorm:"size:255;not null;default:''" json:"rank"`
+	SprintID        *uint          `json:"sprint_id,omitempty"`
+	EstimatePoints  *int           `json:"estimate_points,omitempty"`
+	MilestoneID     *uint          `json:"milestone_id,omitempty"`
+	ProjectID       *uint          `json:"project_id,omitempty"`
+	CreatedBy       uint           `json:"created_by"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relationships
 	Team        Team              `gorm:"foreignKey:TeamID" json:"team,omitempty"`
@@ -36,16 +42,27 @@ type Issue struct {
 	WorkLogs    []IssueWorkLog    `gorm:"foreignKey:IssueID" json:"work_logs,omitempty"`
 	Activities  []IssueActivity   `gorm:"foreignKey:IssueID" json:"activities,omitempty"`
 	HoldReasons []IssueHoldReason `gorm:"foreignKey:IssueID" json:"hold_reasons,omitempty"`
+	Labels      []Label           `gorm:"many2many:issue_labels" json:"labels,omitempty"`
 }
 
+// StatusCategory groups organization-specific statuses into a common workflow stage
+type StatusCategory string
+
+const (
+	CategoryTodo       StatusCategory = "todo"
+	CategoryInProgress StatusCategory = "in_progress"
+	CategoryDone       StatusCategory = "done"
+)
+
 type IssueStatus struct {
-	ID             uint      `gorm:"primaryKey" json:"id"`
-	OrganizationID uint      `gorm:"not null" json:"organization_id"`
-	Name           string    `gorm:"size:100;not null" json:"name"`
-	Position       int       `gorm:"not null" json:"position"`
-	IsFinal        bool      `gorm:"default:false" json:"is_final"`
-	Color          string    `gorm:"size:7;default:#6B7280" json:"color"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	OrganizationID uint           `gorm:"not null" json:"organization_id"`
+	Name           string         `gorm:"size:100;not null" json:"name"`
+	Position       int            `gorm:"not null" json:"position"`
+	IsFinal        bool           `gorm:"default:false" json:"is_final"`
+	Category       StatusCategory `gorm:"type:status_category;default:in_progress" json:"category"`
+	Color          string         `gorm:"size:7;default:#6B7280" json:"color"`
+	CreatedAt      time.Time      `json:"created_at"`
 
 	// Relationships
 	Organization Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
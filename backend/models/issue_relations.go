@@ -4,20 +4,37 @@ import (
 	"time"
 )
 
+type AssignmentRole string
+
+const (
+	AssignmentOwner    AssignmentRole = "owner"
+	AssignmentReviewer AssignmentRole = "reviewer"
+	AssignmentQA       AssignmentRole = "qa"
+)
+
 type IssueAssignment struct {
 	ID         uint      `gorm:"primaryKey" json:"id"`
 	IssueID    uint      `gorm:"not null" json:"issue_id"`
 	UserID     uint      `gorm:"not null" json:"user_id"`
 	StartDate  time.Time `gorm:"type:date;not null" json:"start_date"`
 	EndDate    time.Time `gorm:"type:date;not null" json:"end_date"`
+	Estimate   *int      `gorm:"column:estimated_minutes" json:"estimated_minutes,omitempty"`
 	AssignedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"assigned_at"`
 	AssignedBy *uint     `json:"assigned_by,omitempty"`
 	IsActive   bool      `gorm:"default:true" json:"is_active"`
+	// Role is what the assignee does on the issue; an issue can have several
+	Role AssignmentRole `gorm:"type:assignment_role;not null;default:owner" json:"role"`
+	// Set when the assignment is ended by unassigning or reassigning
+	UnassignedAt *time.Time `json:"unassigned_at,omitempty"`
+	UnassignedBy *uint      `json:"unassigned_by,omitempty"`
+	// HandoverNote is left by the previous assignee when the issue is reassigned
+	HandoverNote string `gorm:"type:text" json:"handover_note,omitempty"`
 
 	// Relationships
-	Issue          Issue `gorm:"foreignKey:IssueID" json:"issue,omitempty"`
-	User           User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	AssignedByUser *User `gorm:"foreignKey:AssignedBy" json:"assigned_by_user,omitempty"`
+	Issue            Issue `gorm:"foreignKey:IssueID" json:"issue,omitempty"`
+	User             User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	AssignedByUser   *User `gorm:"foreignKey:AssignedBy" json:"assigned_by_user,omitempty"`
+	UnassignedByUser *User `gorm:"foreignKey:UnassignedBy" json:"unassigned_by_user,omitempty"`
 }
 
 type IssueWorkLog struct {
@@ -26,6 +43,7 @@ type IssueWorkLog struct {
 	UserID       uint      `gorm:"not null" json:"user_id"`
 	WorkDate     time.Time `gorm:"type:date;not null" json:"work_date"`
 	MinutesSpent int       `gorm:"not null" json:"minutes_spent"`
+	Billable     bool      `gorm:"not null" json:"billable"`
 	Notes        string    `gorm:"type:text" json:"notes"`
 	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 
@@ -54,11 +72,16 @@ type ActivityType string
 const (
 	ActivityCreated         ActivityType = "created"
 	ActivityAssigned        ActivityType = "assigned"
+	ActivityUnassigned      ActivityType = "unassigned"
+	ActivityReassigned      ActivityType = "reassigned"
 	ActivityStatusChanged   ActivityType = "status_changed"
 	ActivityPriorityChanged ActivityType = "priority_changed"
 	ActivityCommented       ActivityType = "commented"
 	ActivityHold            ActivityType = "hold"
 	ActivityResumed         ActivityType = "resumed"
+	ActivityWorkLogged      ActivityType = "work_logged"
+	ActivityWorkLogUpdated  ActivityType = "work_log_updated"
+	ActivityWorkLogDeleted  ActivityType = "work_log_deleted"
 )
 
 type IssueActivity struct {
@@ -89,3 +112,15 @@ type IssueHoldReason struct {
 	CreatedByUser  *User `gorm:"foreignKey:CreatedBy" json:"created_by_user,omitempty"`
 	ResolvedByUser *User `gorm:"foreignKey:ResolvedBy" json:"resolved_by_user,omitempty"`
 }
+
+// IssueDependency means IssueID cannot start before DependsOnID is finished
+type IssueDependency struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	IssueID     uint      `gorm:"not null" json:"issue_id"`
+	DependsOnID uint      `gorm:"column:depends_on_issue_id;not null" json:"depends_on_issue_id"`
+	CreatedBy   *uint     `json:"created_by,omitempty"`
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+
+	// Relationships
+	DependsOn Issue `gorm:"foreignKey:DependsOnID" json:"depends_on,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Label struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID uint      `gorm:"not null" json:"organization_id"`
+	Name           string    `gorm:"size:100;not null" json:"name"`
+	Color          string    `gorm:"size:7;default:#6B7280" json:"color"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type LeaveType string
+
+const (
+	LeaveVacation LeaveType = "vacation"
+	LeaveSick     LeaveType = "sick"
+	LeavePersonal LeaveType = "personal"
+	LeaveUnpaid   LeaveType = "unpaid"
+)
+
+type LeaveStatus string
+
+const (
+	LeavePending   LeaveStatus = "pending"
+	LeaveApproved  LeaveStatus = "approved"
+	LeaveRejected  LeaveStatus = "rejected"
+	LeaveCancelled LeaveStatus = "cancelled"
+)
+
+// LeaveRequest is a user's time off, approved by a manager of one of their
+// teams. Both dates are inclusive.
+type LeaveRequest struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	UserID        uint        `gorm:"not null" json:"user_id"`
+	Type          LeaveType   `gorm:"type:leave_type;not null" json:"type"`
+	StartDate     time.Time   `gorm:"type:date;not null" json:"start_date"`
+	EndDate       time.Time   `gorm:"type:date;not null" json:"end_date"`
+	Reason        string      `gorm:"type:text" json:"reason,omitempty"`
+	Status        LeaveStatus `gorm:"type:leave_status;not null;default:pending" json:"status"`
+	ReviewedBy    *uint       `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time  `json:"reviewed_at,omitempty"`
+	ReviewComment string      `gorm:"type:text" json:"review_comment,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+
+	// Relationships
+	User     User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Reviewer *User `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitempty"`
+}
+
+// Covers reports whether the leave includes the given calendar date
+func (l *LeaveRequest) Covers(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	return day >= l.StartDate.Format("2006-01-02") && day <= l.EndDate.Format("2006-01-02")
+}
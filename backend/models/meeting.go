@@ -19,24 +19,62 @@ const (
 )
 
 type Meeting struct {
-	ID               uint              `gorm:"primaryKey" json:"id"`
-	TeamID           uint              `gorm:"not null" json:"team_id"`
-	Title            string            `gorm:"size:500;not null" json:"title"`
-	Description      string            `gorm:"type:text" json:"description"`
-	MeetingDate      time.Time         `gorm:"type:date;not null" json:"meeting_date"`
-	StartTime        string            `gorm:"type:time;not null" json:"start_time"`
-	EndTime          string            `gorm:"type:time;not null" json:"end_time"`
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	TeamID      uint   `gorm:"not null" json:"team_id"`
+	Title       string `gorm:"size:500;not null" json:"title"`
+	Description string `gorm:"type:text" json:"description"`
+	// StartsAt and EndsAt are the first occurrence. Later occurrences keep its
+	// wall-clock times in Timezone, an IANA name, across DST changes.
+	StartsAt time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt   time.Time `gorm:"not null" json:"ends_at"`
+	Timezone string    `gorm:"size:64;not null;default:UTC" json:"timezone"`
+	// The dates and times of StartsAt and EndsAt in the viewer's timezone, set
+	// by InZone
+	MeetingDate      string            `gorm:"-" json:"meeting_date,omitempty"`
+	StartTime        string            `gorm:"-" json:"start_time,omitempty"`
+	EndDate          string            `gorm:"-" json:"end_date,omitempty"`
+	EndTime          string            `gorm:"-" json:"end_time,omitempty"`
 	Location         string            `gorm:"type:text" json:"location"`
 	IsRecurring      bool              `gorm:"default:false" json:"is_recurring"`
 	RecurringPattern *RecurringPattern `gorm:"type:recurring_pattern" json:"recurring_pattern,omitempty"`
-	CreatedBy        uint              `gorm:"not null" json:"created_by"`
-	CreatedAt        time.Time         `json:"created_at"`
-	UpdatedAt        time.Time         `json:"updated_at"`
+	// RecurrenceInterval repeats the meeting every N days, weeks or months
+	RecurrenceInterval int `gorm:"default:1" json:"recurrence_interval"`
+	// RecurrenceByDay lists the weekdays of weekly meetings, e.g. MO,WE,FR;
+	// empty repeats on the weekday of the first occurrence
+	RecurrenceByDay string `gorm:"size:20" json:"recurrence_by_day,omitempty"`
+	// A series ends on RecurrenceUntil, after RecurrenceCount occurrences, or
+	// never
+	RecurrenceUntil *time.Time `gorm:"type:date" json:"recurrence_until,omitempty"`
+	RecurrenceCount *int       `json:"recurrence_count,omitempty"`
+	CreatedBy       uint       `gorm:"not null" json:"created_by"`
+	// ExternalUID is the iCalendar UID of an imported meeting
+	ExternalUID *string   `gorm:"column:external_uid;size:255" json:"external_uid,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 
 	// Relationships
-	Team      Team              `gorm:"foreignKey:TeamID" json:"team,omitempty"`
-	Creator   User              `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
-	Attendees []MeetingAttendee `gorm:"foreignKey:MeetingID" json:"attendees,omitempty"`
+	Team       Team               `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Creator    User               `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Attendees  []MeetingAttendee  `gorm:"foreignKey:MeetingID" json:"attendees,omitempty"`
+	Exceptions []MeetingException `gorm:"foreignKey:MeetingID" json:"exceptions,omitempty"`
+}
+
+// Zone loads the meeting's timezone, falling back to UTC when it is unknown
+func (m *Meeting) Zone() *time.Location {
+	if loc, err := time.LoadLocation(m.Timezone); err == nil && m.Timezone != "" {
+		return loc
+	}
+	return time.UTC
+}
+
+// InZone sets the local dates and times of the meeting in the viewer's
+// timezone
+func (m *Meeting) InZone(loc *time.Location) {
+	start, end := m.StartsAt.In(loc), m.EndsAt.In(loc)
+	m.MeetingDate = start.Format("2006-01-02")
+	m.StartTime = start.Format("15:04")
+	m.EndDate = end.Format("2006-01-02")
+	m.EndTime = end.Format("15:04")
 }
 
 type MeetingAttendee struct {
@@ -49,3 +87,19 @@ type MeetingAttendee struct {
 	// Relationships
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
+
+// MeetingException changes or cancels one occurrence of a recurring meeting
+type MeetingException struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	MeetingID      uint      `gorm:"not null" json:"meeting_id"`
+	OccurrenceDate time.Time `gorm:"type:date;not null" json:"occurrence_date"`
+	Cancelled      bool      `gorm:"default:false" json:"cancelled"`
+	// The changed occurrence; unset when it is cancelled
+	Title       *string    `gorm:"size:500" json:"title,omitempty"`
+	Description *string    `gorm:"type:text" json:"description,omitempty"`
+	Location    *string    `gorm:"type:text" json:"location,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
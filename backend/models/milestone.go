@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Milestone is a release target for an organization, or for a single team when TeamID is set
+type Milestone struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	OrganizationID uint       `gorm:"not null" json:"organization_id"`
+	TeamID         *uint      `json:"team_id,omitempty"`
+	Name           string     `gorm:"size:255;not null" json:"name"`
+	Description    string     `gorm:"type:text" json:"description"`
+	TargetDate     time.Time  `gorm:"type:date;not null" json:"target_date"`
+	ReleasedAt     *time.Time `json:"released_at,omitempty"`
+	CreatedBy      uint       `gorm:"not null" json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relationships
+	Team    *Team   `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Creator User    `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Issues  []Issue `gorm:"foreignKey:MilestoneID" json:"issues,omitempty"`
+}
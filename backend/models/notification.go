@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationMention           NotificationType = "mention"
+	NotificationTimerStopped      NotificationType = "timer_stopped"
+	NotificationTimesheetReviewed NotificationType = "timesheet_reviewed"
+	NotificationLeaveRequested    NotificationType = "leave_requested"
+	NotificationLeaveReviewed     NotificationType = "leave_reviewed"
+)
+
+// Mention records a user mentioned in an issue description (CommentID nil) or a comment
+type Mention struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	IssueID     uint      `gorm:"not null" json:"issue_id"`
+	CommentID   *uint     `json:"comment_id,omitempty"`
+	UserID      uint      `gorm:"not null" json:"user_id"`
+	MentionedBy uint      `gorm:"not null" json:"mentioned_by"`
+	OutsideTeam bool      `gorm:"default:false" json:"outside_team"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (Mention) TableName() string {
+	return "issue_mentions"
+}
+
+type Notification struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	UserID    uint             `gorm:"not null" json:"user_id"`
+	Type      NotificationType `gorm:"type:notification_type;not null" json:"type"`
+	IssueID   *uint            `json:"issue_id,omitempty"`
+	CommentID *uint            `json:"comment_id,omitempty"`
+	ActorID   *uint            `json:"actor_id,omitempty"`
+	Message   string           `gorm:"type:text;not null" json:"message"`
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+
+	// Relationships
+	Actor *User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+type ProjectStatus string
+
+const (
+	ProjectPlanned   ProjectStatus = "planned"
+	ProjectActive    ProjectStatus = "active"
+	ProjectOnHold    ProjectStatus = "on_hold"
+	ProjectCompleted ProjectStatus = "completed"
+	ProjectCancelled ProjectStatus = "cancelled"
+)
+
+type ProjectHealth string
+
+const (
+	HealthOnTrack  ProjectHealth = "on_track"
+	HealthAtRisk   ProjectHealth = "at_risk"
+	HealthOffTrack ProjectHealth = "off_track"
+)
+
+// Project (epic) groups issues from any team of an organization
+type Project struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	OrganizationID uint          `gorm:"not null" json:"organization_id"`
+	Name           string        `gorm:"size:255;not null" json:"name"`
+	Description    string        `gorm:"type:text" json:"description"`
+	OwnerID        uint          `gorm:"not null" json:"owner_id"`
+	StartDate      *time.Time    `gorm:"type:date" json:"start_date,omitempty"`
+	TargetDate     *time.Time    `gorm:"type:date" json:"target_date,omitempty"`
+	Status         ProjectStatus `gorm:"type:project_status;default:planned" json:"status"`
+	Health         ProjectHealth `gorm:"type:project_health;default:on_track" json:"health"`
+	CreatedBy      uint          `gorm:"not null" json:"created_by"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+
+	// Relationships
+	Owner   User    `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
+	Creator User    `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Issues  []Issue `gorm:"foreignKey:ProjectID" json:"issues,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// HourlyRate prices an hour of work in an organization, either for one user
+// or for everyone with a team role. A user's own rate wins over the role rate,
+// and the rate in effect on the work date applies.
+type HourlyRate struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID uint      `gorm:"not null" json:"organization_id"`
+	UserID         *uint     `json:"user_id,omitempty"`
+	Role           *TeamRole `gorm:"type:team_role" json:"role,omitempty"`
+	Rate           float64   `gorm:"type:numeric(10,2);not null" json:"rate"`
+	EffectiveFrom  time.Time `gorm:"type:date;not null" json:"effective_from"`
+	CreatedBy      uint      `gorm:"not null" json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
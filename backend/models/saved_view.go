@@ -0,0 +1,86 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type ViewVisibility string
+
+const (
+	ViewPrivate ViewVisibility = "private"
+	ViewTeam    ViewVisibility = "team"
+)
+
+// IssueFilter holds the criteria of an issue query. Empty fields don't filter.
+type IssueFilter struct {
+	StatusIDs    []uint          `json:"status_ids,omitempty"`
+	Priorities   []IssuePriority `json:"priorities,omitempty"`
+	AssigneeIDs  []uint          `json:"assignee_ids,omitempty"`
+	Unassigned   bool            `json:"unassigned,omitempty"`
+	LabelIDs     []uint          `json:"label_ids,omitempty"`
+	SprintID     *uint           `json:"sprint_id,omitempty"`
+	MilestoneID  *uint           `json:"milestone_id,omitempty"`
+	ProjectID    *uint           `json:"project_id,omitempty"`
+	DeadlineFrom string          `json:"deadline_from,omitempty"`
+	DeadlineTo   string          `json:"deadline_to,omitempty"`
+	Search       string          `json:"search,omitempty"`
+}
+
+func (f IssueFilter) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+func (f *IssueFilter) Scan(value interface{}) error {
+	return scanJSON(value, f)
+}
+
+// ViewColumns is the ordered list of columns a view displays
+type ViewColumns []string
+
+func (c ViewColumns) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *ViewColumns) Scan(value interface{}) error {
+	return scanJSON(value, c)
+}
+
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return errors.New("unsupported JSON value")
+	}
+}
+
+// SavedView is a named issue query, either private to its owner or shared with a team
+type SavedView struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	TeamID     uint           `gorm:"not null" json:"team_id"`
+	OwnerID    uint           `gorm:"not null" json:"owner_id"`
+	Name       string         `gorm:"size:255;not null" json:"name"`
+	Visibility ViewVisibility `gorm:"type:view_visibility;default:private" json:"visibility"`
+	Filters    IssueFilter    `gorm:"type:jsonb;not null" json:"filters"`
+	SortBy     string         `gorm:"size:50;default:rank" json:"sort_by"`
+	SortOrder  string         `gorm:"size:4;default:asc" json:"sort_order"`
+	GroupBy    string         `gorm:"size:50" json:"group_by,omitempty"`
+	Columns    ViewColumns    `gorm:"type:jsonb;not null" json:"columns"`
+	IsDefault  bool           `gorm:"default:false" json:"is_default"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+
+	// Relationships
+	Team  Team `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Owner User `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+type SprintState string
+
+const (
+	SprintPlanned SprintState = "planned"
+	SprintActive  SprintState = "active"
+	SprintClosed  SprintState = "closed"
+)
+
+type Sprint struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	TeamID    uint        `gorm:"not null" json:"team_id"`
+	Name      string      `gorm:"size:255;not null" json:"name"`
+	Goal      string      `gorm:"type:text" json:"goal"`
+	StartDate time.Time   `gorm:"type:date;not null" json:"start_date"`
+	EndDate   time.Time   `gorm:"type:date;not null" json:"end_date"`
+	State     SprintState `gorm:"type:sprint_state;default:planned" json:"state"`
+	StartedAt *time.Time  `json:"started_at,omitempty"`
+	ClosedAt  *time.Time  `json:"closed_at,omitempty"`
+	CreatedBy uint        `gorm:"not null" json:"created_by"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+
+	// Relationships
+	Team    Team    `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Creator User    `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Issues  []Issue `gorm:"foreignKey:SprintID" json:"issues,omitempty"`
+}
+
+type SprintScopeChangeType string
+
+const (
+	ScopeAdded   SprintScopeChangeType = "added"
+	ScopeRemoved SprintScopeChangeType = "removed"
+)
+
+// SprintScopeChange records an issue entering or leaving a sprint
+type SprintScopeChange struct {
+	ID         uint                  `gorm:"primaryKey" json:"id"`
+	SprintID   uint                  `gorm:"not null" json:"sprint_id"`
+	IssueID    uint                  `gorm:"not null" json:"issue_id"`
+	ChangeType SprintScopeChangeType `gorm:"type:sprint_scope_change_type;not null" json:"change_type"`
+	Reason     string                `gorm:"type:text" json:"reason"`
+	ChangedBy  *uint                 `json:"changed_by,omitempty"`
+	ChangedAt  time.Time             `gorm:"default:CURRENT_TIMESTAMP" json:"changed_at"`
+
+	// Relationships
+	Issue         Issue `gorm:"foreignKey:IssueID" json:"issue,omitempty"`
+	ChangedByUser *User `gorm:"foreignKey:ChangedBy" json:"changed_by_user,omitempty"`
+}
@@ -12,9 +12,17 @@ type Team struct {
 	ParentTeamID   *uint          `json:"parent_team_id,omitempty"`
 	Name           string         `gorm:"size:255;not null" json:"name"`
 	Description    string         `gorm:"type:text" json:"description"`
+	SingleAssignee bool           `gorm:"not null" json:"single_assignee"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	// AutoAssign picks an assignee for new issues; see AutoAssignService
+	AutoAssign AutoAssignStrategy `gorm:"column:auto_assign_strategy;type:auto_assign_strategy;not null;default:none" json:"auto_assign_strategy"`
+	// AutoAssignCursor is the member last picked by round robin
+	AutoAssignCursor *uint `gorm:"column:auto_assign_last_user_id" json:"-"`
+	// MeetingConflicts decides whether meetings with booked attendees are
+	// only warned about or rejected
+	MeetingConflicts MeetingConflictPolicy `gorm:"column:meeting_conflict_policy;type:meeting_conflict_policy;not null;default:warn" json:"meeting_conflict_policy"`
 
 	// Relationships
 	Organization Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
@@ -24,6 +32,22 @@ type Team struct {
 	Issues       []Issue      `gorm:"foreignKey:TeamID" json:"issues,omitempty"`
 }
 
+type AutoAssignStrategy string
+
+const (
+	AutoAssignNone        AutoAssignStrategy = "none"
+	AutoAssignRoundRobin  AutoAssignStrategy = "round_robin"
+	AutoAssignLeastLoaded AutoAssignStrategy = "least_loaded"
+	AutoAssignSkills      AutoAssignStrategy = "skills"
+)
+
+type MeetingConflictPolicy string
+
+const (
+	MeetingConflictWarn  MeetingConflictPolicy = "warn"
+	MeetingConflictBlock MeetingConflictPolicy = "block"
+)
+
 type TeamRole string
 
 const (
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type TimerState string
+
+const (
+	TimerRunning TimerState = "running"
+	TimerPaused  TimerState = "paused"
+)
+
+// WorkTimer tracks time spent on an issue until it is stopped and turned into
+// a work log. StartedAt is the start of the current running period; time of
+// earlier periods is kept in AccumulatedSeconds.
+type WorkTimer struct {
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	UserID             uint       `gorm:"not null" json:"user_id"`
+	IssueID            uint       `gorm:"not null" json:"issue_id"`
+	State              TimerState `gorm:"type:timer_state;not null;default:running" json:"state"`
+	StartedAt          *time.Time `json:"started_at,omitempty"`
+	AccumulatedSeconds int        `gorm:"not null;default:0" json:"accumulated_seconds"`
+	Billable           bool       `gorm:"not null" json:"billable"`
+	Notes              string     `gorm:"type:text" json:"notes"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// ElapsedSeconds is computed when timers are returned
+	ElapsedSeconds int `gorm:"-" json:"elapsed_seconds"`
+
+	// Relationships
+	Issue *Issue `gorm:"foreignKey:IssueID" json:"issue,omitempty"`
+}
+
+// Elapsed returns the total tracked time up to the given moment
+func (t *WorkTimer) Elapsed(at time.Time) time.Duration {
+	elapsed := time.Duration(t.AccumulatedSeconds) * time.Second
+	if t.State == TimerRunning && t.StartedAt != nil && at.After(*t.StartedAt) {
+		elapsed += at.Sub(*t.StartedAt)
+	}
+	return elapsed
+}
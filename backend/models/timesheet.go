@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type TimesheetStatus string
+
+const (
+	TimesheetSubmitted TimesheetStatus = "submitted"
+	TimesheetApproved  TimesheetStatus = "approved"
+	TimesheetRejected  TimesheetStatus = "rejected"
+)
+
+// Timesheet records the submission and approval of a user's week of work
+// logs. Weeks start on Monday. Weeks without a timesheet are open drafts.
+type Timesheet struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	UserID        uint            `gorm:"not null" json:"user_id"`
+	WeekStart     time.Time       `gorm:"type:date;not null" json:"week_start"`
+	Status        TimesheetStatus `gorm:"type:timesheet_status;not null" json:"status"`
+	TotalMinutes  int             `gorm:"not null;default:0" json:"total_minutes"`
+	SubmittedAt   time.Time       `json:"submitted_at"`
+	ReviewedBy    *uint           `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewComment string          `gorm:"type:text" json:"review_comment,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+
+	// Relationships
+	User     User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Reviewer *User `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitempty"`
+}
+
+// IsLocked reports whether the week's work logs can no longer be changed
+func (t *Timesheet) IsLocked() bool {
+	return t.Status == TimesheetSubmitted || t.Status == TimesheetApproved
+}
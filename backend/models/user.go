@@ -13,6 +13,9 @@ type User struct {
 	PasswordHash   string         `gorm:"size:255;not null" json:"-"`
 	FullName       string         `gorm:"size:255;not null" json:"full_name"`
 	Timezone       string         `gorm:"size:50;default:Asia/Jakarta" json:"timezone"`
+	WorkdayStart   string         `gorm:"size:5;default:09:00" json:"workday_start"`
+	WorkdayEnd     string         `gorm:"size:5;default:17:00" json:"workday_end"`
+	WeeklyCapacity *int           `gorm:"column:weekly_capacity_minutes" json:"weekly_capacity_minutes"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
@@ -21,4 +24,5 @@ type User struct {
 	Organization  Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
 	TeamMembers   []TeamMember `gorm:"foreignKey:UserID" json:"team_members,omitempty"`
 	CreatedIssues []Issue      `gorm:"foreignKey:CreatedBy" json:"created_issues,omitempty"`
+	Skills        []Label      `gorm:"many2many:user_skills" json:"skills,omitempty"`
 }
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"task-management/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -18,14 +19,32 @@ func (r *AssignmentRepository) Create(assignment *models.IssueAssignment) error
 	return r.db.Create(assignment).Error
 }
 
+func (r *AssignmentRepository) FindByID(id uint) (*models.IssueAssignment, error) {
+	var assignment models.IssueAssignment
+	err := r.db.Preload("User").First(&assignment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
 func (r *AssignmentRepository) FindByIssue(issueID uint) ([]models.IssueAssignment, error) {
 	var assignments []models.IssueAssignment
-	err := r.db.Preload("User").Preload("AssignedByUser").
+	err := r.db.Preload("User").Preload("AssignedByUser").Preload("UnassignedByUser").
 		Where("issue_id = ?", issueID).
 		Order("assigned_at DESC").Find(&assignments).Error
 	return assignments, err
 }
 
+// FindActiveByIssue returns the active assignees of the issue in the role
+func (r *AssignmentRepository) FindActiveByIssue(issueID uint, role models.AssignmentRole) ([]models.IssueAssignment, error) {
+	var assignments []models.IssueAssignment
+	err := r.db.Preload("User").
+		Where("issue_id = ? AND role = ? AND is_active = true", issueID, role).
+		Find(&assignments).Error
+	return assignments, err
+}
+
 func (r *AssignmentRepository) FindActiveByUser(userID uint) ([]models.IssueAssignment, error) {
 	var assignments []models.IssueAssignment
 	err := r.db.Preload("Issue.Status").Preload("Issue.Team").
@@ -34,8 +53,74 @@ func (r *AssignmentRepository) FindActiveByUser(userID uint) ([]models.IssueAssi
 	return assignments, err
 }
 
-func (r *AssignmentRepository) DeactivateOldAssignments(issueID uint) error {
-	return r.db.Model(&models.IssueAssignment{}).
-		Where("issue_id = ? AND is_active = true", issueID).
-		Update("is_active", false).Error
+// FindActiveOverlapping returns the active assignments of the users that
+// overlap the date range, with their issues
+func (r *AssignmentRepository) FindActiveOverlapping(userIDs []uint, from, to time.Time) ([]models.IssueAssignment, error) {
+	var assignments []models.IssueAssignment
+	if len(userIDs) == 0 {
+		return assignments, nil
+	}
+	err := r.db.Preload("Issue").
+		Joins("JOIN issues ON issues.id = issue_assignments.issue_id AND issues.deleted_at IS NULL").
+		Where("issue_assignments.user_id IN ? AND issue_assignments.is_active = true", userIDs).
+		Where("issue_assignments.start_date <= ? AND issue_assignments.end_date >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("issue_assignments.start_date ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// FindOpenByUsers returns the active assignments of the users on issues that
+// are not done yet
+func (r *AssignmentRepository) FindOpenByUsers(userIDs []uint) ([]models.IssueAssignment, error) {
+	var assignments []models.IssueAssignment
+	if len(userIDs) == 0 {
+		return assignments, nil
+	}
+	err := r.db.
+		Joins("JOIN issues ON issues.id = issue_assignments.issue_id AND issues.deleted_at IS NULL").
+		Joins("LEFT JOIN issue_statuses ON issue_statuses.id = issues.status_id").
+		Where("issue_assignments.user_id IN ? AND issue_assignments.is_active = true", userIDs).
+		Where("issue_statuses.is_final IS NOT TRUE").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// endAssignment deactivates an active assignment, recording who ended it
+func endAssignment(db *gorm.DB, assignment *models.IssueAssignment, endedBy uint) error {
+	now := time.Now()
+	result := db.Model(&models.IssueAssignment{}).
+		Where("id = ? AND is_active = true", assignment.ID).
+		Updates(map[string]interface{}{
+			"is_active":     false,
+			"unassigned_at": now,
+			"unassigned_by": endedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	// Someone else ended it first
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	assignment.IsActive = false
+	assignment.UnassignedAt = &now
+	assignment.UnassignedBy = &endedBy
+	return nil
+}
+
+func (r *AssignmentRepository) End(assignment *models.IssueAssignment, endedBy uint) error {
+	return endAssignment(r.db, assignment, endedBy)
+}
+
+// Replace ends the assignments and creates the one taking over from them in a
+// single transaction
+func (r *AssignmentRepository) Replace(ended []models.IssueAssignment, endedBy uint, assignment *models.IssueAssignment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range ended {
+			if err := endAssignment(tx, &ended[i], endedBy); err != nil {
+				return err
+			}
+		}
+		return tx.Create(assignment).Error
+	})
 }
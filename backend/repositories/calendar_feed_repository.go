@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"task-management/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarFeedRepository(db *gorm.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+func (r *CalendarFeedRepository) Create(feed *models.CalendarFeed) error {
+	return r.db.Create(feed).Error
+}
+
+func (r *CalendarFeedRepository) FindByID(id uint) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.First(&feed, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *CalendarFeedRepository) FindByTokenHash(hash string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.Where("token_hash = ?", hash).First(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *CalendarFeedRepository) FindByUser(userID uint) ([]models.CalendarFeed, error) {
+	var feeds []models.CalendarFeed
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&feeds).Error
+	return feeds, err
+}
+
+func (r *CalendarFeedRepository) Touch(id uint, at time.Time) error {
+	return r.db.Model(&models.CalendarFeed{}).Where("id = ?", id).Update("last_accessed_at", at).Error
+}
+
+func (r *CalendarFeedRepository) Delete(id uint) error {
+	return r.db.Delete(&models.CalendarFeed{}, id).Error
+}
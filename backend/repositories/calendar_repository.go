@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"task-management/models"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,26 +15,34 @@ func NewCalendarRepository(db *gorm.DB) *CalendarRepository {
 	return &CalendarRepository{db: db}
 }
 
+// CalendarEvent is an issue assignment, approved leave (Type "leave") or an
+// issue deadline (Type "deadline")
 type CalendarEvent struct {
-	IssueID     uint      `json:"issue_id"`
-	IssueTitle  string    `json:"issue_title"`
-	Priority    string    `json:"priority"`
+	Type        string    `json:"type"`
+	LeaveType   string    `json:"leave_type,omitempty"`
+	IssueID     uint      `json:"issue_id,omitempty"`
+	IssueTitle  string    `json:"issue_title,omitempty"`
+	Priority    string    `json:"priority,omitempty"`
 	StatusID    *uint     `json:"status_id"`
 	StatusName  string    `json:"status_name"`
 	StatusColor string    `json:"status_color"`
 	UserID      uint      `json:"user_id"`
 	UserName    string    `json:"user_name"`
+	Role        string    `json:"role,omitempty"`
 	TeamID      uint      `json:"team_id"`
 	TeamName    string    `json:"team_name"`
 	StartDate   time.Time `json:"start_date"`
 	EndDate     time.Time `json:"end_date"`
 }
 
-func (r *CalendarRepository) GetCalendarEvents(teamID *uint, userID *uint, startDate, endDate time.Time) ([]CalendarEvent, error) {
+// GetCalendarEvents returns the active assignments overlapping the range.
+// Without teams, assignments of every team are returned.
+func (r *CalendarRepository) GetCalendarEvents(teamIDs []uint, userID *uint, startDate, endDate time.Time) ([]CalendarEvent, error) {
 	var events []CalendarEvent
 
 	query := r.db.Table("issue_assignments").
 		Select(`
+			'assignment' as type,
 			issues.id as issue_id,
 			issues.title as issue_title,
 			issues.priority,
@@ -42,6 +51,7 @@ func (r *CalendarRepository) GetCalendarEvents(teamID *uint, userID *uint, start
 			issue_statuses.color as status_color,
 			users.id as user_id,
 			users.full_name as user_name,
+			issue_assignments.role,
 			teams.id as team_id,
 			teams.name as team_name,
 			issue_assignments.start_date,
@@ -55,8 +65,8 @@ func (r *CalendarRepository) GetCalendarEvents(teamID *uint, userID *uint, start
 		Where("issues.deleted_at IS NULL").
 		Where("issue_assignments.end_date >= ? AND issue_assignments.start_date <= ?", startDate, endDate)
 
-	if teamID != nil {
-		query = query.Where("issues.team_id = ?", *teamID)
+	if teamIDs != nil {
+		query = query.Where("issues.team_id IN ?", teamIDs)
 	}
 
 	if userID != nil {
@@ -66,3 +76,69 @@ func (r *CalendarRepository) GetCalendarEvents(teamID *uint, userID *uint, start
 	err := query.Order("issue_assignments.start_date ASC").Scan(&events).Error
 	return events, err
 }
+
+// GetLeaveEvents returns approved leave overlapping the range of the members
+// of the teams. With one team, the events carry it.
+func (r *CalendarRepository) GetLeaveEvents(teamIDs []uint, userID *uint, startDate, endDate time.Time) ([]CalendarEvent, error) {
+	var events []CalendarEvent
+
+	columns := `
+		'leave' as type,
+		leave_requests.type as leave_type,
+		users.id as user_id,
+		users.full_name as user_name,
+		leave_requests.start_date,
+		leave_requests.end_date`
+	query := r.db.Table("leave_requests").
+		Joins("INNER JOIN users ON leave_requests.user_id = users.id").
+		Where("leave_requests.status = ?", models.LeaveApproved).
+		Where("leave_requests.end_date >= ? AND leave_requests.start_date <= ?", startDate, endDate)
+
+	if len(teamIDs) == 1 {
+		columns += `,
+		teams.id as team_id,
+		teams.name as team_name`
+		query = query.
+			Joins("INNER JOIN team_members ON team_members.user_id = users.id AND team_members.team_id = ?", teamIDs[0]).
+			Joins("INNER JOIN teams ON team_members.team_id = teams.id")
+	} else {
+		query = query.Where("leave_requests.user_id IN (?)",
+			r.db.Model(&models.TeamMember{}).Select("user_id").Where("team_id IN ?", teamIDs))
+	}
+
+	if userID != nil {
+		query = query.Where("leave_requests.user_id = ?", *userID)
+	}
+
+	err := query.Select(columns).Order("leave_requests.start_date ASC").Scan(&events).Error
+	return events, err
+}
+
+// GetDeadlineEvents returns the deadlines in the range of the issues the user
+// is actively assigned to
+func (r *CalendarRepository) GetDeadlineEvents(userID uint, startDate, endDate time.Time) ([]CalendarEvent, error) {
+	var events []CalendarEvent
+
+	err := r.db.Table("issues").
+		Select(`
+			'deadline' as type,
+			issues.id as issue_id,
+			issues.title as issue_title,
+			issues.priority,
+			issue_statuses.id as status_id,
+			issue_statuses.name as status_name,
+			issue_statuses.color as status_color,
+			teams.id as team_id,
+			teams.name as team_name,
+			issues.deadline as start_date,
+			issues.deadline as end_date
+		`).
+		Joins("LEFT JOIN issue_statuses ON issues.status_id = issue_statuses.id").
+		Joins("INNER JOIN teams ON issues.team_id = teams.id").
+		Where("issues.deleted_at IS NULL").
+		Where("issues.deadline >= ? AND issues.deadline <= ?", startDate, endDate).
+		Where("EXISTS (SELECT 1 FROM issue_assignments WHERE issue_assignments.issue_id = issues.id AND issue_assignments.user_id = ? AND issue_assignments.is_active = true)", userID).
+		Order("issues.deadline ASC").
+		Scan(&events).Error
+	return events, err
+}
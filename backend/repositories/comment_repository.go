@@ -20,13 +20,26 @@ func (r *CommentRepository) Create(comment *models.Comment) error {
 
 func (r *CommentRepository) FindByIssue(issueID uint) ([]models.Comment, error) {
 	var comments []models.Comment
-	err := r.db.Preload("User").Where("issue_id = ?", issueID).Order("created_at ASC").Find(&comments).Error
+	err := r.db.Preload("User").Preload("Mentions.User").Where("issue_id = ?", issueID).Order("created_at ASC").Find(&comments).Error
+	return comments, err
+}
+
+// FindThreads returns the top-level comments of an issue with their replies
+func (r *CommentRepository) FindThreads(issueID uint) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Preload("User").Preload("Mentions.User").Preload("ResolvedByUser").Preload("ModeratedByUser").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Replies.User").Preload("Replies.Mentions.User").Preload("Replies.ModeratedByUser").
+		Where("issue_id = ? AND parent_id IS NULL", issueID).Order("created_at ASC").Find(&comments).Error
 	return comments, err
 }
 
 func (r *CommentRepository) FindByID(id uint) (*models.Comment, error) {
 	var comment models.Comment
-	err := r.db.Preload("User").First(&comment, id).Error
+	err := r.db.Preload("User").Preload("Mentions.User").Preload("ResolvedByUser").Preload("ModeratedByUser").
+		First(&comment, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -34,9 +47,51 @@ func (r *CommentRepository) FindByID(id uint) (*models.Comment, error) {
 }
 
 func (r *CommentRepository) Update(comment *models.Comment) error {
-	return r.db.Save(comment).Error
+	return r.db.Omit("User", "ResolvedByUser", "ModeratedByUser", "Mentions", "Replies").Save(comment).Error
 }
 
 func (r *CommentRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Comment{}, id).Error
 }
+
+func (r *CommentRepository) CreateRevision(revision *models.CommentRevision) error {
+	return r.db.Create(revision).Error
+}
+
+func (r *CommentRepository) FindRevisions(commentID uint) ([]models.CommentRevision, error) {
+	var revisions []models.CommentRevision
+	err := r.db.Preload("Editor").Where("comment_id = ?", commentID).Order("edited_at DESC, id DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *CommentRepository) DeleteRevisions(commentID uint) error {
+	return r.db.Where("comment_id = ?", commentID).Delete(&models.CommentRevision{}).Error
+}
+
+func (r *CommentRepository) FindReactions(commentIDs []uint) ([]models.CommentReaction, error) {
+	var reactions []models.CommentReaction
+	if len(commentIDs) == 0 {
+		return reactions, nil
+	}
+	err := r.db.Where("comment_id IN ?", commentIDs).Order("created_at ASC").Find(&reactions).Error
+	return reactions, err
+}
+
+// ToggleReaction adds the user's reaction, or removes it when it already
+// exists. It reports whether the reaction is now present.
+func (r *CommentRepository) ToggleReaction(commentID, userID uint, emoji string) (bool, error) {
+	result := r.db.Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, userID, emoji).
+		Delete(&models.CommentReaction{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return false, nil
+	}
+
+	reaction := &models.CommentReaction{CommentID: commentID, UserID: userID, Emoji: emoji}
+	if err := r.db.Create(reaction).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+)
+
+type DependencyRepository struct {
+	db *gorm.DB
+}
+
+func NewDependencyRepository(db *gorm.DB) *DependencyRepository {
+	return &DependencyRepository{db: db}
+}
+
+func (r *DependencyRepository) Create(dependency *models.IssueDependency) error {
+	return r.db.Create(dependency).Error
+}
+
+func (r *DependencyRepository) Delete(issueID, dependsOnID uint) error {
+	return r.db.Where("issue_id = ? AND depends_on_issue_id = ?", issueID, dependsOnID).
+		Delete(&models.IssueDependency{}).Error
+}
+
+// FindBlockers returns the dependencies of an issue with the blocking issues preloaded
+func (r *DependencyRepository) FindBlockers(issueID uint) ([]models.IssueDependency, error) {
+	var dependencies []models.IssueDependency
+	err := r.db.Preload("DependsOn.Status").Where("issue_id = ?", issueID).Find(&dependencies).Error
+	return dependencies, err
+}
+
+// FindBlockerIDs returns the IDs of the issues the given issue depends on
+func (r *DependencyRepository) FindBlockerIDs(issueID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.IssueDependency{}).Where("issue_id = ?", issueID).
+		Pluck("depends_on_issue_id", &ids).Error
+	return ids, err
+}
+
+// FindAmong returns the dependencies where both issues are in the given set
+func (r *DependencyRepository) FindAmong(issueIDs []uint) ([]models.IssueDependency, error) {
+	var dependencies []models.IssueDependency
+	if len(issueIDs) == 0 {
+		return dependencies, nil
+	}
+	err := r.db.Where("issue_id IN ? AND depends_on_issue_id IN ?", issueIDs, issueIDs).
+		Find(&dependencies).Error
+	return dependencies, err
+}
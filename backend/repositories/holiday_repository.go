@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"task-management/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HolidayRepository struct {
+	db *gorm.DB
+}
+
+func NewHolidayRepository(db *gorm.DB) *HolidayRepository {
+	return &HolidayRepository{db: db}
+}
+
+func (r *HolidayRepository) CreateCalendar(calendar *models.HolidayCalendar) error {
+	return r.db.Create(calendar).Error
+}
+
+// FindCalendarByID loads a calendar with its holidays in date order
+func (r *HolidayRepository) FindCalendarByID(id uint) (*models.HolidayCalendar, error) {
+	var calendar models.HolidayCalendar
+	err := r.db.Preload("Holidays", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date")
+	}).First(&calendar, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (r *HolidayRepository) FindCalendarsByOrganization(orgID uint) ([]models.HolidayCalendar, error) {
+	var calendars []models.HolidayCalendar
+	err := r.db.Where("organization_id = ?", orgID).Order("name").Find(&calendars).Error
+	return calendars, err
+}
+
+func (r *HolidayRepository) DeleteCalendar(id uint) error {
+	return r.db.Delete(&models.HolidayCalendar{}, id).Error
+}
+
+func (r *HolidayRepository) FindHolidayByID(id uint) (*models.Holiday, error) {
+	var holiday models.Holiday
+	err := r.db.First(&holiday, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// UpsertHolidays adds holidays to a calendar, renaming those on dates the
+// calendar already has
+func (r *HolidayRepository) UpsertHolidays(holidays []models.Holiday) error {
+	if len(holidays) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "calendar_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "uid"}),
+	}).Create(&holidays).Error
+}
+
+func (r *HolidayRepository) DeleteHoliday(id uint) error {
+	return r.db.Delete(&models.Holiday{}, id).Error
+}
+
+// FindByOrganization lists the holidays of all of the organization's
+// calendars within the range
+func (r *HolidayRepository) FindByOrganization(orgID uint, from, to time.Time) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := r.db.Joins("JOIN holiday_calendars ON holiday_calendars.id = holidays.calendar_id").
+		Where("holiday_calendars.organization_id = ?", orgID).
+		Where("holidays.date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("holidays.date").
+		Find(&holidays).Error
+	return holidays, err
+}
@@ -20,7 +20,7 @@ func (r *IssueRepository) Create(issue *models.Issue) error {
 
 func (r *IssueRepository) FindByID(id uint) (*models.Issue, error) {
 	var issue models.Issue
-	err := r.db.Preload("Status").Preload("Creator").Preload("Team").
+	err := r.db.Preload("Status").Preload("Creator").Preload("Team").Preload("Labels").
 		Preload("Assignments.User").Preload("Assignments.AssignedByUser").
 		Preload("HoldReasons", func(db *gorm.DB) *gorm.DB {
 			return db.Order("created_at DESC")
@@ -35,23 +35,126 @@ func (r *IssueRepository) FindByID(id uint) (*models.Issue, error) {
 
 func (r *IssueRepository) FindByTeam(teamID uint) ([]models.Issue, error) {
 	var issues []models.Issue
-	err := r.db.Preload("Status").Preload("Creator").
+	err := r.db.Preload("Status").Preload("Creator").Preload("Labels").
 		Preload("Assignments.User").
 		Where("team_id = ? AND deleted_at IS NULL", teamID).
-		Order("created_at DESC").Find(&issues).Error
+		Order("rank ASC, id ASC").Find(&issues).Error
 	return issues, err
 }
 
+// Search returns a team's issues matching the filter. sortColumn must be a
+// trusted column name; ties are broken by rank.
+func (r *IssueRepository) Search(teamID uint, filter *models.IssueFilter, sortColumn string, desc bool) ([]models.Issue, error) {
+	query := r.db.Preload("Status").Preload("Creator").Preload("Labels").
+		Preload("Assignments.User").
+		Where("team_id = ? AND deleted_at IS NULL", teamID)
+
+	if len(filter.StatusIDs) > 0 {
+		query = query.Where("status_id IN ?", filter.StatusIDs)
+	}
+	if len(filter.Priorities) > 0 {
+		query = query.Where("priority IN ?", filter.Priorities)
+	}
+	if len(filter.AssigneeIDs) > 0 {
+		query = query.Where("id IN (?)", r.db.Table("issue_assignments").Select("issue_id").
+			Where("user_id IN ? AND is_active = ?", filter.AssigneeIDs, true))
+	}
+	if filter.Unassigned {
+		query = query.Where("id NOT IN (?)", r.db.Table("issue_assignments").Select("issue_id").
+			Where("is_active = ?", true))
+	}
+	if len(filter.LabelIDs) > 0 {
+		query = query.Where("id IN (?)", r.db.Table("issue_labels").Select("issue_id").
+			Where("label_id IN ?", filter.LabelIDs))
+	}
+	if filter.SprintID != nil {
+		query = query.Where("sprint_id = ?", *filter.SprintID)
+	}
+	if filter.MilestoneID != nil {
+		query = query.Where("milestone_id = ?", *filter.MilestoneID)
+	}
+	if filter.ProjectID != nil {
+		query = query.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.DeadlineFrom != "" {
+		query = query.Where("deadline >= ?", filter.DeadlineFrom)
+	}
+	if filter.DeadlineTo != "" {
+		query = query.Where("deadline <= ?", filter.DeadlineTo)
+	}
+	if filter.Search != "" {
+		pattern := "%" + filter.Search + "%"
+		query = query.Where("(title ILIKE ? OR description ILIKE ?)", pattern, pattern)
+	}
+
+	order := sortColumn + " ASC NULLS LAST"
+	if desc {
+		order = sortColumn + " DESC NULLS LAST"
+	}
+
+	var issues []models.Issue
+	err := query.Order(order).Order("rank ASC, id ASC").Find(&issues).Error
+	return issues, err
+}
+
+// FindExistingIDs returns which of the given issue IDs exist in the organization
+func (r *IssueRepository) FindExistingIDs(orgID uint, ids []uint) ([]uint, error) {
+	var existing []uint
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	err := r.db.Model(&models.Issue{}).
+		Joins("JOIN teams ON teams.id = issues.team_id").
+		Where("issues.id IN ? AND teams.organization_id = ? AND issues.deleted_at IS NULL", ids, orgID).
+		Pluck("issues.id", &existing).Error
+	return existing, err
+}
+
+// FindLastRank returns the rank of the bottom issue in a team's backlog
+func (r *IssueRepository) FindLastRank(teamID uint) (string, error) {
+	var rank string
+	err := r.db.Model(&models.Issue{}).
+		Where("team_id = ? AND deleted_at IS NULL", teamID).
+		Select("COALESCE(MAX(rank), '')").Scan(&rank).Error
+	return rank, err
+}
+
+// UpdatePosition moves an issue to a new rank and, optionally, a new status
+func (r *IssueRepository) UpdatePosition(id uint, rank string, statusID *uint) error {
+	updates := map[string]interface{}{"rank": rank}
+	if statusID != nil {
+		updates["status_id"] = *statusID
+	}
+	return r.db.Model(&models.Issue{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *IssueRepository) CountByStatus(teamID, statusID, excludeIssueID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Issue{}).
+		Where("team_id = ? AND status_id = ? AND id <> ? AND deleted_at IS NULL", teamID, statusID, excludeIssueID).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *IssueRepository) Update(issue *models.Issue) error {
 	return r.db.Save(issue).Error
 }
 
+// UpdateDescriptionHTML stores the rendered description without touching updated_at
+func (r *IssueRepository) UpdateDescriptionHTML(id uint, rendered string) error {
+	return r.db.Model(&models.Issue{}).Where("id = ?", id).UpdateColumn("description_html", rendered).Error
+}
+
 func (r *IssueRepository) Delete(id uint) error {
 	return r.db.Model(&models.Issue{}).Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
 }
 
 func (r *IssueRepository) CreateActivity(activity *models.IssueActivity) error {
-	return r.db.Omit("Metadata").Create(activity).Error
+	// Leave the column default when there is no metadata
+	if activity.Metadata == nil {
+		return r.db.Omit("Metadata").Create(activity).Error
+	}
+	return r.db.Create(activity).Error
 }
 
 func (r *IssueRepository) GetActivities(issueID uint) ([]models.IssueActivity, error) {
@@ -64,6 +167,13 @@ func (r *IssueRepository) CreateStatusLog(log *models.IssueStatusLog) error {
 	return r.db.Create(log).Error
 }
 
+// GetStatusLogs returns the status history of the given issues, oldest first
+func (r *IssueRepository) GetStatusLogs(issueIDs []uint) ([]models.IssueStatusLog, error) {
+	var logs []models.IssueStatusLog
+	err := r.db.Where("issue_id IN ?", issueIDs).Order("changed_at ASC, id ASC").Find(&logs).Error
+	return logs, err
+}
+
 func (r *IssueRepository) CreateHoldReason(reason *models.IssueHoldReason) error {
 	return r.db.Create(reason).Error
 }
@@ -76,13 +186,3 @@ func (r *IssueRepository) ResolveHoldReason(issueID, userID uint) error {
 			"resolved_by": userID,
 		}).Error
 }
-
-func (r *IssueRepository) CreateWorkLog(log *models.IssueWorkLog) error {
-	return r.db.Create(log).Error
-}
-
-func (r *IssueRepository) GetWorkLogs(issueID uint) ([]models.IssueWorkLog, error) {
-	var logs []models.IssueWorkLog
-	err := r.db.Preload("User").Where("issue_id = ?", issueID).Order("work_date DESC").Find(&logs).Error
-	return logs, err
-}
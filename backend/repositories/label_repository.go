@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+)
+
+type LabelRepository struct {
+	db *gorm.DB
+}
+
+func NewLabelRepository(db *gorm.DB) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+func (r *LabelRepository) Create(label *models.Label) error {
+	return r.db.Create(label).Error
+}
+
+func (r *LabelRepository) FindByID(id uint) (*models.Label, error) {
+	var label models.Label
+	err := r.db.First(&label, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+func (r *LabelRepository) FindByOrganization(orgID uint) ([]models.Label, error) {
+	var labels []models.Label
+	err := r.db.Where("organization_id = ?", orgID).Order("name ASC").Find(&labels).Error
+	return labels, err
+}
+
+func (r *LabelRepository) FindByIDs(orgID uint, ids []uint) ([]models.Label, error) {
+	var labels []models.Label
+	err := r.db.Where("organization_id = ? AND id IN ?", orgID, ids).Find(&labels).Error
+	return labels, err
+}
+
+func (r *LabelRepository) Update(label *models.Label) error {
+	return r.db.Save(label).Error
+}
+
+func (r *LabelRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Label{}, id).Error
+}
+
+// ReplaceIssueLabels sets the complete label list of an issue
+func (r *LabelRepository) ReplaceIssueLabels(issueID uint, labels []models.Label) error {
+	return r.db.Model(&models.Issue{ID: issueID}).Association("Labels").Replace(labels)
+}
+
+func (r *LabelRepository) FindUserSkills(userID uint) ([]models.Label, error) {
+	var labels []models.Label
+	err := r.db.Model(&models.User{ID: userID}).Order("name ASC").Association("Skills").Find(&labels)
+	return labels, err
+}
+
+// ReplaceUserSkills sets the complete skill list of a user
+func (r *LabelRepository) ReplaceUserSkills(userID uint, labels []models.Label) error {
+	return r.db.Model(&models.User{ID: userID}).Association("Skills").Replace(labels)
+}
+
+// FindSkillIDs returns the skill label IDs of each of the users
+func (r *LabelRepository) FindSkillIDs(userIDs []uint) (map[uint][]uint, error) {
+	var rows []struct {
+		UserID  uint
+		LabelID uint
+	}
+	skills := make(map[uint][]uint)
+	if len(userIDs) == 0 {
+		return skills, nil
+	}
+	err := r.db.Table("user_skills").Where("user_id IN ?", userIDs).Find(&rows).Error
+	for _, row := range rows {
+		skills[row.UserID] = append(skills[row.UserID], row.LabelID)
+	}
+	return skills, err
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"task-management/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LeaveRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveRepository(db *gorm.DB) *LeaveRepository {
+	return &LeaveRepository{db: db}
+}
+
+func (r *LeaveRepository) Create(leave *models.LeaveRequest) error {
+	return r.db.Omit("User", "Reviewer").Create(leave).Error
+}
+
+func (r *LeaveRepository) FindByID(id uint) (*models.LeaveRequest, error) {
+	var leave models.LeaveRequest
+	err := r.db.Preload("User").Preload("Reviewer").First(&leave, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+// FindByUser lists the user's leave requests, latest first
+func (r *LeaveRepository) FindByUser(userID uint) ([]models.LeaveRequest, error) {
+	var leaves []models.LeaveRequest
+	err := r.db.Preload("Reviewer").
+		Where("user_id = ?", userID).
+		Order("start_date DESC").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// FindOverlapping lists the leave of the users with one of the given statuses
+// that overlaps the date range, earliest first
+func (r *LeaveRepository) FindOverlapping(userIDs []uint, statuses []models.LeaveStatus, from, to time.Time) ([]models.LeaveRequest, error) {
+	var leaves []models.LeaveRequest
+	if len(userIDs) == 0 {
+		return leaves, nil
+	}
+	err := r.db.Preload("User").
+		Where("user_id IN ? AND status IN ?", userIDs, statuses).
+		Where("start_date <= ? AND end_date >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("start_date, user_id").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// Save updates a leave request
+func (r *LeaveRepository) Save(leave *models.LeaveRequest) error {
+	return r.db.Omit("User", "Reviewer").Save(leave).Error
+}
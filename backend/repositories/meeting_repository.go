@@ -1,10 +1,12 @@
 package repositories
 
 import (
+	"database/sql"
 	"task-management/models"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MeetingRepository struct {
@@ -15,13 +17,25 @@ func NewMeetingRepository(db *gorm.DB) *MeetingRepository {
 	return &MeetingRepository{db: db}
 }
 
+// seriesOverlaps matches meetings ending after the @from time, and recurring
+// meetings whose series does not end before the @fromDate date
+const seriesOverlaps = "(meetings.ends_at > @from OR (meetings.is_recurring = true AND " +
+	"(meetings.recurrence_until IS NULL OR meetings.recurrence_until >= @fromDate)))"
+
+// overlapsFrom binds seriesOverlaps to a time. Series end on a date in the
+// meeting's timezone, which can be a day behind UTC.
+func overlapsFrom(from time.Time) []interface{} {
+	fromDate := from.UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	return []interface{}{sql.Named("from", from), sql.Named("fromDate", fromDate)}
+}
+
 func (r *MeetingRepository) Create(meeting *models.Meeting) error {
 	return r.db.Create(meeting).Error
 }
 
 func (r *MeetingRepository) FindByID(id uint) (*models.Meeting, error) {
 	var meeting models.Meeting
-	err := r.db.Preload("Team").Preload("Creator").Preload("Attendees.User").First(&meeting, id).Error
+	err := r.db.Preload("Team").Preload("Creator").Preload("Attendees.User").Preload("Exceptions").First(&meeting, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -32,28 +46,173 @@ func (r *MeetingRepository) FindByTeam(teamID uint) ([]models.Meeting, error) {
 	var meetings []models.Meeting
 	err := r.db.Preload("Creator").Preload("Attendees.User").
 		Where("team_id = ?", teamID).
-		Order("meeting_date ASC, start_time ASC").
+		Order("meetings.starts_at ASC").
 		Find(&meetings).Error
 	return meetings, err
 }
 
-func (r *MeetingRepository) FindByDateRange(teamID uint, startDate, endDate time.Time) ([]models.Meeting, error) {
+// FindByDateRange returns the team's meetings taking place in the time from
+// start up to end, and the recurring meetings that may have occurrences in it
+func (r *MeetingRepository) FindByDateRange(teamID uint, start, end time.Time) ([]models.Meeting, error) {
 	var meetings []models.Meeting
-	err := r.db.Preload("Creator").Preload("Attendees.User").
-		Where("team_id = ? AND meeting_date >= ? AND meeting_date <= ?", teamID, startDate, endDate).
-		Order("meeting_date ASC, start_time ASC").
+	err := r.db.Preload("Creator").Preload("Attendees.User").Preload("Exceptions").
+		Where("team_id = ? AND starts_at < ?", teamID, end).
+		Where(seriesOverlaps, overlapsFrom(start)...).
+		Order("meetings.starts_at ASC").
 		Find(&meetings).Error
 	return meetings, err
 }
 
-func (r *MeetingRepository) Update(meeting *models.Meeting) error {
-	return r.db.Save(meeting).Error
+// FindByExternalUID returns the team's meeting imported from the iCalendar
+// event with the UID
+func (r *MeetingRepository) FindByExternalUID(teamID uint, uid string) (*models.Meeting, error) {
+	var meeting models.Meeting
+	err := r.db.Where("team_id = ? AND external_uid = ?", teamID, uid).First(&meeting).Error
+	if err != nil {
+		return nil, err
+	}
+	return &meeting, nil
+}
+
+// ImportedMeeting is a meeting read from an iCalendar file, to be created or
+// updated with its attendees and the changes to its occurrences, or deleted
+// when its event was cancelled
+type ImportedMeeting struct {
+	Meeting    *models.Meeting
+	Attendees  []models.MeetingAttendee
+	Exceptions []models.MeetingException
+	Delete     bool
+}
+
+// SaveImports saves the meetings of an import in one transaction
+func (r *MeetingRepository) SaveImports(imports []ImportedMeeting) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range imports {
+			if imports[i].Delete {
+				if err := tx.Delete(&models.Meeting{}, imports[i].Meeting.ID).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := saveImported(tx, imports[i].Meeting, imports[i].Attendees); err != nil {
+				return err
+			}
+			// The file's changed and cancelled occurrences replace the
+			// meeting's
+			meetingID := imports[i].Meeting.ID
+			if err := tx.Where("meeting_id = ?", meetingID).Delete(&models.MeetingException{}).Error; err != nil {
+				return err
+			}
+			exceptions := imports[i].Exceptions
+			for j := range exceptions {
+				exceptions[j].MeetingID = meetingID
+			}
+			if len(exceptions) > 0 {
+				if err := tx.Create(&exceptions).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// saveImported creates or updates an imported meeting and replaces its
+// attendees. Attendees who were already invited keep their response.
+func saveImported(tx *gorm.DB, meeting *models.Meeting, attendees []models.MeetingAttendee) error {
+	if err := tx.Omit(clause.Associations).Save(meeting).Error; err != nil {
+		return err
+	}
+
+	var existing []models.MeetingAttendee
+	if err := tx.Where("meeting_id = ?", meeting.ID).Find(&existing).Error; err != nil {
+		return err
+	}
+	responded := make(map[uint]models.AttendeeStatus)
+	for _, attendee := range existing {
+		responded[attendee.UserID] = attendee.Status
+	}
+
+	if err := tx.Where("meeting_id = ?", meeting.ID).Delete(&models.MeetingAttendee{}).Error; err != nil {
+		return err
+	}
+	for i := range attendees {
+		attendees[i].MeetingID = meeting.ID
+		if status, ok := responded[attendees[i].UserID]; ok && status != models.AttendeeStatusPending {
+			attendees[i].Status = status
+		}
+	}
+	if len(attendees) == 0 {
+		return nil
+	}
+	return tx.Create(&attendees).Error
+}
+
+// Update saves a meeting and deletes the exceptions of occurrences its
+// schedule no longer has, in one transaction
+func (r *MeetingRepository) Update(meeting *models.Meeting, staleExceptionIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(meeting).Error; err != nil {
+			return err
+		}
+		if len(staleExceptionIDs) == 0 {
+			return nil
+		}
+		return tx.Delete(&models.MeetingException{}, staleExceptionIDs).Error
+	})
 }
 
 func (r *MeetingRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Meeting{}, id).Error
 }
 
+// SaveException adds or replaces the exception of an occurrence
+func (r *MeetingRepository) SaveException(exception *models.MeetingException) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "meeting_id"}, {Name: "occurrence_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"cancelled", "title", "description", "location", "starts_at", "ends_at", "updated_at",
+		}),
+	}).Create(exception).Error
+}
+
+// EndSeries saves a recurring meeting whose series was cut short and deletes
+// the exceptions of the occurrences it no longer has
+func (r *MeetingRepository) EndSeries(meeting *models.Meeting) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(meeting).Error; err != nil {
+			return err
+		}
+		return tx.Where("meeting_id = ? AND occurrence_date > ?", meeting.ID, meeting.RecurrenceUntil).
+			Delete(&models.MeetingException{}).Error
+	})
+}
+
+// SplitSeries ends a recurring meeting and continues it as a new meeting with
+// the given attendees, in one transaction
+func (r *MeetingRepository) SplitSeries(ended, next *models.Meeting, attendees []models.MeetingAttendee) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(ended).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("meeting_id = ? AND occurrence_date > ?", ended.ID, ended.RecurrenceUntil).
+			Delete(&models.MeetingException{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Create(next).Error; err != nil {
+			return err
+		}
+		for i := range attendees {
+			attendees[i].ID = 0
+			attendees[i].MeetingID = next.ID
+		}
+		if len(attendees) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(&attendees).Error
+	})
+}
+
 // Attendee operations
 func (r *MeetingRepository) AddAttendee(attendee *models.MeetingAttendee) error {
 	return r.db.Create(attendee).Error
@@ -70,13 +229,47 @@ func (r *MeetingRepository) RemoveAttendee(meetingID, userID uint) error {
 		Delete(&models.MeetingAttendee{}).Error
 }
 
-func (r *MeetingRepository) GetUserMeetings(userID uint, startDate, endDate time.Time) ([]models.Meeting, error) {
+func (r *MeetingRepository) GetUserMeetings(userID uint, start, end time.Time) ([]models.Meeting, error) {
 	var meetings []models.Meeting
 	err := r.db.Preload("Creator").Preload("Attendees.User").
 		Joins("JOIN meeting_attendees ON meeting_attendees.meeting_id = meetings.id").
-		Where("meeting_attendees.user_id = ? AND meetings.meeting_date >= ? AND meetings.meeting_date <= ?",
-			userID, startDate, endDate).
-		Order("meeting_date ASC, start_time ASC").
+		Where("meeting_attendees.user_id = ? AND meetings.starts_at < ? AND meetings.ends_at > ?",
+			userID, end, start).
+		Order("meetings.starts_at ASC").
+		Find(&meetings).Error
+	return meetings, err
+}
+
+// FindByAttendee returns the meetings taking place in the time from start up
+// to end that the user is invited to, and recurring ones that started before
+// it ends
+func (r *MeetingRepository) FindByAttendee(userID uint, start, end time.Time) ([]models.Meeting, error) {
+	return r.FindByAttendees([]uint{userID}, start, end)
+}
+
+// FindByAttendees returns the meetings taking place in the time from start up
+// to end that any of the users is invited to, and recurring ones that started
+// before it ends
+func (r *MeetingRepository) FindByAttendees(userIDs []uint, start, end time.Time) ([]models.Meeting, error) {
+	var meetings []models.Meeting
+	err := r.db.Preload("Creator").Preload("Attendees.User").Preload("Exceptions").
+		Where("meetings.id IN (SELECT meeting_id FROM meeting_attendees WHERE user_id IN ?)", userIDs).
+		Where("meetings.starts_at < ?", end).
+		Where(seriesOverlaps, overlapsFrom(start)...).
+		Order("meetings.starts_at ASC").
+		Find(&meetings).Error
+	return meetings, err
+}
+
+// FindUpcomingByAttendee returns the team's meetings that have not ended by the
+// given time, and its recurring meetings, that the user is invited to
+func (r *MeetingRepository) FindUpcomingByAttendee(teamID, userID uint, from time.Time) ([]models.Meeting, error) {
+	var meetings []models.Meeting
+	err := r.db.
+		Joins("JOIN meeting_attendees ON meeting_attendees.meeting_id = meetings.id").
+		Where("meetings.team_id = ? AND meeting_attendees.user_id = ?", teamID, userID).
+		Where(seriesOverlaps, overlapsFrom(from)...).
+		Order("meetings.starts_at ASC").
 		Find(&meetings).Error
 	return meetings, err
 }
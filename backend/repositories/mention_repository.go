@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+)
+
+type MentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) *MentionRepository {
+	return &MentionRepository{db: db}
+}
+
+func (r *MentionRepository) Create(mention *models.Mention) error {
+	return r.db.Create(mention).Error
+}
+
+// FindBySource returns the mentions of an issue description (commentID nil) or of a comment
+func (r *MentionRepository) FindBySource(issueID uint, commentID *uint) ([]models.Mention, error) {
+	var mentions []models.Mention
+	query := r.db.Preload("User").Where("issue_id = ?", issueID)
+	if commentID == nil {
+		query = query.Where("comment_id IS NULL")
+	} else {
+		query = query.Where("comment_id = ?", *commentID)
+	}
+	err := query.Order("id ASC").Find(&mentions).Error
+	return mentions, err
+}
+
+func (r *MentionRepository) FindByIssue(issueID uint) ([]models.Mention, error) {
+	var mentions []models.Mention
+	err := r.db.Preload("User").Where("issue_id = ?", issueID).Order("created_at ASC").Find(&mentions).Error
+	return mentions, err
+}
+
+func (r *MentionRepository) DeleteByIDs(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Delete(&models.Mention{}, ids).Error
+}
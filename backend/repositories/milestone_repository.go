@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+)
+
+type MilestoneRepository struct {
+	db *gorm.DB
+}
+
+func NewMilestoneRepository(db *gorm.DB) *MilestoneRepository {
+	return &MilestoneRepository{db: db}
+}
+
+func (r *MilestoneRepository) Create(milestone *models.Milestone) error {
+	return r.db.Create(milestone).Error
+}
+
+func (r *MilestoneRepository) FindByID(id uint) (*models.Milestone, error) {
+	var milestone models.Milestone
+	err := r.db.Preload("Team").Preload("Creator").First(&milestone, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// FindByOrganization lists milestones of an organization. When teamID is set,
+// only that team's milestones and organization-wide milestones are returned.
+func (r *MilestoneRepository) FindByOrganization(orgID uint, teamID *uint) ([]models.Milestone, error) {
+	var milestones []models.Milestone
+	query := r.db.Preload("Team").Where("organization_id = ?", orgID)
+	if teamID != nil {
+		query = query.Where("team_id = ? OR team_id IS NULL", *teamID)
+	}
+	err := query.Order("target_date ASC").Find(&milestones).Error
+	return milestones, err
+}
+
+func (r *MilestoneRepository) FindIssues(milestoneID uint) ([]models.Issue, error) {
+	var issues []models.Issue
+	err := r.db.Preload("Status").Preload("Labels").Preload("Team").
+		Where("milestone_id = ? AND deleted_at IS NULL", milestoneID).
+		Order("team_id ASC, rank ASC, id ASC").Find(&issues).Error
+	return issues, err
+}
+
+func (r *MilestoneRepository) Update(milestone *models.Milestone) error {
+	return r.db.Omit("Team", "Creator", "Issues").Save(milestone).Error
+}
+
+func (r *MilestoneRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Milestone{}, id).Error
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OffboardingRepository struct {
+	db *gorm.DB
+}
+
+func NewOffboardingRepository(db *gorm.DB) *OffboardingRepository {
+	return &OffboardingRepository{db: db}
+}
+
+// Offboarding is everything that changes when a user leaves a team
+type Offboarding struct {
+	TeamID   uint
+	UserID   uint
+	ByUserID uint
+	// Ended assignments are replaced by the assignment at the same index of
+	// Replacements, or just ended when it is nil
+	Ended        []models.IssueAssignment
+	Replacements []*models.IssueAssignment
+	// Timers are deleted; stopped ones are completed by the work log at the
+	// same index of WorkLogs, discarded ones have nil there
+	Timers   []models.WorkTimer
+	WorkLogs []*models.IssueWorkLog
+	// The user is removed from the meetings of MeetingIDs, and from recurring
+	// meetings that already took place by splitting them in SeriesSplits
+	MeetingIDs   []uint
+	SeriesSplits []SeriesSplit
+	// Activities builds the activity log entries once the replacements and
+	// work logs have their IDs
+	Activities func() ([]*models.IssueActivity, error)
+}
+
+// SeriesSplit ends a recurring meeting and continues it as Next, with the
+// remaining attendees and the changes to its later occurrences
+type SeriesSplit struct {
+	Ended     *models.Meeting
+	Next      *models.Meeting
+	Attendees []models.MeetingAttendee
+}
+
+// Apply carries out the offboarding in a single transaction, ending with the
+// removal of the team membership
+func (r *OffboardingRepository) Apply(o *Offboarding) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range o.Ended {
+			if err := endAssignment(tx, &o.Ended[i], o.ByUserID); err != nil {
+				return err
+			}
+			if o.Replacements[i] != nil {
+				if err := tx.Create(o.Replacements[i]).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		for i := range o.Timers {
+			result := tx.Delete(&models.WorkTimer{}, o.Timers[i].ID)
+			if result.Error != nil {
+				return result.Error
+			}
+			// The user stopped it in the meantime
+			if result.RowsAffected == 0 {
+				o.WorkLogs[i] = nil
+				continue
+			}
+			if o.WorkLogs[i] != nil {
+				if err := tx.Create(o.WorkLogs[i]).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(o.MeetingIDs) > 0 {
+			err := tx.Where("meeting_id IN ? AND user_id = ?", o.MeetingIDs, o.UserID).
+				Delete(&models.MeetingAttendee{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, split := range o.SeriesSplits {
+			if err := tx.Omit(clause.Associations).Save(split.Ended).Error; err != nil {
+				return err
+			}
+			if err := tx.Omit(clause.Associations).Create(split.Next).Error; err != nil {
+				return err
+			}
+			err := tx.Model(&models.MeetingException{}).
+				Where("meeting_id = ? AND occurrence_date > ?", split.Ended.ID, split.Ended.RecurrenceUntil).
+				Update("meeting_id", split.Next.ID).Error
+			if err != nil {
+				return err
+			}
+			for i := range split.Attendees {
+				split.Attendees[i].ID = 0
+				split.Attendees[i].MeetingID = split.Next.ID
+			}
+			if len(split.Attendees) > 0 {
+				if err := tx.Omit(clause.Associations).Create(&split.Attendees).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		activities, err := o.Activities()
+		if err != nil {
+			return err
+		}
+		for _, activity := range activities {
+			if err := tx.Create(activity).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("team_id = ? AND user_id = ?", o.TeamID, o.UserID).Delete(&models.TeamMember{}).Error
+	})
+}
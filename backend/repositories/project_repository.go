@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"task-management/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProjectRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectRepository(db *gorm.DB) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+type ProjectTeamCount struct {
+	TeamID     uint   `json:"team_id"`
+	TeamName   string `json:"team_name"`
+	IssueCount int64  `json:"issue_count"`
+}
+
+func (r *ProjectRepository) Create(project *models.Project) error {
+	return r.db.Create(project).Error
+}
+
+func (r *ProjectRepository) FindByID(id uint) (*models.Project, error) {
+	var project models.Project
+	err := r.db.Preload("Owner").Preload("Creator").First(&project, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *ProjectRepository) FindByOrganization(orgID uint) ([]models.Project, error) {
+	var projects []models.Project
+	err := r.db.Preload("Owner").Where("organization_id = ?", orgID).
+		Order("created_at DESC").Find(&projects).Error
+	return projects, err
+}
+
+func (r *ProjectRepository) Update(project *models.Project) error {
+	return r.db.Omit("Owner", "Creator", "Issues").Save(project).Error
+}
+
+func (r *ProjectRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Project{}, id).Error
+}
+
+// FindIssues returns the project's issues that belong to one of the given teams
+func (r *ProjectRepository) FindIssues(projectID uint, teamIDs []uint) ([]models.Issue, error) {
+	var issues []models.Issue
+	if len(teamIDs) == 0 {
+		return issues, nil
+	}
+	err := r.db.Preload("Status").Preload("Team").Preload("Labels").Preload("Assignments.User").
+		Where("project_id = ? AND team_id IN ? AND deleted_at IS NULL", projectID, teamIDs).
+		Order("team_id ASC, rank ASC, id ASC").Find(&issues).Error
+	return issues, err
+}
+
+func (r *ProjectRepository) CountIssues(projectID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Issue{}).
+		Where("project_id = ? AND deleted_at IS NULL", projectID).Count(&count).Error
+	return count, err
+}
+
+func (r *ProjectRepository) CountByCategory(projectID uint) ([]CategoryCount, error) {
+	var counts []CategoryCount
+	err := r.db.Table("issues").
+		Select(`CASE
+			WHEN issue_statuses.is_final THEN 'done'
+			ELSE COALESCE(issue_statuses.category::text, 'todo')
+		END AS category, COUNT(*) AS count`).
+		Joins("LEFT JOIN issue_statuses ON issues.status_id = issue_statuses.id").
+		Where("issues.project_id = ? AND issues.deleted_at IS NULL", projectID).
+		Group("1").Scan(&counts).Error
+	return counts, err
+}
+
+func (r *ProjectRepository) CountByTeam(projectID uint) ([]ProjectTeamCount, error) {
+	var counts []ProjectTeamCount
+	err := r.db.Table("issues").
+		Select("teams.id AS team_id, teams.name AS team_name, COUNT(*) AS issue_count").
+		Joins("INNER JOIN teams ON issues.team_id = teams.id").
+		Where("issues.project_id = ? AND issues.deleted_at IS NULL", projectID).
+		Group("teams.id, teams.name").Order("teams.name ASC").Scan(&counts).Error
+	return counts, err
+}
+
+func (r *ProjectRepository) SumLoggedMinutes(projectID uint) (int64, error) {
+	var minutes int64
+	err := r.db.Table("issue_work_logs").
+		Select("COALESCE(SUM(issue_work_logs.minutes_spent), 0)").
+		Joins("INNER JOIN issues ON issue_work_logs.issue_id = issues.id").
+		Where("issues.project_id = ? AND issues.deleted_at IS NULL", projectID).
+		Scan(&minutes).Error
+	return minutes, err
+}
+
+// CountOverdue counts open issues whose deadline is before the given date
+func (r *ProjectRepository) CountOverdue(projectID uint, today time.Time) (int64, error) {
+	var count int64
+	err := r.db.Table("issues").
+		Joins("LEFT JOIN issue_statuses ON issues.status_id = issue_statuses.id").
+		Where("issues.project_id = ? AND issues.deleted_at IS NULL", projectID).
+		Where("issues.deadline < ?", today).
+		Where("issue_statuses.id IS NULL OR issue_statuses.is_final = false").
+		Count(&count).Error
+	return count, err
+}
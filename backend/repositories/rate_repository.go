@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+)
+
+type RateRepository struct {
+	db *gorm.DB
+}
+
+func NewRateRepository(db *gorm.DB) *RateRepository {
+	return &RateRepository{db: db}
+}
+
+func (r *RateRepository) Create(rate *models.HourlyRate) error {
+	return r.db.Create(rate).Error
+}
+
+func (r *RateRepository) FindByID(id uint) (*models.HourlyRate, error) {
+	var rate models.HourlyRate
+	err := r.db.First(&rate, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *RateRepository) FindByOrganization(orgID uint) ([]models.HourlyRate, error) {
+	var rates []models.HourlyRate
+	err := r.db.Preload("User").Where("organization_id = ?", orgID).
+		Order("user_id NULLS FIRST, role, effective_from DESC").
+		Find(&rates).Error
+	return rates, err
+}
+
+func (r *RateRepository) Delete(id uint) error {
+	return r.db.Delete(&models.HourlyRate{}, id).Error
+}
@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// TimeReportQuery selects and groups work logs for a time report. GroupBy
+// holds report dimensions: user, team, issue, label, project or period.
+type TimeReportQuery struct {
+	TeamIDs   []uint
+	From      time.Time
+	To        time.Time
+	GroupBy   []string
+	Period    string // day, week or month
+	UserID    *uint
+	ProjectID *uint
+	LabelID   *uint
+	Billable  *bool
+}
+
+// TimeReportKey is the value of one dimension for a row; ID is nil for
+// periods and for work without a label or project
+type TimeReportKey struct {
+	ID   *uint
+	Name string
+}
+
+type TimeReportRow struct {
+	Keys            []TimeReportKey
+	Minutes         int64
+	BillableMinutes int64
+	Cost            float64
+	BillableCost    float64
+}
+
+type reportDimension struct {
+	id   string
+	name string
+}
+
+var reportDimensions = map[string]reportDimension{
+	"user":    {id: "wl.user_id", name: "u.full_name"},
+	"team":    {id: "t.id", name: "t.name"},
+	"issue":   {id: "i.id", name: "i.title"},
+	"label":   {id: "l.id", name: "l.name"},
+	"project": {id: "p.id", name: "p.name"},
+}
+
+// IsReportDimension reports whether name can be used to group time reports
+func IsReportDimension(name string) bool {
+	_, ok := reportDimensions[name]
+	return ok || name == "period"
+}
+
+// The rate of a work log is the user's own rate, or else the rate of their
+// role in the issue's team, in effect on the work date
+const reportRateJoin = `
+LEFT JOIN team_members tm ON tm.team_id = i.team_id AND tm.user_id = wl.user_id
+LEFT JOIN LATERAL (
+	SELECT hr.rate FROM hourly_rates hr
+	WHERE hr.organization_id = t.organization_id
+	AND hr.effective_from <= wl.work_date
+	AND (hr.user_id = wl.user_id OR (hr.user_id IS NULL AND hr.role = tm.role))
+	ORDER BY (hr.user_id IS NOT NULL) DESC, hr.effective_from DESC
+	LIMIT 1
+) rate ON TRUE`
+
+const reportTotals = `
+SUM(wl.minutes_spent) AS minutes,
+SUM(CASE WHEN wl.billable THEN wl.minutes_spent ELSE 0 END) AS billable_minutes,
+COALESCE(SUM(wl.minutes_spent * rate.rate / 60.0), 0)::float8 AS cost,
+COALESCE(SUM(CASE WHEN wl.billable THEN wl.minutes_spent * rate.rate / 60.0 ELSE 0 END), 0)::float8 AS billable_cost`
+
+func (q *TimeReportQuery) build(grouped bool) (string, []interface{}) {
+	var selects, groups, orders []string
+	joinLabels := q.LabelID != nil
+	if grouped {
+		for _, dimension := range q.GroupBy {
+			if dimension == "period" {
+				period := fmt.Sprintf("date_trunc('%s', wl.work_date)::date", q.Period)
+				selects = append(selects, period)
+				groups = append(groups, period)
+				orders = append(orders, period)
+				continue
+			}
+			d := reportDimensions[dimension]
+			selects = append(selects, d.id, d.name)
+			groups = append(groups, d.id, d.name)
+			orders = append(orders, d.name+" NULLS LAST", d.id)
+			if dimension == "label" {
+				joinLabels = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	for _, s := range selects {
+		b.WriteString(s + ", ")
+	}
+	b.WriteString(reportTotals)
+	b.WriteString(`
+FROM issue_work_logs wl
+JOIN issues i ON i.id = wl.issue_id
+JOIN teams t ON t.id = i.team_id
+JOIN users u ON u.id = wl.user_id
+LEFT JOIN projects p ON p.id = i.project_id`)
+	if grouped && joinLabels {
+		b.WriteString(`
+LEFT JOIN issue_labels il ON il.issue_id = i.id
+LEFT JOIN labels l ON l.id = il.label_id`)
+	}
+	b.WriteString(reportRateJoin)
+	b.WriteString(`
+WHERE i.team_id IN ? AND wl.work_date BETWEEN ? AND ? AND i.deleted_at IS NULL`)
+
+	args := []interface{}{q.TeamIDs, q.From.Format("2006-01-02"), q.To.Format("2006-01-02")}
+	if q.UserID != nil {
+		b.WriteString(" AND wl.user_id = ?")
+		args = append(args, *q.UserID)
+	}
+	if q.ProjectID != nil {
+		b.WriteString(" AND i.project_id = ?")
+		args = append(args, *q.ProjectID)
+	}
+	if q.LabelID != nil {
+		if grouped && joinLabels {
+			b.WriteString(" AND il.label_id = ?")
+		} else {
+			b.WriteString(" AND EXISTS (SELECT 1 FROM issue_labels fl WHERE fl.issue_id = i.id AND fl.label_id = ?)")
+		}
+		args = append(args, *q.LabelID)
+	}
+	if q.Billable != nil {
+		b.WriteString(" AND wl.billable = ?")
+		args = append(args, *q.Billable)
+	}
+
+	if len(groups) > 0 {
+		b.WriteString("\nGROUP BY " + strings.Join(groups, ", "))
+		b.WriteString("\nORDER BY " + strings.Join(orders, ", "))
+	}
+	return b.String(), args
+}
+
+// StreamTimeReport runs the grouped report and passes each row to fn as it
+// is read from the database, so large reports are never held in memory
+func (r *ReportRepository) StreamTimeReport(q *TimeReportQuery, fn func(row *TimeReportRow) error) error {
+	query, args := q.build(true)
+	rows, err := r.db.Raw(query, args...).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &TimeReportRow{Keys: make([]TimeReportKey, len(q.GroupBy))}
+		var dest []interface{}
+		ids := make([]sql.NullInt64, len(q.GroupBy))
+		names := make([]sql.NullString, len(q.GroupBy))
+		periods := make([]time.Time, len(q.GroupBy))
+		for i, dimension := range q.GroupBy {
+			if dimension == "period" {
+				dest = append(dest, &periods[i])
+			} else {
+				dest = append(dest, &ids[i], &names[i])
+			}
+		}
+		dest = append(dest, &row.Minutes, &row.BillableMinutes, &row.Cost, &row.BillableCost)
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		for i, dimension := range q.GroupBy {
+			if dimension == "period" {
+				row.Keys[i].Name = periods[i].Format("2006-01-02")
+				continue
+			}
+			if ids[i].Valid {
+				id := uint(ids[i].Int64)
+				row.Keys[i].ID = &id
+			}
+			row.Keys[i].Name = names[i].String
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// TimeReportTotals sums the whole report. Unlike the rows it counts work on
+// issues with several labels once.
+func (r *ReportRepository) TimeReportTotals(q *TimeReportQuery) (*TimeReportRow, error) {
+	query, args := q.build(false)
+	row := &TimeReportRow{}
+	var minutes, billableMinutes sql.NullInt64
+	err := r.db.Raw(query, args...).Row().Scan(&minutes, &billableMinutes, &row.Cost, &row.BillableCost)
+	row.Minutes = minutes.Int64
+	row.BillableMinutes = billableMinutes.Int64
+	return row, err
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+)
+
+type SavedViewRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedViewRepository(db *gorm.DB) *SavedViewRepository {
+	return &SavedViewRepository{db: db}
+}
+
+func (r *SavedViewRepository) Create(view *models.SavedView) error {
+	return r.db.Create(view).Error
+}
+
+func (r *SavedViewRepository) FindByID(id uint) (*models.SavedView, error) {
+	var view models.SavedView
+	err := r.db.Preload("Owner").First(&view, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// FindVisible lists the team's shared views and the user's own private views
+func (r *SavedViewRepository) FindVisible(teamID, userID uint) ([]models.SavedView, error) {
+	var views []models.SavedView
+	err := r.db.Preload("Owner").
+		Where("team_id = ? AND (visibility = ? OR owner_id = ?)", teamID, models.ViewTeam, userID).
+		Order("is_default DESC, name ASC").Find(&views).Error
+	return views, err
+}
+
+func (r *SavedViewRepository) FindTeamDefault(teamID uint) (*models.SavedView, error) {
+	var view models.SavedView
+	err := r.db.Preload("Owner").Where("team_id = ? AND is_default = ?", teamID, true).First(&view).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (r *SavedViewRepository) Update(view *models.SavedView) error {
+	return r.db.Omit("Team", "Owner").Save(view).Error
+}
+
+// SetTeamDefault makes the view the team's only default view
+func (r *SavedViewRepository) SetTeamDefault(teamID, viewID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.SavedView{}).Where("team_id = ? AND is_default = ?", teamID, true).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.SavedView{}).Where("id = ?", viewID).Update("is_default", true).Error
+	})
+}
+
+func (r *SavedViewRepository) ClearDefault(viewID uint) error {
+	return r.db.Model(&models.SavedView{}).Where("id = ?", viewID).Update("is_default", false).Error
+}
+
+func (r *SavedViewRepository) Delete(id uint) error {
+	return r.db.Delete(&models.SavedView{}, id).Error
+}
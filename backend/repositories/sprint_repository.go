@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"task-management/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SprintRepository struct {
+	db *gorm.DB
+}
+
+func NewSprintRepository(db *gorm.DB) *SprintRepository {
+	return &SprintRepository{db: db}
+}
+
+func (r *SprintRepository) Create(sprint *models.Sprint) error {
+	return r.db.Create(sprint).Error
+}
+
+func (r *SprintRepository) FindByID(id uint) (*models.Sprint, error) {
+	var sprint models.Sprint
+	err := r.db.Preload("Creator").
+		Preload("Issues", func(db *gorm.DB) *gorm.DB {
+			return db.Where("deleted_at IS NULL").Order("rank ASC, id ASC")
+		}).
+		Preload("Issues.Status").Preload("Issues.Assignments.User").
+		First(&sprint, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (r *SprintRepository) FindByTeam(teamID uint) ([]models.Sprint, error) {
+	var sprints []models.Sprint
+	err := r.db.Where("team_id = ?", teamID).Order("start_date DESC").Find(&sprints).Error
+	return sprints, err
+}
+
+func (r *SprintRepository) FindActiveByTeam(teamID uint) (*models.Sprint, error) {
+	var sprint models.Sprint
+	err := r.db.Where("team_id = ? AND state = ?", teamID, models.SprintActive).First(&sprint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+// FindNextPlanned returns the earliest planned sprint of a team starting on or after the given date
+func (r *SprintRepository) FindNextPlanned(teamID uint, after time.Time) (*models.Sprint, error) {
+	var sprint models.Sprint
+	err := r.db.Where("team_id = ? AND state = ? AND start_date >= ?", teamID, models.SprintPlanned, after).
+		Order("start_date ASC").First(&sprint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (r *SprintRepository) Update(sprint *models.Sprint) error {
+	return r.db.Omit("Issues", "Team", "Creator").Save(sprint).Error
+}
+
+func (r *SprintRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Sprint{}, id).Error
+}
+
+// SetIssueSprint moves issues into a sprint, or back to the backlog when sprintID is nil
+func (r *SprintRepository) SetIssueSprint(issueIDs []uint, sprintID *uint) error {
+	return r.db.Model(&models.Issue{}).Where("id IN ?", issueIDs).Update("sprint_id", sprintID).Error
+}
+
+// Close saves a closed sprint, moves its unfinished issues to the rollover
+// sprint (or the backlog when sprintID is nil) and records the scope changes,
+// in one transaction
+func (r *SprintRepository) Close(sprint *models.Sprint, issueIDs []uint, sprintID *uint, changes []models.SprintScopeChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(issueIDs) > 0 {
+			if err := tx.Model(&models.Issue{}).Where("id IN ?", issueIDs).Update("sprint_id", sprintID).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes) > 0 {
+			if err := tx.Create(&changes).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Issues", "Team", "Creator").Save(sprint).Error
+	})
+}
+
+func (r *SprintRepository) CreateScopeChange(change *models.SprintScopeChange) error {
+	return r.db.Create(change).Error
+}
+
+func (r *SprintRepository) GetScopeChanges(sprintID uint) ([]models.SprintScopeChange, error) {
+	var changes []models.SprintScopeChange
+	err := r.db.Preload("Issue").Preload("ChangedByUser").
+		Where("sprint_id = ?", sprintID).Order("changed_at ASC, id ASC").Find(&changes).Error
+	return changes, err
+}
+
+// FindScopeIssues returns every issue that has ever been part of the sprint
+func (r *SprintRepository) FindScopeIssues(sprintID uint) ([]models.Issue, error) {
+	var issues []models.Issue
+	err := r.db.Where("id IN (?) OR sprint_id = ?",
+		r.db.Model(&models.SprintScopeChange{}).Select("issue_id").Where("sprint_id = ?", sprintID), sprintID).
+		Find(&issues).Error
+	return issues, err
+}
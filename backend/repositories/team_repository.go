@@ -33,8 +33,24 @@ func (r *TeamRepository) FindByOrganization(orgID uint) ([]models.Team, error) {
 	return teams, err
 }
 
+// Update saves the team details; assignment settings have their own update
 func (r *TeamRepository) Update(team *models.Team) error {
-	return r.db.Save(team).Error
+	return r.db.Omit("SingleAssignee", "AutoAssign", "AutoAssignCursor", "MeetingConflicts").Save(team).Error
+}
+
+func (r *TeamRepository) UpdateAssignmentSettings(teamID uint, singleAssignee bool, strategy models.AutoAssignStrategy) error {
+	return r.db.Model(&models.Team{}).Where("id = ?", teamID).Updates(map[string]interface{}{
+		"single_assignee":      singleAssignee,
+		"auto_assign_strategy": strategy,
+	}).Error
+}
+
+func (r *TeamRepository) UpdateMeetingConflictPolicy(teamID uint, policy models.MeetingConflictPolicy) error {
+	return r.db.Model(&models.Team{}).Where("id = ?", teamID).Update("meeting_conflict_policy", policy).Error
+}
+
+func (r *TeamRepository) UpdateAutoAssignCursor(teamID, userID uint) error {
+	return r.db.Model(&models.Team{}).Where("id = ?", teamID).Update("auto_assign_last_user_id", userID).Error
 }
 
 func (r *TeamRepository) Delete(id uint) error {
@@ -63,3 +79,29 @@ func (r *TeamRepository) GetMembers(teamID uint) ([]models.TeamMember, error) {
 	err := r.db.Preload("User").Where("team_id = ?", teamID).Find(&members).Error
 	return members, err
 }
+
+// FindManagedTeamIDs returns the IDs of the teams the user manages
+func (r *TeamRepository) FindManagedTeamIDs(userID uint) ([]uint, error) {
+	var teamIDs []uint
+	err := r.db.Model(&models.TeamMember{}).Where("user_id = ? AND role = ?", userID, models.RoleManager).
+		Pluck("team_id", &teamIDs).Error
+	return teamIDs, err
+}
+
+// FindManagerIDs returns the IDs of the managers of any team the user is a
+// member of, excluding the user
+func (r *TeamRepository) FindManagerIDs(userID uint) ([]uint, error) {
+	var managerIDs []uint
+	err := r.db.Model(&models.TeamMember{}).
+		Where("role = ? AND user_id <> ?", models.RoleManager, userID).
+		Where("team_id IN (?)", r.db.Model(&models.TeamMember{}).Select("team_id").Where("user_id = ?", userID)).
+		Distinct().Pluck("user_id", &managerIDs).Error
+	return managerIDs, err
+}
+
+// FindTeamIDsByUser returns the IDs of all teams the user is a member of
+func (r *TeamRepository) FindTeamIDsByUser(userID uint) ([]uint, error) {
+	var teamIDs []uint
+	err := r.db.Model(&models.TeamMember{}).Where("user_id = ?", userID).Pluck("team_id", &teamIDs).Error
+	return teamIDs, err
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+)
+
+type TimerRepository struct {
+	db *gorm.DB
+}
+
+func NewTimerRepository(db *gorm.DB) *TimerRepository {
+	return &TimerRepository{db: db}
+}
+
+func (r *TimerRepository) Create(timer *models.WorkTimer) error {
+	return r.db.Create(timer).Error
+}
+
+func (r *TimerRepository) FindByID(id uint) (*models.WorkTimer, error) {
+	var timer models.WorkTimer
+	err := r.db.Preload("Issue").First(&timer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &timer, nil
+}
+
+func (r *TimerRepository) FindByUser(userID uint) ([]models.WorkTimer, error) {
+	var timers []models.WorkTimer
+	err := r.db.Preload("Issue").Where("user_id = ?", userID).Order("created_at").Find(&timers).Error
+	return timers, err
+}
+
+func (r *TimerRepository) FindByUserAndIssue(userID, issueID uint) (*models.WorkTimer, error) {
+	var timer models.WorkTimer
+	err := r.db.Where("user_id = ? AND issue_id = ?", userID, issueID).First(&timer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &timer, nil
+}
+
+// CountRunning counts the user's running timers, excluding one timer
+func (r *TimerRepository) CountRunning(userID, exceptID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.WorkTimer{}).
+		Where("user_id = ? AND state = ? AND id <> ?", userID, models.TimerRunning, exceptID).
+		Count(&count).Error
+	return count, err
+}
+
+// FindAllRunning returns every running timer with its issue
+func (r *TimerRepository) FindAllRunning() ([]models.WorkTimer, error) {
+	var timers []models.WorkTimer
+	err := r.db.Preload("Issue").Where("state = ?", models.TimerRunning).Find(&timers).Error
+	return timers, err
+}
+
+func (r *TimerRepository) Update(timer *models.WorkTimer) error {
+	return r.db.Omit("Issue").Save(timer).Error
+}
+
+func (r *TimerRepository) Delete(id uint) error {
+	return r.db.Delete(&models.WorkTimer{}, id).Error
+}
+
+// Complete replaces a stopped timer with its work log and records the issue
+// activity built for the saved log, in one transaction
+func (r *TimerRepository) Complete(timer *models.WorkTimer, log *models.IssueWorkLog, activity func() (*models.IssueActivity, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.WorkTimer{}, timer.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		// Someone else stopped it first
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Create(log).Error; err != nil {
+			return err
+		}
+		return createActivity(tx, activity)
+	})
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"task-management/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TimesheetRepository struct {
+	db *gorm.DB
+}
+
+func NewTimesheetRepository(db *gorm.DB) *TimesheetRepository {
+	return &TimesheetRepository{db: db}
+}
+
+func (r *TimesheetRepository) FindByID(id uint) (*models.Timesheet, error) {
+	var timesheet models.Timesheet
+	err := r.db.Preload("User").Preload("Reviewer").First(&timesheet, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &timesheet, nil
+}
+
+func (r *TimesheetRepository) FindByUserAndWeek(userID uint, weekStart time.Time) (*models.Timesheet, error) {
+	var timesheet models.Timesheet
+	err := r.db.Preload("Reviewer").
+		Where("user_id = ? AND week_start = ?", userID, weekStart.Format("2006-01-02")).
+		First(&timesheet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &timesheet, nil
+}
+
+// FindByUsers lists the timesheets of the users with the given status whose
+// week starts within the range, oldest week first
+func (r *TimesheetRepository) FindByUsers(userIDs []uint, status models.TimesheetStatus, from, to time.Time) ([]models.Timesheet, error) {
+	var timesheets []models.Timesheet
+	if len(userIDs) == 0 {
+		return timesheets, nil
+	}
+	err := r.db.Preload("User").Preload("Reviewer").
+		Where("user_id IN ? AND status = ? AND week_start BETWEEN ? AND ?",
+			userIDs, status, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("week_start, user_id").
+		Find(&timesheets).Error
+	return timesheets, err
+}
+
+// Save creates or updates a timesheet
+func (r *TimesheetRepository) Save(timesheet *models.Timesheet) error {
+	return r.db.Omit("User", "Reviewer").Save(timesheet).Error
+}
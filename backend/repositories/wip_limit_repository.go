@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"task-management/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WIPLimitRepository struct {
+	db *gorm.DB
+}
+
+func NewWIPLimitRepository(db *gorm.DB) *WIPLimitRepository {
+	return &WIPLimitRepository{db: db}
+}
+
+func (r *WIPLimitRepository) FindByTeam(teamID uint) ([]models.TeamWIPLimit, error) {
+	var limits []models.TeamWIPLimit
+	err := r.db.Preload("Status").Where("team_id = ?", teamID).Find(&limits).Error
+	return limits, err
+}
+
+func (r *WIPLimitRepository) FindByTeamAndStatus(teamID, statusID uint) (*models.TeamWIPLimit, error) {
+	var limit models.TeamWIPLimit
+	err := r.db.Where("team_id = ? AND status_id = ?", teamID, statusID).First(&limit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &limit, nil
+}
+
+// Upsert creates or replaces the limit for a team/status pair
+func (r *WIPLimitRepository) Upsert(limit *models.TeamWIPLimit) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_id"}, {Name: "status_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_issues", "is_strict", "updated_at"}),
+	}).Create(limit).Error
+}
+
+func (r *WIPLimitRepository) Delete(teamID, statusID uint) error {
+	return r.db.Where("team_id = ? AND status_id = ?", teamID, statusID).Delete(&models.TeamWIPLimit{}).Error
+}
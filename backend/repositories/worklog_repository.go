@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"task-management/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WorkLogRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkLogRepository(db *gorm.DB) *WorkLogRepository {
+	return &WorkLogRepository{db: db}
+}
+
+// Create saves a work log and records the issue activity built for the saved
+// log, in one transaction
+func (r *WorkLogRepository) Create(log *models.IssueWorkLog, activity func() (*models.IssueActivity, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(log).Error; err != nil {
+			return err
+		}
+		return createActivity(tx, activity)
+	})
+}
+
+func (r *WorkLogRepository) FindByID(id uint) (*models.IssueWorkLog, error) {
+	var log models.IssueWorkLog
+	err := r.db.Preload("User").First(&log, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+func (r *WorkLogRepository) FindByIssue(issueID uint) ([]models.IssueWorkLog, error) {
+	var logs []models.IssueWorkLog
+	err := r.db.Preload("User").Where("issue_id = ?", issueID).Order("work_date DESC, id DESC").Find(&logs).Error
+	return logs, err
+}
+
+// Update saves a corrected work log and records its issue activity, in one
+// transaction
+func (r *WorkLogRepository) Update(log *models.IssueWorkLog, activity func() (*models.IssueActivity, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Issue", "User").Save(log).Error; err != nil {
+			return err
+		}
+		return createActivity(tx, activity)
+	})
+}
+
+// Delete removes a work log and records its issue activity, in one
+// transaction
+func (r *WorkLogRepository) Delete(id uint, activity func() (*models.IssueActivity, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.IssueWorkLog{}, id).Error; err != nil {
+			return err
+		}
+		return createActivity(tx, activity)
+	})
+}
+
+// createActivity records the issue activity of a change made in the
+// transaction
+func createActivity(tx *gorm.DB, activity func() (*models.IssueActivity, error)) error {
+	entry, err := activity()
+	if err != nil {
+		return err
+	}
+	return tx.Create(entry).Error
+}
+
+// FindByUserBetween returns a user's work logs in a date range with their issues
+func (r *WorkLogRepository) FindByUserBetween(userID uint, from, to time.Time) ([]models.IssueWorkLog, error) {
+	var logs []models.IssueWorkLog
+	err := r.db.Preload("Issue").
+		Where("user_id = ? AND work_date BETWEEN ? AND ?", userID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("work_date, id").
+		Find(&logs).Error
+	return logs, err
+}
+
+// SumMinutes totals the minutes a user logged on a day across all issues,
+// leaving out one work log (0 for none)
+func (r *WorkLogRepository) SumMinutes(userID uint, workDate time.Time, exceptID uint) (int, error) {
+	var total int
+	err := r.db.Model(&models.IssueWorkLog{}).
+		Select("COALESCE(SUM(minutes_spent), 0)").
+		Where("user_id = ? AND work_date = ? AND id <> ?", userID, workDate.Format("2006-01-02"), exceptID).
+		Scan(&total).Error
+	return total, err
+}
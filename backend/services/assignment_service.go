@@ -1,27 +1,34 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"task-management/models"
 	"task-management/repositories"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type AssignmentService struct {
-	assignmentRepo *repositories.AssignmentRepository
-	issueRepo      *repositories.IssueRepository
-	userRepo       *repositories.UserRepository
+	assignmentRepo  *repositories.AssignmentRepository
+	issueRepo       *repositories.IssueRepository
+	userRepo        *repositories.UserRepository
+	capacityService *CapacityService
 }
 
 func NewAssignmentService(
 	assignmentRepo *repositories.AssignmentRepository,
 	issueRepo *repositories.IssueRepository,
 	userRepo *repositories.UserRepository,
+	capacityService *CapacityService,
 ) *AssignmentService {
 	return &AssignmentService{
-		assignmentRepo: assignmentRepo,
-		issueRepo:      issueRepo,
-		userRepo:       userRepo,
+		assignmentRepo:  assignmentRepo,
+		issueRepo:       issueRepo,
+		userRepo:        userRepo,
+		capacityService: capacityService,
 	}
 }
 
@@ -30,57 +37,315 @@ type AssignmentRequest struct {
 	UserID    uint      `json:"user_id" binding:"required"`
 	StartDate time.Time `json:"start_date" binding:"required"`
 	EndDate   time.Time `json:"end_date" binding:"required"`
+	// Role defaults to owner
+	Role models.AssignmentRole `json:"role"`
+	// EstimatedMinutes is the work planned for the whole assignment
+	EstimatedMinutes *int `json:"estimated_minutes"`
+	// Force assigns even when the user would be over capacity
+	Force bool `json:"force"`
+	// Set by AutoAssignService to log how the assignee was chosen
+	Strategy models.AutoAssignStrategy `json:"-"`
+	Reason   string                    `json:"-"`
+}
+
+// ReassignRequest hands an assignment over to another user. The dates and
+// estimate default to those of the current assignment, starting no earlier
+// than today.
+type ReassignRequest struct {
+	UserID           uint       `json:"user_id" binding:"required"`
+	HandoverNote     string     `json:"handover_note"`
+	StartDate        *time.Time `json:"start_date"`
+	EndDate          *time.Time `json:"end_date"`
+	EstimatedMinutes *int       `json:"estimated_minutes"`
+	Force            bool       `json:"force"`
+}
+
+// AssignmentTimeline is every assignment an issue has had, oldest first, with
+// the activity recorded for each change
+type AssignmentTimeline struct {
+	IssueID     uint                     `json:"issue_id"`
+	Assignments []models.IssueAssignment `json:"assignments"`
+	Activities  []models.IssueActivity   `json:"activities"`
+}
+
+func validAssignmentRole(role models.AssignmentRole) bool {
+	switch role {
+	case models.AssignmentOwner, models.AssignmentReviewer, models.AssignmentQA:
+		return true
+	}
+	return false
+}
+
+// assignmentActivity builds the activity log entry of an assignment change
+func assignmentActivity(activityType models.ActivityType, assignment *models.IssueAssignment, userID uint, description string, extra map[string]interface{}) (*models.IssueActivity, error) {
+	metadata := map[string]interface{}{
+		"assignment_id": assignment.ID,
+		"user_id":       assignment.UserID,
+		"role":          assignment.Role,
+	}
+	for key, value := range extra {
+		metadata[key] = value
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	raw := string(encoded)
+
+	return &models.IssueActivity{
+		IssueID:      assignment.IssueID,
+		UserID:       &userID,
+		ActivityType: activityType,
+		Description:  description,
+		Metadata:     &raw,
+	}, nil
+}
+
+// recordAssignmentActivity adds an assignment change to the issue's activity log
+func (s *AssignmentService) recordAssignmentActivity(activityType models.ActivityType, assignment *models.IssueAssignment, userID uint, description string, extra map[string]interface{}) error {
+	activity, err := assignmentActivity(activityType, assignment, userID, description, extra)
+	if err != nil {
+		return err
+	}
+	return s.issueRepo.CreateActivity(activity)
 }
 
-func (s *AssignmentService) Assign(req *AssignmentRequest, assignedBy uint) error {
+// checkCapacity runs the capacity check of a new assignment, rejecting it with
+// ErrOverCapacity when it overloads the user and is not forced
+func (s *AssignmentService) checkCapacity(assignment *models.IssueAssignment, force bool) (*CapacityCheck, error) {
+	warning, err := s.capacityService.Check(assignment)
+	if err != nil {
+		return nil, err
+	}
+	if warning != nil && warning.Overloaded() && !force {
+		return warning, ErrOverCapacity
+	}
+	return warning, nil
+}
+
+// Assign creates an assignment. A non-nil CapacityCheck is returned when the
+// assignment puts the user over capacity or the user is on leave during it.
+// Going over capacity is rejected with ErrOverCapacity unless forced.
+// In single-assignee teams the new assignee replaces the active one in the
+// same role.
+func (s *AssignmentService) Assign(req *AssignmentRequest, assignedBy uint) (*models.IssueAssignment, *CapacityCheck, error) {
 	// Verify issue exists
-	_, err := s.issueRepo.FindByID(req.IssueID)
+	issue, err := s.issueRepo.FindByID(req.IssueID)
 	if err != nil {
-		return errors.New("issue not found")
+		return nil, nil, errors.New("issue not found")
 	}
 
 	// Verify user exists
-	_, err = s.userRepo.FindByID(req.UserID)
+	user, err := s.userRepo.FindByID(req.UserID)
 	if err != nil {
-		return errors.New("user not found")
+		return nil, nil, errors.New("user not found")
 	}
 
 	// Validate dates
 	if req.EndDate.Before(req.StartDate) {
-		return errors.New("end date must be after start date")
+		return nil, nil, errors.New("end date must be after start date")
+	}
+	if req.EstimatedMinutes != nil && *req.EstimatedMinutes <= 0 {
+		return nil, nil, errors.New("estimated minutes must be positive")
+	}
+	if req.Role == "" {
+		req.Role = models.AssignmentOwner
+	}
+	if !validAssignmentRole(req.Role) {
+		return nil, nil, errors.New("role must be owner, reviewer or qa")
 	}
 
-	// Deactivate old assignments (optional: keep history)
-	// s.assignmentRepo.DeactivateOldAssignments(req.IssueID)
+	active, err := s.assignmentRepo.FindActiveByIssue(req.IssueID, req.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, existing := range active {
+		if existing.UserID == req.UserID {
+			return nil, nil, errors.New("user is already assigned to this issue in this role")
+		}
+	}
+	var replaced []models.IssueAssignment
+	if issue.Team.SingleAssignee {
+		replaced = active
+	}
 
 	// Create new assignment
 	assignment := &models.IssueAssignment{
 		IssueID:    req.IssueID,
 		UserID:     req.UserID,
+		Role:       req.Role,
 		StartDate:  req.StartDate,
 		EndDate:    req.EndDate,
+		Estimate:   req.EstimatedMinutes,
 		AssignedBy: &assignedBy,
 		IsActive:   true,
 	}
 
-	if err := s.assignmentRepo.Create(assignment); err != nil {
-		return err
+	warning, err := s.checkCapacity(assignment, req.Force)
+	if err != nil {
+		return nil, warning, err
+	}
+
+	if err := s.assignmentRepo.Replace(replaced, assignedBy, assignment); err != nil {
+		return nil, nil, err
 	}
 
 	// Log activity
-	activity := &models.IssueActivity{
-		IssueID:      req.IssueID,
-		UserID:       &assignedBy,
-		ActivityType: models.ActivityAssigned,
-		Description:  "Issue assigned",
+	for i := range replaced {
+		description := fmt.Sprintf("%s replaced %s as %s", user.FullName, replaced[i].User.FullName, req.Role)
+		if err := s.recordAssignmentActivity(models.ActivityReassigned, assignment, assignedBy, description, map[string]interface{}{
+			"previous_assignment_id": replaced[i].ID,
+			"previous_user_id":       replaced[i].UserID,
+		}); err != nil {
+			return nil, nil, err
+		}
 	}
-	return s.issueRepo.CreateActivity(activity)
+	if len(replaced) == 0 {
+		description := fmt.Sprintf("Assigned %s as %s", user.FullName, req.Role)
+		var extra map[string]interface{}
+		if req.Strategy != "" {
+			description = fmt.Sprintf("Auto-assigned %s as %s: %s", user.FullName, req.Role, req.Reason)
+			extra = map[string]interface{}{"strategy": req.Strategy, "reason": req.Reason}
+		}
+		if err := s.recordAssignmentActivity(models.ActivityAssigned, assignment, assignedBy, description, extra); err != nil {
+			return nil, nil, err
+		}
+	}
+	return assignment, warning, nil
+}
+
+// Unassign ends an active assignment
+func (s *AssignmentService) Unassign(assignment *models.IssueAssignment, unassignedBy uint) error {
+	if !assignment.IsActive {
+		return errors.New("assignment is not active")
+	}
+	if err := s.assignmentRepo.End(assignment, unassignedBy); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("assignment is not active")
+		}
+		return err
+	}
+	description := fmt.Sprintf("Unassigned %s as %s", assignment.User.FullName, assignment.Role)
+	return s.recordAssignmentActivity(models.ActivityUnassigned, assignment, unassignedBy, description, nil)
+}
+
+// Reassign ends an active assignment and hands it over to another user in the
+// same role, with the same capacity rules as Assign
+func (s *AssignmentService) Reassign(assignment *models.IssueAssignment, req *ReassignRequest, reassignedBy uint) (*models.IssueAssignment, *CapacityCheck, error) {
+	if !assignment.IsActive {
+		return nil, nil, errors.New("assignment is not active")
+	}
+	if req.UserID == assignment.UserID {
+		return nil, nil, errors.New("issue is already assigned to this user")
+	}
+	user, err := s.userRepo.FindByID(req.UserID)
+	if err != nil {
+		return nil, nil, errors.New("user not found")
+	}
+
+	active, err := s.assignmentRepo.FindActiveByIssue(assignment.IssueID, assignment.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, existing := range active {
+		if existing.UserID == req.UserID {
+			return nil, nil, errors.New("user is already assigned to this issue in this role")
+		}
+	}
+
+	startDate := assignment.StartDate
+	if today := calendarDay(time.Now()); today.After(startDate) {
+		startDate = today
+	}
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+	endDate := assignment.EndDate
+	if req.EndDate != nil {
+		endDate = *req.EndDate
+	}
+	if endDate.Before(startDate) {
+		return nil, nil, errors.New("end date must be after start date")
+	}
+	estimate := assignment.Estimate
+	if req.EstimatedMinutes != nil {
+		if *req.EstimatedMinutes <= 0 {
+			return nil, nil, errors.New("estimated minutes must be positive")
+		}
+		estimate = req.EstimatedMinutes
+	}
+
+	replacement := &models.IssueAssignment{
+		IssueID:      assignment.IssueID,
+		UserID:       req.UserID,
+		Role:         assignment.Role,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Estimate:     estimate,
+		AssignedBy:   &reassignedBy,
+		IsActive:     true,
+		HandoverNote: req.HandoverNote,
+	}
+
+	warning, err := s.checkCapacity(replacement, req.Force)
+	if err != nil {
+		return nil, warning, err
+	}
+
+	if err := s.assignmentRepo.Replace([]models.IssueAssignment{*assignment}, reassignedBy, replacement); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("assignment is not active")
+		}
+		return nil, nil, err
+	}
+
+	description := fmt.Sprintf("Reassigned from %s to %s as %s", assignment.User.FullName, user.FullName, assignment.Role)
+	if err := s.recordAssignmentActivity(models.ActivityReassigned, replacement, reassignedBy, description, map[string]interface{}{
+		"previous_assignment_id": assignment.ID,
+		"previous_user_id":       assignment.UserID,
+		"handover_note":          req.HandoverNote,
+	}); err != nil {
+		return nil, nil, err
+	}
+	return replacement, warning, nil
+}
+
+func (s *AssignmentService) GetByID(id uint) (*models.IssueAssignment, error) {
+	return s.assignmentRepo.FindByID(id)
 }
 
 func (s *AssignmentService) GetByIssue(issueID uint) ([]models.IssueAssignment, error) {
 	return s.assignmentRepo.FindByIssue(issueID)
 }
 
+// GetTimeline returns the assignment history of the issue, oldest first
+func (s *AssignmentService) GetTimeline(issueID uint) (*AssignmentTimeline, error) {
+	assignments, err := s.assignmentRepo.FindByIssue(issueID)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := s.issueRepo.GetActivities(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &AssignmentTimeline{
+		IssueID:     issueID,
+		Assignments: make([]models.IssueAssignment, 0, len(assignments)),
+		Activities:  []models.IssueActivity{},
+	}
+	for i := len(assignments) - 1; i >= 0; i-- {
+		timeline.Assignments = append(timeline.Assignments, assignments[i])
+	}
+	for i := len(activities) - 1; i >= 0; i-- {
+		switch activities[i].ActivityType {
+		case models.ActivityAssigned, models.ActivityUnassigned, models.ActivityReassigned:
+			timeline.Activities = append(timeline.Activities, activities[i])
+		}
+	}
+	return timeline, nil
+}
+
 func (s *AssignmentService) GetActiveByUser(userID uint) ([]models.IssueAssignment, error) {
 	return s.assignmentRepo.FindActiveByUser(userID)
 }
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+// autoAssignDays is the length in business days of automatic assignments for
+// issues without a deadline
+const autoAssignDays = 5
+
+type AutoAssignService struct {
+	issueRepo         *repositories.IssueRepository
+	teamRepo          *repositories.TeamRepository
+	assignmentRepo    *repositories.AssignmentRepository
+	labelRepo         *repositories.LabelRepository
+	leaveService      *LeaveService
+	holidayService    *HolidayService
+	assignmentService *AssignmentService
+}
+
+func NewAutoAssignService(
+	issueRepo *repositories.IssueRepository,
+	teamRepo *repositories.TeamRepository,
+	assignmentRepo *repositories.AssignmentRepository,
+	labelRepo *repositories.LabelRepository,
+	leaveService *LeaveService,
+	holidayService *HolidayService,
+	assignmentService *AssignmentService,
+) *AutoAssignService {
+	return &AutoAssignService{
+		issueRepo:         issueRepo,
+		teamRepo:          teamRepo,
+		assignmentRepo:    assignmentRepo,
+		labelRepo:         labelRepo,
+		leaveService:      leaveService,
+		holidayService:    holidayService,
+		assignmentService: assignmentService,
+	}
+}
+
+// candidate is a team member who can be picked, with their open work
+type candidate struct {
+	user        *models.User
+	assignments int
+	minutes     int
+}
+
+func (c *candidate) load() string {
+	return fmt.Sprintf("%d open assignments, %d estimated minutes", c.assignments, c.minutes)
+}
+
+func ValidAutoAssignStrategy(strategy models.AutoAssignStrategy) bool {
+	switch strategy {
+	case models.AutoAssignNone, models.AutoAssignRoundRobin, models.AutoAssignLeastLoaded, models.AutoAssignSkills:
+		return true
+	}
+	return false
+}
+
+// UpdateSettings changes how issues of the team are assigned
+func (s *AutoAssignService) UpdateSettings(teamID uint, singleAssignee bool, strategy models.AutoAssignStrategy) (*models.Team, error) {
+	if !ValidAutoAssignStrategy(strategy) {
+		return nil, errors.New("auto_assign_strategy must be none, round_robin, least_loaded or skills")
+	}
+	if err := s.teamRepo.UpdateAssignmentSettings(teamID, singleAssignee, strategy); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.FindByID(teamID)
+}
+
+// candidates returns the members who do the team's work, excluding
+// stakeholders and anyone on approved leave today, ordered by user ID
+func (s *AutoAssignService) candidates(teamID uint, today time.Time) ([]*candidate, error) {
+	members, err := s.teamRepo.GetMembers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []uint
+	for _, member := range members {
+		if member.Role != models.RoleStakeholder {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	leaves, err := s.leaveService.ApprovedBetween(userIDs, today, today)
+	if err != nil {
+		return nil, err
+	}
+	away := make(map[uint]bool)
+	for _, leave := range leaves {
+		away[leave.UserID] = true
+	}
+
+	byUser := make(map[uint]*candidate)
+	var result []*candidate
+	for i := range members {
+		if members[i].Role == models.RoleStakeholder || away[members[i].UserID] {
+			continue
+		}
+		c := &candidate{user: &members[i].User}
+		byUser[members[i].UserID] = c
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].user.ID < result[j].user.ID })
+
+	open, err := s.assignmentRepo.FindOpenByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range open {
+		if c, ok := byUser[assignment.UserID]; ok {
+			c.assignments++
+			if assignment.Estimate != nil {
+				c.minutes += *assignment.Estimate
+			}
+		}
+	}
+	return result, nil
+}
+
+// leastLoaded picks the candidate with the fewest estimated minutes of open
+// work, then the fewest open assignments
+func leastLoaded(candidates []*candidate) *candidate {
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.minutes < best.minutes || (c.minutes == best.minutes && c.assignments < best.assignments) {
+			best = c
+		}
+	}
+	return best
+}
+
+// nextInRotation picks the first candidate after the one picked last time,
+// wrapping around
+func nextInRotation(candidates []*candidate, last *uint) *candidate {
+	if last != nil {
+		for _, c := range candidates {
+			if c.user.ID > *last {
+				return c
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// bestSkillMatch picks the candidate whose skills cover most of the labels,
+// breaking ties by load, and names the matching labels. It returns nil when
+// nobody has any of the skills.
+func (s *AutoAssignService) bestSkillMatch(candidates []*candidate, labels []models.Label) (*candidate, []string, error) {
+	userIDs := make([]uint, len(candidates))
+	for i, c := range candidates {
+		userIDs[i] = c.user.ID
+	}
+	skills, err := s.labelRepo.FindSkillIDs(userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matches := make(map[uint][]string)
+	most := 0
+	for _, c := range candidates {
+		for _, label := range labels {
+			for _, skillID := range skills[c.user.ID] {
+				if skillID == label.ID {
+					matches[c.user.ID] = append(matches[c.user.ID], label.Name)
+				}
+			}
+		}
+		if len(matches[c.user.ID]) > most {
+			most = len(matches[c.user.ID])
+		}
+	}
+	if most == 0 {
+		return nil, nil, nil
+	}
+
+	var best []*candidate
+	for _, c := range candidates {
+		if len(matches[c.user.ID]) == most {
+			best = append(best, c)
+		}
+	}
+	picked := leastLoaded(best)
+	return picked, matches[picked.user.ID], nil
+}
+
+// pick chooses an assignee with the strategy and explains why
+func (s *AutoAssignService) pick(strategy models.AutoAssignStrategy, team *models.Team, issue *models.Issue, candidates []*candidate) (*candidate, string, error) {
+	switch strategy {
+	case models.AutoAssignRoundRobin:
+		return nextInRotation(candidates, team.AutoAssignCursor), "next in the round robin rotation", nil
+	case models.AutoAssignLeastLoaded:
+		c := leastLoaded(candidates)
+		return c, "least open work: " + c.load(), nil
+	case models.AutoAssignSkills:
+		c, matches, err := s.bestSkillMatch(candidates, issue.Labels)
+		if err != nil {
+			return nil, "", err
+		}
+		if c != nil {
+			return c, "skills match " + strings.Join(matches, ", "), nil
+		}
+		c = leastLoaded(candidates)
+		return c, "no member has the skills for the issue labels; least open work: " + c.load(), nil
+	}
+	return nil, "", errors.New("team has no auto assignment strategy")
+}
+
+// AutoAssign assigns an issue without an owner to a member picked by the
+// team's strategy, as if assigned by the given user. It returns nil when the
+// team does not assign automatically or nobody is available.
+func (s *AutoAssignService) AutoAssign(issueID, assignedBy uint) (*models.IssueAssignment, error) {
+	issue, err := s.issueRepo.FindByID(issueID)
+	if err != nil {
+		return nil, errors.New("issue not found")
+	}
+	strategy := issue.Team.AutoAssign
+	if strategy == "" || strategy == models.AutoAssignNone {
+		return nil, nil
+	}
+	for _, assignment := range issue.Assignments {
+		if assignment.IsActive && assignment.Role == models.AssignmentOwner {
+			return nil, nil
+		}
+	}
+
+	today := calendarDay(time.Now())
+	candidates, err := s.candidates(issue.TeamID, today)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	picked, reason, err := s.pick(strategy, &issue.Team, issue, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Work on the issue until its deadline, or for a business week
+	endDate := today
+	if issue.Deadline != nil && !calendarDay(*issue.Deadline).Before(today) {
+		endDate = calendarDay(*issue.Deadline)
+	} else {
+		endDate, err = s.holidayService.AddTeamBusinessDays(issue.TeamID, today, autoAssignDays-1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req := &AssignmentRequest{
+		IssueID:   issue.ID,
+		UserID:    picked.user.ID,
+		StartDate: today,
+		EndDate:   endDate,
+		Role:      models.AssignmentOwner,
+		// Capacity is reported by the workload view rather than blocking
+		// issue creation
+		Force:    true,
+		Strategy: strategy,
+		Reason:   reason,
+	}
+	assignment, _, err := s.assignmentService.Assign(req, assignedBy)
+	if err != nil {
+		return nil, err
+	}
+	if strategy == models.AutoAssignRoundRobin {
+		if err := s.teamRepo.UpdateAutoAssignCursor(issue.TeamID, picked.user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return assignment, nil
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"task-management/models"
+	"task-management/repositories"
+)
+
+type BoardService struct {
+	issueRepo    *repositories.IssueRepository
+	statusRepo   *repositories.StatusRepository
+	teamRepo     *repositories.TeamRepository
+	wipLimitRepo *repositories.WIPLimitRepository
+}
+
+func NewBoardService(
+	issueRepo *repositories.IssueRepository,
+	statusRepo *repositories.StatusRepository,
+	teamRepo *repositories.TeamRepository,
+	wipLimitRepo *repositories.WIPLimitRepository,
+) *BoardService {
+	return &BoardService{
+		issueRepo:    issueRepo,
+		statusRepo:   statusRepo,
+		teamRepo:     teamRepo,
+		wipLimitRepo: wipLimitRepo,
+	}
+}
+
+type BoardColumn struct {
+	Status    *models.IssueStatus  `json:"status"`
+	Issues    []models.Issue       `json:"issues"`
+	Count     int                  `json:"count"`
+	WIPLimit  *models.TeamWIPLimit `json:"wip_limit,omitempty"`
+	OverLimit bool                 `json:"over_limit"`
+}
+
+type Board struct {
+	TeamID  uint          `json:"team_id"`
+	Columns []BoardColumn `json:"columns"`
+	Total   int           `json:"total"`
+}
+
+type WIPLimitRequest struct {
+	StatusID  uint `json:"status_id" binding:"required"`
+	MaxIssues int  `json:"max_issues" binding:"required,min=1"`
+	IsStrict  bool `json:"is_strict"`
+}
+
+// GetBoard returns the team's issues grouped into columns ordered by
+// IssueStatus.Position. Issues without a status are put in a leading column
+// with a nil status.
+func (s *BoardService) GetBoard(teamID uint) (*Board, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, errors.New("team not found")
+	}
+
+	statuses, err := s.statusRepo.FindByOrganization(team.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := s.issueRepo.FindByTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := s.wipLimitRepo.FindByTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	limitByStatus := make(map[uint]*models.TeamWIPLimit, len(limits))
+	for i := range limits {
+		limitByStatus[limits[i].StatusID] = &limits[i]
+	}
+
+	board := &Board{TeamID: teamID, Columns: []BoardColumn{}, Total: len(issues)}
+	columnIndex := make(map[uint]int, len(statuses))
+
+	noStatus := BoardColumn{Issues: []models.Issue{}}
+	for i := range statuses {
+		columnIndex[statuses[i].ID] = len(board.Columns)
+		board.Columns = append(board.Columns, BoardColumn{
+			Status:   &statuses[i],
+			Issues:   []models.Issue{},
+			WIPLimit: limitByStatus[statuses[i].ID],
+		})
+	}
+
+	// Issues are already in rank order
+	for _, issue := range issues {
+		if issue.StatusID == nil {
+			noStatus.Issues = append(noStatus.Issues, issue)
+			continue
+		}
+		idx, ok := columnIndex[*issue.StatusID]
+		if !ok {
+			noStatus.Issues = append(noStatus.Issues, issue)
+			continue
+		}
+		board.Columns[idx].Issues = append(board.Columns[idx].Issues, issue)
+	}
+
+	for i := range board.Columns {
+		col := &board.Columns[i]
+		col.Count = len(col.Issues)
+		col.OverLimit = col.WIPLimit != nil && col.Count > col.WIPLimit.MaxIssues
+	}
+
+	if len(noStatus.Issues) > 0 {
+		noStatus.Count = len(noStatus.Issues)
+		board.Columns = append([]BoardColumn{noStatus}, board.Columns...)
+	}
+
+	return board, nil
+}
+
+func (s *BoardService) GetWIPLimits(teamID uint) ([]models.TeamWIPLimit, error) {
+	return s.wipLimitRepo.FindByTeam(teamID)
+}
+
+func (s *BoardService) SetWIPLimit(teamID uint, req *WIPLimitRequest) (*models.TeamWIPLimit, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, errors.New("team not found")
+	}
+
+	status, err := s.statusRepo.FindByID(req.StatusID)
+	if err != nil || status.OrganizationID != team.OrganizationID {
+		return nil, errors.New("status not found")
+	}
+
+	limit := &models.TeamWIPLimit{
+		TeamID:    teamID,
+		StatusID:  req.StatusID,
+		MaxIssues: req.MaxIssues,
+		IsStrict:  req.IsStrict,
+	}
+	if err := s.wipLimitRepo.Upsert(limit); err != nil {
+		return nil, err
+	}
+	return s.wipLimitRepo.FindByTeamAndStatus(teamID, req.StatusID)
+}
+
+func (s *BoardService) DeleteWIPLimit(teamID, statusID uint) error {
+	return s.wipLimitRepo.Delete(teamID, statusID)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"task-management/models"
+	"time"
+)
+
+// defaultWorkingDays is Monday to Friday
+var defaultWorkingDays = models.Weekdays{1, 2, 3, 4, 5}
+
+// BusinessCalendar knows the days an organization works: its working days of
+// the week, less the holidays of its calendars within the loaded range
+type BusinessCalendar struct {
+	workingDays map[time.Weekday]bool
+	holidays    map[string]string
+}
+
+func newBusinessCalendar(workingDays models.Weekdays, holidays []models.Holiday) *BusinessCalendar {
+	if len(workingDays) == 0 {
+		workingDays = defaultWorkingDays
+	}
+	calendar := &BusinessCalendar{
+		workingDays: make(map[time.Weekday]bool, len(workingDays)),
+		holidays:    make(map[string]string, len(holidays)),
+	}
+	for _, day := range workingDays {
+		calendar.workingDays[time.Weekday(day)] = true
+	}
+	for _, holiday := range holidays {
+		calendar.holidays[holiday.Date.Format("2006-01-02")] = holiday.Name
+	}
+	return calendar
+}
+
+// Holiday returns the name of the holiday on the date, if any
+func (c *BusinessCalendar) Holiday(date time.Time) (string, bool) {
+	name, ok := c.holidays[date.Format("2006-01-02")]
+	return name, ok
+}
+
+// IsBusinessDay reports whether the date is a working day and not a holiday
+func (c *BusinessCalendar) IsBusinessDay(date time.Time) bool {
+	if !c.workingDays[date.Weekday()] {
+		return false
+	}
+	_, holiday := c.Holiday(date)
+	return !holiday
+}
+
+// AddBusinessDays moves the given number of business days from the date,
+// backwards when negative. The date itself is not counted, so adding zero
+// days returns it unchanged.
+func (c *BusinessCalendar) AddBusinessDays(date time.Time, days int) time.Time {
+	step := 1
+	if days < 0 {
+		step, days = -1, -days
+	}
+	for days > 0 {
+		date = date.AddDate(0, 0, step)
+		if c.IsBusinessDay(date) {
+			days--
+		}
+	}
+	return date
+}
+
+// BusinessDaysBetween counts the business days after from, up to and
+// including to
+func (c *BusinessCalendar) BusinessDaysBetween(from, to time.Time) int {
+	count := 0
+	for day := from.AddDate(0, 0, 1); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(day) {
+			count++
+		}
+	}
+	return count
+}
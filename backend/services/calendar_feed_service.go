@@ -0,0 +1,316 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+// Feeds cover meetings, assignments and deadlines from feedPastDays ago to
+// feedFutureDays ahead
+const (
+	feedPastDays   = 90
+	feedFutureDays = 365
+)
+
+var icsPartStat = map[models.AttendeeStatus]string{
+	models.AttendeeStatusPending:  "NEEDS-ACTION",
+	models.AttendeeStatusAccepted: "ACCEPTED",
+	models.AttendeeStatusDeclined: "DECLINED",
+}
+
+var icsFrequency = map[models.RecurringPattern]string{
+	models.RecurringDaily:   "DAILY",
+	models.RecurringWeekly:  "WEEKLY",
+	models.RecurringMonthly: "MONTHLY",
+}
+
+type CalendarFeedService struct {
+	feedRepo     *repositories.CalendarFeedRepository
+	calendarRepo *repositories.CalendarRepository
+	meetingRepo  *repositories.MeetingRepository
+	userRepo     *repositories.UserRepository
+}
+
+func NewCalendarFeedService(
+	feedRepo *repositories.CalendarFeedRepository,
+	calendarRepo *repositories.CalendarRepository,
+	meetingRepo *repositories.MeetingRepository,
+	userRepo *repositories.UserRepository,
+) *CalendarFeedService {
+	return &CalendarFeedService{
+		feedRepo:     feedRepo,
+		calendarRepo: calendarRepo,
+		meetingRepo:  meetingRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create adds a feed and returns its token, which is not stored and cannot be
+// shown again
+func (s *CalendarFeedService) Create(userID uint, name string) (*models.CalendarFeed, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", errors.New("name is required")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(secret)
+
+	feed := &models.CalendarFeed{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashFeedToken(token),
+	}
+	if err := s.feedRepo.Create(feed); err != nil {
+		return nil, "", err
+	}
+	return feed, token, nil
+}
+
+func (s *CalendarFeedService) GetForUser(userID uint) ([]models.CalendarFeed, error) {
+	return s.feedRepo.FindByUser(userID)
+}
+
+func (s *CalendarFeedService) GetByID(id uint) (*models.CalendarFeed, error) {
+	return s.feedRepo.FindByID(id)
+}
+
+// Revoke deletes the feed, so its URL stops working
+func (s *CalendarFeedService) Revoke(id uint) error {
+	return s.feedRepo.Delete(id)
+}
+
+// Resolve finds the feed of a token and records the access
+func (s *CalendarFeedService) Resolve(token string) (*models.CalendarFeed, error) {
+	feed, err := s.feedRepo.FindByTokenHash(hashFeedToken(token))
+	if err != nil {
+		return nil, errors.New("calendar feed not found")
+	}
+	if err := s.feedRepo.Touch(feed.ID, time.Now()); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// writeMeetingTime writes a time of a meeting in its timezone, so that
+// clients repeat it at the same wall-clock time across DST changes
+func writeMeetingTime(w *icsWriter, name string, meeting *models.Meeting, t time.Time) {
+	zone := meeting.Zone()
+	if zone == time.UTC {
+		w.property(name, formatICSTime(t))
+		return
+	}
+	w.property(name, formatICSLocalTime(t.In(zone)), "TZID="+zone.String())
+}
+
+// meetingRRule builds the RRULE of a recurring meeting. UNTIL is the end of
+// the last day in the meeting's timezone, in UTC.
+func meetingRRule(meeting *models.Meeting) (string, bool) {
+	if !meeting.IsRecurring || meeting.RecurringPattern == nil {
+		return "", false
+	}
+	frequency, ok := icsFrequency[*meeting.RecurringPattern]
+	if !ok {
+		return "", false
+	}
+	rule := "FREQ=" + frequency
+	if meeting.RecurrenceInterval > 1 {
+		rule += fmt.Sprintf(";INTERVAL=%d", meeting.RecurrenceInterval)
+	}
+	if meeting.RecurrenceByDay != "" {
+		rule += ";BYDAY=" + meeting.RecurrenceByDay
+	}
+	if meeting.RecurrenceUntil != nil {
+		until := *meeting.RecurrenceUntil
+		end := time.Date(until.Year(), until.Month(), until.Day(), 23, 59, 59, 0, meeting.Zone())
+		rule += ";UNTIL=" + formatICSTime(end)
+	}
+	if meeting.RecurrenceCount != nil {
+		rule += fmt.Sprintf(";COUNT=%d", *meeting.RecurrenceCount)
+	}
+	return rule, true
+}
+
+// writeMeetingVEvent writes a meeting, or with a recurrence ID a changed
+// occurrence of it
+func writeMeetingVEvent(w *icsWriter, meeting *models.Meeting, stamp time.Time, recurrenceID *time.Time) {
+	w.line("BEGIN:VEVENT")
+	w.property("UID", fmt.Sprintf("meeting-%d@task-management", meeting.ID))
+	w.property("DTSTAMP", formatICSTime(stamp))
+	if recurrenceID != nil {
+		writeMeetingTime(w, "RECURRENCE-ID", meeting, *recurrenceID)
+	}
+	writeMeetingTime(w, "DTSTART", meeting, meeting.StartsAt)
+	writeMeetingTime(w, "DTEND", meeting, meeting.EndsAt)
+	if recurrenceID == nil {
+		if rule, ok := meetingRRule(meeting); ok {
+			w.property("RRULE", rule)
+			for _, exception := range meeting.Exceptions {
+				if !exception.Cancelled {
+					continue
+				}
+				original, _ := occurrenceTimes(meeting, exception.OccurrenceDate)
+				writeMeetingTime(w, "EXDATE", meeting, original)
+			}
+		}
+	}
+	w.property("SUMMARY", escapeICSText(meeting.Title))
+	if meeting.Description != "" {
+		w.property("DESCRIPTION", escapeICSText(meeting.Description))
+	}
+	if meeting.Location != "" {
+		w.property("LOCATION", escapeICSText(meeting.Location))
+	}
+	if meeting.Creator.ID != 0 {
+		w.property("ORGANIZER", "mailto:"+meeting.Creator.Email, "CN="+quoteICSParam(meeting.Creator.FullName))
+	}
+	for _, attendee := range meeting.Attendees {
+		partStat, ok := icsPartStat[attendee.Status]
+		if !ok {
+			partStat = "NEEDS-ACTION"
+		}
+		w.property("ATTENDEE", "mailto:"+attendee.User.Email,
+			"CN="+quoteICSParam(attendee.User.FullName), "PARTSTAT="+partStat)
+	}
+	w.line("END:VEVENT")
+}
+
+// writeMeetingEvent writes a meeting, with its cancelled occurrences excluded
+// from its recurrence and its changed occurrences as separate events
+func writeMeetingEvent(w *icsWriter, meeting *models.Meeting, stamp time.Time) {
+	writeMeetingVEvent(w, meeting, stamp, nil)
+	if !meeting.IsRecurring {
+		return
+	}
+	for i := range meeting.Exceptions {
+		exception := &meeting.Exceptions[i]
+		if exception.Cancelled {
+			continue
+		}
+		original, _ := occurrenceTimes(meeting, exception.OccurrenceDate)
+		occurrence := applyException(meeting, calendarDay(exception.OccurrenceDate), exception)
+		writeMeetingVEvent(w, &occurrence.Meeting, stamp, &original)
+	}
+}
+
+// writeIssueEvent writes an assignment or deadline as an all-day event
+func writeIssueEvent(w *icsWriter, event *repositories.CalendarEvent, stamp time.Time) {
+	uid := fmt.Sprintf("deadline-%d@task-management", event.IssueID)
+	summary := fmt.Sprintf("Due: #%d %s", event.IssueID, event.IssueTitle)
+	if event.Type == "assignment" {
+		uid = fmt.Sprintf("assignment-%d-%d-%s@task-management", event.IssueID, event.UserID, event.Role)
+		summary = fmt.Sprintf("#%d %s", event.IssueID, event.IssueTitle)
+		if event.Role != "" && event.Role != string(models.AssignmentOwner) {
+			summary += fmt.Sprintf(" (%s)", event.Role)
+		}
+	}
+
+	var details []string
+	if event.StatusName != "" {
+		details = append(details, "Status: "+event.StatusName)
+	}
+	if event.Priority != "" {
+		details = append(details, "Priority: "+event.Priority)
+	}
+	details = append(details, "Team: "+event.TeamName)
+
+	w.line("BEGIN:VEVENT")
+	w.property("UID", uid)
+	w.property("DTSTAMP", formatICSTime(stamp))
+	w.property("DTSTART", formatICSDate(event.StartDate), "VALUE=DATE")
+	// DTEND of all-day events is exclusive
+	w.property("DTEND", formatICSDate(event.EndDate.AddDate(0, 0, 1)), "VALUE=DATE")
+	w.property("SUMMARY", escapeICSText(summary))
+	w.property("DESCRIPTION", escapeICSText(strings.Join(details, "\n")))
+	w.property("TRANSP", "TRANSPARENT")
+	w.line("END:VEVENT")
+}
+
+// Render builds the RFC 5545 calendar of a user: their meetings with the
+// attendees' responses, their active assignments as all-day spans and the
+// deadlines of the issues they are assigned to
+func (s *CalendarFeedService) Render(userID uint) (string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", errors.New("user not found")
+	}
+
+	now := time.Now()
+	today := calendarDay(now)
+	from, to := today.AddDate(0, 0, -feedPastDays), today.AddDate(0, 0, feedFutureDays)
+
+	meetings, err := s.meetingRepo.FindByAttendee(user.ID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return "", err
+	}
+	assignments, err := s.calendarRepo.GetCalendarEvents(nil, &user.ID, from, to)
+	if err != nil {
+		return "", err
+	}
+	deadlines, err := s.calendarRepo.GetDeadlineEvents(user.ID, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	w := &icsWriter{}
+	w.line("BEGIN:VCALENDAR")
+	w.property("VERSION", "2.0")
+	w.property("PRODID", "-//Task Management//Calendar Feed//EN")
+	w.property("CALSCALE", "GREGORIAN")
+	w.property("METHOD", "PUBLISH")
+	w.property("X-WR-CALNAME", escapeICSText(user.FullName+" - Task Management"))
+	w.property("REFRESH-INTERVAL", "PT1H", "VALUE=DURATION")
+	w.property("X-PUBLISHED-TTL", "PT1H")
+
+	// Timezones cover the first occurrences of the meetings given in them up
+	// to the end of the feed
+	zones := make(map[string]time.Time)
+	var zoneNames []string
+	for i := range meetings {
+		zone := meetings[i].Zone()
+		if zone == time.UTC {
+			continue
+		}
+		first, ok := zones[zone.String()]
+		if !ok {
+			zoneNames = append(zoneNames, zone.String())
+		}
+		if !ok || meetings[i].StartsAt.Before(first) {
+			zones[zone.String()] = meetings[i].StartsAt
+		}
+	}
+	sort.Strings(zoneNames)
+	for _, name := range zoneNames {
+		zone, _ := time.LoadLocation(name)
+		writeVTimezone(w, zone, zones[name], to.AddDate(0, 0, 1))
+	}
+
+	for i := range meetings {
+		writeMeetingEvent(w, &meetings[i], now)
+	}
+	for i := range assignments {
+		writeIssueEvent(w, &assignments[i], now)
+	}
+	for i := range deadlines {
+		writeIssueEvent(w, &deadlines[i], now)
+	}
+
+	w.line("END:VCALENDAR")
+	return w.String(), nil
+}
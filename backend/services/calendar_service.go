@@ -1,18 +1,53 @@
 package services
 
 import (
+	"sort"
 	"task-management/repositories"
 	"time"
 )
 
 type CalendarService struct {
 	calendarRepo *repositories.CalendarRepository
+	teamRepo     *repositories.TeamRepository
 }
 
-func NewCalendarService(calendarRepo *repositories.CalendarRepository) *CalendarService {
-	return &CalendarService{calendarRepo: calendarRepo}
+func NewCalendarService(calendarRepo *repositories.CalendarRepository, teamRepo *repositories.TeamRepository) *CalendarService {
+	return &CalendarService{
+		calendarRepo: calendarRepo,
+		teamRepo:     teamRepo,
+	}
 }
 
-func (s *CalendarService) GetCalendarEvents(teamID *uint, userID *uint, startDate, endDate time.Time) ([]repositories.CalendarEvent, error) {
-	return s.calendarRepo.GetCalendarEvents(teamID, userID, startDate, endDate)
+// GetCalendarEvents returns assignments and approved leave in the range,
+// ordered by start date. Without a team, it covers the teams the viewer
+// belongs to.
+func (s *CalendarService) GetCalendarEvents(viewerID uint, teamID *uint, userID *uint, startDate, endDate time.Time) ([]repositories.CalendarEvent, error) {
+	var teamIDs []uint
+	if teamID != nil {
+		teamIDs = []uint{*teamID}
+	} else {
+		var err error
+		teamIDs, err = s.teamRepo.FindTeamIDsByUser(viewerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(teamIDs) == 0 {
+		return []repositories.CalendarEvent{}, nil
+	}
+
+	events, err := s.calendarRepo.GetCalendarEvents(teamIDs, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := s.calendarRepo.GetLeaveEvents(teamIDs, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	events = append(events, leaves...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartDate.Before(events[j].StartDate)
+	})
+	return events, nil
 }
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+var ErrOverCapacity = errors.New("assignment exceeds the user's capacity")
+
+// maxWorkloadDays bounds the date range of a workload heatmap
+const maxWorkloadDays = 366
+
+type CapacityService struct {
+	assignmentRepo *repositories.AssignmentRepository
+	userRepo       *repositories.UserRepository
+	teamRepo       *repositories.TeamRepository
+	leaveService   *LeaveService
+	holidayService *HolidayService
+}
+
+func NewCapacityService(
+	assignmentRepo *repositories.AssignmentRepository,
+	userRepo *repositories.UserRepository,
+	teamRepo *repositories.TeamRepository,
+	leaveService *LeaveService,
+	holidayService *HolidayService,
+) *CapacityService {
+	return &CapacityService{
+		assignmentRepo: assignmentRepo,
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		leaveService:   leaveService,
+		holidayService: holidayService,
+	}
+}
+
+// WorkloadCell is the planned load of one user for a day, or for the week
+// starting on Start
+type WorkloadCell struct {
+	Start           string  `json:"start"`
+	CapacityMinutes int     `json:"capacity_minutes"`
+	LoadMinutes     int     `json:"load_minutes"`
+	Utilization     float64 `json:"utilization"`
+	Overloaded      bool    `json:"overloaded"`
+}
+
+// CapacityCheck lists the days and weeks a new assignment pushes over
+// capacity, and the approved leave of the user during the assignment
+type CapacityCheck struct {
+	UserID uint                  `json:"user_id"`
+	Days   []WorkloadCell        `json:"days,omitempty"`
+	Weeks  []WorkloadCell        `json:"weeks,omitempty"`
+	Away   []models.LeaveRequest `json:"away,omitempty"`
+}
+
+// Overloaded reports whether the assignment puts the user over capacity
+func (c *CapacityCheck) Overloaded() bool {
+	return len(c.Days) > 0 || len(c.Weeks) > 0
+}
+
+// Warning summarises the check for API responses
+func (c *CapacityCheck) Warning() string {
+	if c.Overloaded() {
+		return "User is over capacity"
+	}
+	return "User is away during the assignment"
+}
+
+type MemberWorkload struct {
+	UserID   uint   `json:"user_id"`
+	FullName string `json:"full_name"`
+	// Unestimated assignments count as no load
+	Unestimated int            `json:"unestimated_assignments"`
+	Cells       []WorkloadCell `json:"cells"`
+}
+
+type TeamWorkload struct {
+	TeamID      uint             `json:"team_id"`
+	From        string           `json:"from"`
+	To          string           `json:"to"`
+	Granularity string           `json:"granularity"`
+	Members     []MemberWorkload `json:"members"`
+}
+
+// calendarDay drops the time of day, keeping the calendar date
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// availability is when a user can work: the business days of their
+// organization less their approved leave
+type availability struct {
+	user     *models.User
+	calendar *BusinessCalendar
+	leaves   []models.LeaveRequest
+}
+
+func (a *availability) isAway(date time.Time) bool {
+	for i := range a.leaves {
+		if a.leaves[i].Covers(date) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *availability) works(date time.Time) bool {
+	return a.calendar.IsBusinessDay(date) && !a.isAway(date)
+}
+
+// dayCapacity is the number of minutes the user can work on the given date
+func (a *availability) dayCapacity(date time.Time) int {
+	if !a.works(date) {
+		return 0
+	}
+	return workdayMinutes(a.user)
+}
+
+// weekCapacity is the number of minutes the user can work in the week starting
+// on monday. An explicit weekly capacity overrides the working hours and is
+// reduced in proportion to the business days lost to leave.
+func (a *availability) weekCapacity(monday time.Time) int {
+	capacity, workingDays, availableDays := 0, 0, 0
+	for i := 0; i < 7; i++ {
+		day := monday.AddDate(0, 0, i)
+		capacity += a.dayCapacity(day)
+		if a.calendar.IsBusinessDay(day) {
+			workingDays++
+			if !a.isAway(day) {
+				availableDays++
+			}
+		}
+	}
+	if a.user.WeeklyCapacity == nil {
+		return capacity
+	}
+	if workingDays == 0 {
+		return *a.user.WeeklyCapacity
+	}
+	return *a.user.WeeklyCapacity * availableDays / workingDays
+}
+
+// spreadLoad adds the assignment's estimate evenly to each day of it the user
+// works. Assignments that fall entirely on days off are spread over all of
+// their days.
+func (a *availability) spreadLoad(load map[time.Time]int, assignment *models.IssueAssignment) {
+	if assignment.Estimate == nil {
+		return
+	}
+	start, end := calendarDay(assignment.StartDate), calendarDay(assignment.EndDate)
+
+	var days []time.Time
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if a.works(day) {
+			days = append(days, day)
+		}
+	}
+	if len(days) == 0 {
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			days = append(days, day)
+		}
+	}
+
+	share, remainder := *assignment.Estimate/len(days), *assignment.Estimate%len(days)
+	for i, day := range days {
+		load[day] += share
+		if i < remainder {
+			load[day]++
+		}
+	}
+}
+
+func weekLoad(load map[time.Time]int, monday time.Time) int {
+	total := 0
+	for i := 0; i < 7; i++ {
+		total += load[monday.AddDate(0, 0, i)]
+	}
+	return total
+}
+
+func newWorkloadCell(start time.Time, capacity, load int) WorkloadCell {
+	cell := WorkloadCell{
+		Start:           start.Format("2006-01-02"),
+		CapacityMinutes: capacity,
+		LoadMinutes:     load,
+		Overloaded:      load > capacity,
+	}
+	if capacity > 0 {
+		cell.Utilization = math.Round(float64(load)/float64(capacity)*100) / 100
+	}
+	return cell
+}
+
+// assignmentSpan widens the range to cover all of the assignments, so their
+// estimates are spread knowing all of the leave they overlap
+func assignmentSpan(assignments []models.IssueAssignment, from, to time.Time) (time.Time, time.Time) {
+	for _, assignment := range assignments {
+		if start := calendarDay(assignment.StartDate); start.Before(from) {
+			from = start
+		}
+		if end := calendarDay(assignment.EndDate); end.After(to) {
+			to = end
+		}
+	}
+	return from, to
+}
+
+// availabilities loads the business calendars of the users' organizations and
+// their approved leave overlapping the range
+func (s *CapacityService) availabilities(users []*models.User, from, to time.Time) (map[uint]*availability, error) {
+	userIDs := make([]uint, len(users))
+	calendars := make(map[uint]*BusinessCalendar)
+	result := make(map[uint]*availability, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+		calendar, ok := calendars[user.OrganizationID]
+		if !ok {
+			var err error
+			calendar, err = s.holidayService.BusinessCalendar(user.OrganizationID, from, to)
+			if err != nil {
+				return nil, err
+			}
+			calendars[user.OrganizationID] = calendar
+		}
+		result[user.ID] = &availability{user: user, calendar: calendar}
+	}
+	leaves, err := s.leaveService.ApprovedBetween(userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, leave := range leaves {
+		result[leave.UserID].leaves = append(result[leave.UserID].leaves, leave)
+	}
+	return result, nil
+}
+
+// Check reports the days and weeks in which the new assignment would put its
+// user over capacity, and the user's approved leave during the assignment.
+// It returns nil when there is nothing to report.
+func (s *CapacityService) Check(assignment *models.IssueAssignment) (*CapacityCheck, error) {
+	user, err := s.userRepo.FindByID(assignment.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	from := weekStart(assignment.StartDate)
+	to := weekStart(assignment.EndDate).AddDate(0, 0, 6)
+	existing, err := s.assignmentRepo.FindActiveOverlapping([]uint{user.ID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	spanFrom, spanTo := assignmentSpan(existing, from, to)
+	availabilities, err := s.availabilities([]*models.User{user}, spanFrom, spanTo)
+	if err != nil {
+		return nil, err
+	}
+	available := availabilities[user.ID]
+
+	check := &CapacityCheck{UserID: user.ID}
+	start, end := calendarDay(assignment.StartDate), calendarDay(assignment.EndDate)
+	for _, leave := range available.leaves {
+		if !leave.StartDate.After(end) && !leave.EndDate.Before(start) {
+			check.Away = append(check.Away, leave)
+		}
+	}
+
+	if assignment.Estimate != nil {
+		load := make(map[time.Time]int)
+		for i := range existing {
+			available.spreadLoad(load, &existing[i])
+		}
+		added := make(map[time.Time]int)
+		available.spreadLoad(added, assignment)
+		for day, minutes := range added {
+			load[day] += minutes
+		}
+
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if added[day] == 0 {
+				continue
+			}
+			if cell := newWorkloadCell(day, available.dayCapacity(day), load[day]); cell.Overloaded {
+				check.Days = append(check.Days, cell)
+			}
+		}
+		for monday := from; !monday.After(to); monday = monday.AddDate(0, 0, 7) {
+			if weekLoad(added, monday) == 0 {
+				continue
+			}
+			if cell := newWorkloadCell(monday, available.weekCapacity(monday), weekLoad(load, monday)); cell.Overloaded {
+				check.Weeks = append(check.Weeks, cell)
+			}
+		}
+	}
+
+	if !check.Overloaded() && len(check.Away) == 0 {
+		return nil, nil
+	}
+	return check, nil
+}
+
+// GetTeamWorkload builds a heatmap of the planned load of each team member
+// from their active assignments, per day or per week. Weekly cells cover whole
+// weeks starting on Monday.
+func (s *CapacityService) GetTeamWorkload(teamID uint, from, to time.Time, granularity string) (*TeamWorkload, error) {
+	from, to = calendarDay(from), calendarDay(to)
+	switch granularity {
+	case "day":
+	case "week":
+		from = weekStart(from)
+		to = weekStart(to).AddDate(0, 0, 6)
+	default:
+		return nil, errors.New("granularity must be day or week")
+	}
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+	if to.Sub(from) > maxWorkloadDays*24*time.Hour {
+		return nil, errors.New("date range cannot exceed 366 days")
+	}
+
+	members, err := s.teamRepo.GetMembers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, len(members))
+	users := make([]*models.User, len(members))
+	for i := range members {
+		userIDs[i] = members[i].UserID
+		users[i] = &members[i].User
+	}
+	assignments, err := s.assignmentRepo.FindActiveOverlapping(userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	spanFrom, spanTo := assignmentSpan(assignments, from, to)
+	availabilities, err := s.availabilities(users, spanFrom, spanTo)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint][]*models.IssueAssignment)
+	for i := range assignments {
+		byUser[assignments[i].UserID] = append(byUser[assignments[i].UserID], &assignments[i])
+	}
+
+	workload := &TeamWorkload{
+		TeamID:      teamID,
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		Granularity: granularity,
+		Members:     make([]MemberWorkload, 0, len(members)),
+	}
+	for i := range members {
+		available := availabilities[members[i].UserID]
+		row := MemberWorkload{UserID: members[i].UserID, FullName: members[i].User.FullName}
+
+		load := make(map[time.Time]int)
+		for _, assignment := range byUser[members[i].UserID] {
+			if assignment.Estimate == nil {
+				row.Unestimated++
+			}
+			available.spreadLoad(load, assignment)
+		}
+
+		if granularity == "day" {
+			for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+				row.Cells = append(row.Cells, newWorkloadCell(day, available.dayCapacity(day), load[day]))
+			}
+		} else {
+			for monday := from; !monday.After(to); monday = monday.AddDate(0, 0, 7) {
+				row.Cells = append(row.Cells, newWorkloadCell(monday, available.weekCapacity(monday), weekLoad(load, monday)))
+			}
+		}
+		workload.Members = append(workload.Members, row)
+	}
+	return workload, nil
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+	"unicode"
+)
+
+type CommentService struct {
+	commentRepo   *repositories.CommentRepository
+	issueRepo     *repositories.IssueRepository
+	editWindow    time.Duration
+	renderService *RenderService
+}
+
+func NewCommentService(
+	commentRepo *repositories.CommentRepository,
+	issueRepo *repositories.IssueRepository,
+	editWindow time.Duration,
+	renderService *RenderService,
+) *CommentService {
+	return &CommentService{
+		commentRepo:   commentRepo,
+		issueRepo:     issueRepo,
+		editWindow:    editWindow,
+		renderService: renderService,
+	}
+}
+
+type ModerationRequest struct {
+	Action models.CommentModeration `json:"action" binding:"required"`
+	Reason string                   `json:"reason" binding:"required"`
+}
+
+// Create adds a comment or, with a parent, a reply to a top-level comment
+func (s *CommentService) Create(comment *models.Comment) error {
+	if comment.ParentID != nil {
+		parent, err := s.commentRepo.FindByID(*comment.ParentID)
+		if err != nil || parent.IssueID != comment.IssueID {
+			return errors.New("parent comment not found")
+		}
+		if parent.ParentID != nil {
+			return errors.New("replies cannot be nested")
+		}
+	}
+
+	if err := s.render(comment, comment.Content); err != nil {
+		return err
+	}
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return err
+	}
+
+	description := "Comment added"
+	if comment.ParentID != nil {
+		description = "Replied to a comment"
+	}
+	activity := &models.IssueActivity{
+		IssueID:      comment.IssueID,
+		UserID:       &comment.UserID,
+		ActivityType: models.ActivityCommented,
+		Description:  description,
+	}
+	return s.issueRepo.CreateActivity(activity)
+}
+
+func (s *CommentService) GetByID(id uint) (*models.Comment, error) {
+	return s.commentRepo.FindByID(id)
+}
+
+// render sets the comment's content together with its rendered HTML
+func (s *CommentService) render(comment *models.Comment, content string) error {
+	teamID, err := s.GetIssueTeamID(comment.IssueID)
+	if err != nil {
+		return err
+	}
+	rendered, err := s.renderService.RenderForTeam(content, teamID)
+	if err != nil {
+		return err
+	}
+	comment.Content = content
+	comment.ContentHTML = rendered
+	return nil
+}
+
+// GetIssueTeamID returns the team of the issue a comment belongs to
+func (s *CommentService) GetIssueTeamID(issueID uint) (uint, error) {
+	issue, err := s.issueRepo.FindByID(issueID)
+	if err != nil {
+		return 0, errors.New("issue not found")
+	}
+	return issue.TeamID, nil
+}
+
+// Edit replaces the content of a comment, keeping the previous content as a
+// revision. Edits made after the edit window mark the comment as edited.
+func (s *CommentService) Edit(comment *models.Comment, content string, editorID uint) error {
+	if comment.Moderation != nil {
+		return errors.New("moderated comments cannot be edited")
+	}
+	if content == comment.Content {
+		return nil
+	}
+
+	revision := &models.CommentRevision{
+		CommentID: comment.ID,
+		Content:   comment.Content,
+		EditedBy:  editorID,
+	}
+	if err := s.commentRepo.CreateRevision(revision); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.Sub(comment.CreatedAt) > s.editWindow {
+		comment.EditedAt = &now
+	}
+	if err := s.render(comment, content); err != nil {
+		return err
+	}
+	return s.commentRepo.Update(comment)
+}
+
+func (s *CommentService) GetRevisions(commentID uint) ([]models.CommentRevision, error) {
+	return s.commentRepo.FindRevisions(commentID)
+}
+
+// Moderate hides or removes a comment. Hidden comments keep their content and
+// can be restored; removed comments lose their content and revisions.
+func (s *CommentService) Moderate(comment *models.Comment, moderatorID uint, req *ModerationRequest) error {
+	if req.Action != models.CommentHidden && req.Action != models.CommentRemoved {
+		return errors.New("action must be hidden or removed")
+	}
+	if comment.Moderation != nil && *comment.Moderation == models.CommentRemoved {
+		return errors.New("comment has already been removed")
+	}
+
+	now := time.Now()
+	action := req.Action
+	comment.Moderation = &action
+	comment.ModeratedBy = &moderatorID
+	comment.ModeratedAt = &now
+	comment.ModerationReason = strings.TrimSpace(req.Reason)
+	comment.ModeratedByUser = nil
+
+	if action == models.CommentRemoved {
+		comment.Content = ""
+		comment.ContentHTML = ""
+		if err := s.commentRepo.DeleteRevisions(comment.ID); err != nil {
+			return err
+		}
+	}
+	return s.commentRepo.Update(comment)
+}
+
+// Unhide restores a hidden comment
+func (s *CommentService) Unhide(comment *models.Comment) error {
+	if comment.Moderation == nil || *comment.Moderation != models.CommentHidden {
+		return errors.New("comment is not hidden")
+	}
+	comment.Moderation = nil
+	comment.ModeratedBy = nil
+	comment.ModeratedAt = nil
+	comment.ModerationReason = ""
+	comment.ModeratedByUser = nil
+	return s.commentRepo.Update(comment)
+}
+
+// tombstone strips the content of a moderated comment for display
+func tombstone(comment *models.Comment) {
+	if comment.Moderation != nil {
+		comment.Content = ""
+		comment.ContentHTML = ""
+		comment.Mentions = nil
+	}
+}
+
+func (s *CommentService) Delete(id uint) error {
+	return s.commentRepo.Delete(id)
+}
+
+// GetThreads returns the issue's top-level comments with their replies and
+// reaction counts, from the point of view of viewerID. Moderated comments are
+// shown as tombstones unless the viewer is a moderator.
+func (s *CommentService) GetThreads(issueID, viewerID uint, isModerator bool) ([]models.Comment, error) {
+	threads, err := s.commentRepo.FindThreads(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	var commentIDs []uint
+	for _, thread := range threads {
+		commentIDs = append(commentIDs, thread.ID)
+		for _, reply := range thread.Replies {
+			commentIDs = append(commentIDs, reply.ID)
+		}
+	}
+
+	reactions, err := s.commentRepo.FindReactions(commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	summaries := summarizeReactions(reactions, viewerID)
+
+	for i := range threads {
+		threads[i].Reactions = reactionsOf(summaries, threads[i].ID)
+		for j := range threads[i].Replies {
+			threads[i].Replies[j].Reactions = reactionsOf(summaries, threads[i].Replies[j].ID)
+			if !isModerator {
+				tombstone(&threads[i].Replies[j])
+			}
+		}
+		if !isModerator {
+			tombstone(&threads[i])
+		}
+	}
+	return threads, nil
+}
+
+// ToggleReaction adds or removes the user's emoji reaction and returns the
+// comment's updated reactions
+func (s *CommentService) ToggleReaction(commentID, userID uint, emoji string) ([]models.ReactionSummary, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" || len(emoji) > 32 || strings.IndexFunc(emoji, unicode.IsSpace) >= 0 {
+		return nil, errors.New("invalid emoji")
+	}
+
+	if _, err := s.commentRepo.ToggleReaction(commentID, userID, emoji); err != nil {
+		return nil, err
+	}
+
+	reactions, err := s.commentRepo.FindReactions([]uint{commentID})
+	if err != nil {
+		return nil, err
+	}
+	return reactionsOf(summarizeReactions(reactions, userID), commentID), nil
+}
+
+// Resolve marks a thread as resolved; only top-level comments can be resolved
+func (s *CommentService) Resolve(comment *models.Comment, userID uint) error {
+	if comment.ParentID != nil {
+		return errors.New("only top-level comments can be resolved")
+	}
+	if comment.ResolvedAt != nil {
+		return errors.New("thread is already resolved")
+	}
+	now := time.Now()
+	comment.ResolvedAt = &now
+	comment.ResolvedBy = &userID
+	return s.commentRepo.Update(comment)
+}
+
+func (s *CommentService) Reopen(comment *models.Comment) error {
+	if comment.ResolvedAt == nil {
+		return errors.New("thread is not resolved")
+	}
+	comment.ResolvedAt = nil
+	comment.ResolvedBy = nil
+	comment.ResolvedByUser = nil
+	return s.commentRepo.Update(comment)
+}
+
+// summarizeReactions groups reactions per comment and emoji, in order of first use
+func summarizeReactions(reactions []models.CommentReaction, viewerID uint) map[uint][]models.ReactionSummary {
+	summaries := make(map[uint][]models.ReactionSummary)
+	for _, reaction := range reactions {
+		list := summaries[reaction.CommentID]
+		i := 0
+		for i < len(list) && list[i].Emoji != reaction.Emoji {
+			i++
+		}
+		if i == len(list) {
+			list = append(list, models.ReactionSummary{Emoji: reaction.Emoji, UserIDs: []uint{}})
+		}
+		list[i].Count++
+		list[i].UserIDs = append(list[i].UserIDs, reaction.UserID)
+		if reaction.UserID == viewerID {
+			list[i].ReactedByMe = true
+		}
+		summaries[reaction.CommentID] = list
+	}
+	return summaries
+}
+
+func reactionsOf(summaries map[uint][]models.ReactionSummary, commentID uint) []models.ReactionSummary {
+	if list, ok := summaries[commentID]; ok {
+		return list
+	}
+	return []models.ReactionSummary{}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+// maxHolidayDays bounds how many days one imported event can cover
+const maxHolidayDays = 31
+
+type HolidayService struct {
+	holidayRepo *repositories.HolidayRepository
+	orgRepo     *repositories.OrganizationRepository
+	teamRepo    *repositories.TeamRepository
+}
+
+func NewHolidayService(
+	holidayRepo *repositories.HolidayRepository,
+	orgRepo *repositories.OrganizationRepository,
+	teamRepo *repositories.TeamRepository,
+) *HolidayService {
+	return &HolidayService{
+		holidayRepo: holidayRepo,
+		orgRepo:     orgRepo,
+		teamRepo:    teamRepo,
+	}
+}
+
+type HolidayInput struct {
+	Date string `json:"date" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+// ValidateWorkingDays checks a list of working days of the week
+func ValidateWorkingDays(days models.Weekdays) error {
+	if len(days) == 0 {
+		return errors.New("at least one working day is required")
+	}
+	seen := make(map[int]bool)
+	for _, day := range days {
+		if day < 0 || day > 6 {
+			return errors.New("working days must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		if seen[day] {
+			return errors.New("working days must not repeat")
+		}
+		seen[day] = true
+	}
+	return nil
+}
+
+func (s *HolidayService) CreateCalendar(orgID, createdBy uint, name string) (*models.HolidayCalendar, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	calendar := &models.HolidayCalendar{
+		OrganizationID: orgID,
+		Name:           name,
+		CreatedBy:      createdBy,
+	}
+	if err := s.holidayRepo.CreateCalendar(calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+func (s *HolidayService) GetCalendars(orgID uint) ([]models.HolidayCalendar, error) {
+	return s.holidayRepo.FindCalendarsByOrganization(orgID)
+}
+
+func (s *HolidayService) GetCalendar(id uint) (*models.HolidayCalendar, error) {
+	return s.holidayRepo.FindCalendarByID(id)
+}
+
+func (s *HolidayService) DeleteCalendar(id uint) error {
+	return s.holidayRepo.DeleteCalendar(id)
+}
+
+// AddHoliday adds a holiday to the calendar, renaming any holiday already on
+// that date
+func (s *HolidayService) AddHoliday(calendar *models.HolidayCalendar, input *HolidayInput) (*models.Holiday, error) {
+	date, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format (use YYYY-MM-DD)")
+	}
+	holiday := models.Holiday{
+		CalendarID: calendar.ID,
+		Date:       date,
+		Name:       strings.TrimSpace(input.Name),
+	}
+	holidays := []models.Holiday{holiday}
+	if err := s.holidayRepo.UpsertHolidays(holidays); err != nil {
+		return nil, err
+	}
+	return &holidays[0], nil
+}
+
+func (s *HolidayService) GetHoliday(id uint) (*models.Holiday, error) {
+	return s.holidayRepo.FindHolidayByID(id)
+}
+
+func (s *HolidayService) DeleteHoliday(id uint) error {
+	return s.holidayRepo.DeleteHoliday(id)
+}
+
+// Import adds the events of an iCalendar file, such as a national holiday
+// feed, to the calendar. Each day an event covers becomes a holiday named
+// after its summary; holidays already on those dates are renamed. It returns
+// the number of holidays imported.
+func (s *HolidayService) Import(calendar *models.HolidayCalendar, r io.Reader) (int, error) {
+	events, err := parseICS(r)
+	if err != nil {
+		return 0, err
+	}
+
+	byDate := make(map[string]models.Holiday)
+	for _, event := range events {
+		name := strings.TrimSpace(event.Summary)
+		if name == "" {
+			name = "Holiday"
+		}
+		start := calendarDay(event.Start)
+		end := calendarDay(event.End)
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+		if end.Sub(start) > maxHolidayDays*24*time.Hour {
+			return 0, errors.New("event " + name + " is longer than 31 days")
+		}
+		for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+			byDate[day.Format("2006-01-02")] = models.Holiday{
+				CalendarID: calendar.ID,
+				Date:       day,
+				Name:       name,
+				UID:        event.UID,
+			}
+		}
+	}
+
+	holidays := make([]models.Holiday, 0, len(byDate))
+	for _, holiday := range byDate {
+		holidays = append(holidays, holiday)
+	}
+	if err := s.holidayRepo.UpsertHolidays(holidays); err != nil {
+		return 0, err
+	}
+	return len(holidays), nil
+}
+
+// BusinessCalendar loads the organization's working days and its holidays
+// within the range
+func (s *HolidayService) BusinessCalendar(orgID uint, from, to time.Time) (*BusinessCalendar, error) {
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		return nil, errors.New("organization not found")
+	}
+	holidays, err := s.holidayRepo.FindByOrganization(orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return newBusinessCalendar(org.WorkingDays, holidays), nil
+}
+
+// AddBusinessDays moves the given number of the organization's business days
+// from the date
+func (s *HolidayService) AddBusinessDays(orgID uint, date time.Time, days int) (time.Time, error) {
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		return time.Time{}, errors.New("organization not found")
+	}
+	workingDays := len(org.WorkingDays)
+	if workingDays == 0 {
+		workingDays = len(defaultWorkingDays)
+	}
+
+	// Enough calendar days for the working days plus a year of holidays
+	span := days
+	if span < 0 {
+		span = -span
+	}
+	window := (span/workingDays+1)*7 + 366
+	from, to := date.AddDate(0, 0, -window), date.AddDate(0, 0, window)
+	holidays, err := s.holidayRepo.FindByOrganization(orgID, from, to)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return newBusinessCalendar(org.WorkingDays, holidays).AddBusinessDays(date, days), nil
+}
+
+// AddTeamBusinessDays moves the given number of business days of the team's
+// organization from the date
+func (s *HolidayService) AddTeamBusinessDays(teamID uint, date time.Time, days int) (time.Time, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return time.Time{}, errors.New("team not found")
+	}
+	return s.AddBusinessDays(team.OrganizationID, date, days)
+}
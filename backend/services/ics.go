@@ -0,0 +1,372 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsEvent is the part of an iCalendar VEVENT the app uses
+type icsEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	// End is exclusive. All-day events without DTEND or DURATION last one day.
+	End    time.Time
+	AllDay bool
+	// RRule is the raw recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO
+	RRule string
+	// RecurrenceID is the original start of a changed occurrence of the
+	// recurring event with the same UID
+	RecurrenceID *time.Time
+	// ExDates are the original starts of cancelled occurrences
+	ExDates   []time.Time
+	Status    string
+	Organizer string
+	Attendees []icsAttendee
+}
+
+type icsAttendee struct {
+	Email    string
+	Name     string
+	PartStat string
+}
+
+// icsMailto returns the address of a mailto: URI, lowercased
+func icsMailto(value string) string {
+	if len(value) > len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		value = value[len("mailto:"):]
+	}
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// icsDurationUnits are the units of DURATION values, before and after the T
+var icsDurationUnits = map[bool]map[rune]time.Duration{
+	false: {'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour},
+	true:  {'H': time.Hour, 'M': time.Minute, 'S': time.Second},
+}
+
+// parseICSDuration parses a positive DURATION value such as PT1H30M or P1D
+func parseICSDuration(value string) (time.Duration, error) {
+	invalid := errors.New("invalid DURATION: " + value)
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(value, "+"), "P")
+	if !ok || rest == "" {
+		return 0, invalid
+	}
+
+	var total time.Duration
+	inTime := false
+	number, digits := 0, 0
+	for _, r := range rest {
+		if r >= '0' && r <= '9' {
+			number = number*10 + int(r-'0')
+			digits++
+			continue
+		}
+		if r == 'T' && !inTime && digits == 0 {
+			inTime = true
+			continue
+		}
+		unit, ok := icsDurationUnits[inTime][r]
+		if !ok || digits == 0 {
+			return 0, invalid
+		}
+		total += time.Duration(number) * unit
+		number, digits = 0, 0
+	}
+	if digits > 0 || strings.HasSuffix(rest, "T") {
+		return 0, invalid
+	}
+	return total, nil
+}
+
+// icsProperty is one unfolded content line, e.g. DTSTART;VALUE=DATE:20260101
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+func parseICSLine(line string) (icsProperty, bool) {
+	// The value starts after the first colon outside a quoted parameter
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	property := icsProperty{
+		Name:   strings.ToUpper(parts[0]),
+		Params: make(map[string]string),
+		Value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			property.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return property, true
+}
+
+// unescapeICSText decodes the backslash escapes of TEXT values
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// parseICSTime parses DATE and DATE-TIME values. Times in UTC end in Z,
+// others are in their TZID, or UTC when it is missing or unknown.
+func parseICSTime(property icsProperty) (time.Time, bool, error) {
+	value := property.Value
+	if property.Params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tzid := property.Params["TZID"]; tzid != "" {
+		if tzLoc, err := time.LoadLocation(tzid); err == nil && tzid != "Local" {
+			loc = tzLoc
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseICS reads the events of an iCalendar file. Events without a start are
+// skipped.
+func parseICS(r io.Reader) ([]icsEvent, error) {
+	// Unfold continuation lines, which start with a space or tab
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(lines[0], "\ufeff"))) != "BEGIN:VCALENDAR" {
+		return nil, errors.New("not an iCalendar file")
+	}
+
+	var events []icsEvent
+	var current *icsEvent
+	hasEnd := false
+	var duration time.Duration
+	// Properties of components inside an event, such as VALARM, are ignored
+	nested := 0
+	for _, line := range lines {
+		property, ok := parseICSLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case property.Name == "BEGIN" && strings.EqualFold(property.Value, "VEVENT"):
+			current, hasEnd, duration, nested = &icsEvent{}, false, 0, 0
+		case property.Name == "BEGIN" && current != nil:
+			nested++
+		case property.Name == "END" && current != nil && nested > 0:
+			nested--
+		case nested > 0:
+			continue
+		case property.Name == "END" && strings.EqualFold(property.Value, "VEVENT"):
+			if current != nil && !current.Start.IsZero() {
+				if !hasEnd {
+					current.End = current.Start.Add(duration)
+					if current.AllDay && duration == 0 {
+						current.End = current.Start.AddDate(0, 0, 1)
+					}
+				}
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case property.Name == "UID":
+			current.UID = property.Value
+		case property.Name == "SUMMARY":
+			current.Summary = unescapeICSText(property.Value)
+		case property.Name == "DESCRIPTION":
+			current.Description = unescapeICSText(property.Value)
+		case property.Name == "LOCATION":
+			current.Location = unescapeICSText(property.Value)
+		case property.Name == "RRULE":
+			current.RRule = strings.ToUpper(property.Value)
+		case property.Name == "RECURRENCE-ID":
+			original, _, err := parseICSTime(property)
+			if err != nil {
+				return nil, errors.New("invalid RECURRENCE-ID: " + property.Value)
+			}
+			current.RecurrenceID = &original
+		case property.Name == "EXDATE":
+			// One EXDATE can list several comma-separated times
+			for _, value := range strings.Split(property.Value, ",") {
+				property.Value = value
+				exDate, _, err := parseICSTime(property)
+				if err != nil {
+					return nil, errors.New("invalid EXDATE: " + value)
+				}
+				current.ExDates = append(current.ExDates, exDate)
+			}
+		case property.Name == "STATUS":
+			current.Status = strings.ToUpper(property.Value)
+		case property.Name == "ORGANIZER":
+			current.Organizer = icsMailto(property.Value)
+		case property.Name == "ATTENDEE":
+			current.Attendees = append(current.Attendees, icsAttendee{
+				Email:    icsMailto(property.Value),
+				Name:     property.Params["CN"],
+				PartStat: strings.ToUpper(property.Params["PARTSTAT"]),
+			})
+		case property.Name == "DURATION":
+			var err error
+			if duration, err = parseICSDuration(property.Value); err != nil {
+				return nil, err
+			}
+		case property.Name == "DTSTART":
+			start, allDay, err := parseICSTime(property)
+			if err != nil {
+				return nil, errors.New("invalid DTSTART: " + property.Value)
+			}
+			current.Start, current.AllDay = start, allDay
+		case property.Name == "DTEND":
+			end, _, err := parseICSTime(property)
+			if err != nil {
+				return nil, errors.New("invalid DTEND: " + property.Value)
+			}
+			current.End, hasEnd = end, true
+		}
+	}
+	return events, nil
+}
+
+// escapeICSText encodes a TEXT value
+func escapeICSText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(value)
+}
+
+// quoteICSParam quotes a parameter value, which cannot contain double quotes
+func quoteICSParam(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+}
+
+func formatICSDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+func formatICSTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// formatICSLocalTime formats a time as the wall-clock time of its location,
+// for properties with a TZID
+func formatICSLocalTime(t time.Time) string {
+	return t.Format("20060102T150405")
+}
+
+// formatICSOffset formats a UTC offset in seconds as +HHMM
+func formatICSOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// writeZoneObservance writes the period of a timezone starting when its
+// offset changed from offsetFrom to that of zoned
+func writeZoneObservance(w *icsWriter, zoned time.Time, offsetFrom int) {
+	name, offset := zoned.Zone()
+	kind := "STANDARD"
+	if zoned.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	w.line("BEGIN:" + kind)
+	// DTSTART is the wall-clock time the change happens at, before it
+	w.property("DTSTART", formatICSLocalTime(zoned.In(time.FixedZone("", offsetFrom))))
+	w.property("TZOFFSETFROM", formatICSOffset(offsetFrom))
+	w.property("TZOFFSETTO", formatICSOffset(offset))
+	w.property("TZNAME", escapeICSText(name))
+	w.line("END:" + kind)
+}
+
+// writeVTimezone writes an IANA timezone with the offset changes from the
+// period in effect at from through to, for times written with its TZID
+func writeVTimezone(w *icsWriter, loc *time.Location, from, to time.Time) {
+	w.line("BEGIN:VTIMEZONE")
+	w.property("TZID", loc.String())
+
+	current := from.In(loc)
+	start, end := current.ZoneBounds()
+	if start.IsZero() {
+		start = current
+	}
+	_, offsetFrom := start.Add(-time.Second).In(loc).Zone()
+	writeZoneObservance(w, start.In(loc), offsetFrom)
+	for !end.IsZero() && end.Before(to) {
+		_, offsetFrom = current.Zone()
+		current = end.In(loc)
+		writeZoneObservance(w, current, offsetFrom)
+		_, end = current.ZoneBounds()
+	}
+	w.line("END:VTIMEZONE")
+}
+
+// icsWriter builds an iCalendar stream with CRLF line endings, folding lines
+// longer than 75 octets without splitting characters
+type icsWriter struct {
+	b strings.Builder
+}
+
+func (w *icsWriter) line(line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the limit
+		limit = 74
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+// property writes NAME;PARAMS:value, with params already formatted as
+// KEY=value
+func (w *icsWriter) property(name, value string, params ...string) {
+	for _, param := range params {
+		name += ";" + param
+	}
+	w.line(name + ":" + value)
+}
+
+func (w *icsWriter) String() string {
+	return w.b.String()
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestICSWriterLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"short", "BEGIN:VEVENT", []string{"BEGIN:VEVENT"}},
+		{"empty", "", []string{""}},
+		{"exactly 75 octets", strings.Repeat("a", 75), []string{strings.Repeat("a", 75)}},
+		{"76 octets", strings.Repeat("a", 76), []string{strings.Repeat("a", 75), " a"}},
+		{
+			"several folds",
+			strings.Repeat("b", 75+74+10),
+			[]string{strings.Repeat("b", 75), " " + strings.Repeat("b", 74), " " + strings.Repeat("b", 10)},
+		},
+		{
+			// The three-octet character would straddle octet 75
+			"multibyte character at the limit",
+			strings.Repeat("c", 73) + "€" + "d",
+			[]string{strings.Repeat("c", 73), " €d"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &icsWriter{}
+			w.line(tt.line)
+			got := w.String()
+
+			want := strings.Join(tt.want, "\r\n") + "\r\n"
+			if got != want {
+				t.Errorf("line(%q) wrote %q, want %q", tt.line, got, want)
+			}
+			for _, folded := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+				if len(folded) > 75 {
+					t.Errorf("folded line %q is %d octets long", folded, len(folded))
+				}
+				if !utf8.ValidString(folded) {
+					t.Errorf("folded line %q splits a character", folded)
+				}
+			}
+			// Unfolding gives back the line
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(got, "\r\n"), "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolded %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestICSWriterProperty(t *testing.T) {
+	tests := []struct {
+		name   string
+		prop   string
+		value  string
+		params []string
+		want   string
+	}{
+		{"no params", "SUMMARY", "Standup", nil, "SUMMARY:Standup\r\n"},
+		{"one param", "DTSTART", "20260105T090000", []string{"TZID=Asia/Jakarta"}, "DTSTART;TZID=Asia/Jakarta:20260105T090000\r\n"},
+		{
+			"several params",
+			"ATTENDEE", "mailto:jane@example.com",
+			[]string{`CN="Jane Doe"`, "PARTSTAT=ACCEPTED"},
+			"ATTENDEE;CN=\"Jane Doe\";PARTSTAT=ACCEPTED:mailto:jane@example.com\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &icsWriter{}
+			w.property(tt.prop, tt.value, tt.params...)
+			if got := w.String(); got != tt.want {
+				t.Errorf("property wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseICSDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "PT1H", want: time.Hour},
+		{value: "PT1H30M", want: 90 * time.Minute},
+		{value: "PT45S", want: 45 * time.Second},
+		{value: "P1D", want: 24 * time.Hour},
+		{value: "P2W", want: 14 * 24 * time.Hour},
+		{value: "P1DT2H", want: 26 * time.Hour},
+		{value: "+PT15M", want: 15 * time.Minute},
+		{value: "PT0S", want: 0},
+		{value: "", wantErr: true},
+		{value: "P", wantErr: true},
+		{value: "PT", wantErr: true},
+		{value: "P1DT", wantErr: true},
+		{value: "1H", wantErr: true},
+		{value: "PT1", wantErr: true},
+		{value: "P1H", wantErr: true},
+		{value: "PT1D", wantErr: true},
+		{value: "PTH", wantErr: true},
+		{value: "-PT1H", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseICSDuration(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseICSDuration(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseICSDuration(%q) returned error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseICSDuration(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// icsCalendar wraps content lines in a VCALENDAR with CRLF line endings
+func icsCalendar(lines ...string) string {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...)
+	all = append(all, "END:VCALENDAR")
+	return strings.Join(all, "\r\n") + "\r\n"
+}
+
+func TestParseICS(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skip("timezone data is not available")
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []icsEvent
+		wantErr bool
+	}{
+		{
+			name: "timed event in UTC",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"UID:a@example.com",
+				"SUMMARY:Standup",
+				"DTSTART:20260105T020000Z",
+				"DTEND:20260105T021500Z",
+				"END:VEVENT",
+			),
+			want: []icsEvent{{
+				UID:     "a@example.com",
+				Summary: "Standup",
+				Start:   time.Date(2026, 1, 5, 2, 0, 0, 0, time.UTC),
+				End:     time.Date(2026, 1, 5, 2, 15, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "TZID, duration and escaped text",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"UID:b@example.com",
+				`SUMMARY:Planning\, Q1`,
+				`DESCRIPTION:Line one\nLine two`,
+				`LOCATION:Room 1\; 2nd floor`,
+				"DTSTART;TZID=Asia/Jakarta:20260105T090000",
+				"DURATION:PT1H30M",
+				"END:VEVENT",
+			),
+			want: []icsEvent{{
+				UID:         "b@example.com",
+				Summary:     "Planning, Q1",
+				Description: "Line one\nLine two",
+				Location:    "Room 1; 2nd floor",
+				Start:       time.Date(2026, 1, 5, 9, 0, 0, 0, jakarta),
+				End:         time.Date(2026, 1, 5, 10, 30, 0, 0, jakarta),
+			}},
+		},
+		{
+			name: "unknown TZID is UTC",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"UID:c@example.com",
+				"DTSTART;TZID=Mars/Olympus:20260105T090000",
+				"DTEND;TZID=Mars/Olympus:20260105T100000",
+				"END:VEVENT",
+			),
+			want: []icsEvent{{
+				UID:   "c@example.com",
+				Start: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "all-day event without an end lasts a day",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"UID:d@example.com",
+				"DTSTART;VALUE=DATE:20260817",
+				"END:VEVENT",
+			),
+			want: []icsEvent{{
+				UID:    "d@example.com",
+				Start:  time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC),
+				End:    time.Date(2026, 8, 18, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+			}},
+		},
+		{
+			name: "folded lines, organizer, attendees and recurrence",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"UID:e@example.com",
+				"SUMMARY:Weekly sync with a long",
+				"  title",
+				"DTSTART:20260105T020000Z",
+				"DTEND:20260105T030000Z",
+				"RRULE:freq=weekly;byday=MO",
+				"EXDATE:20260112T020000Z,20260119T020000Z",
+				"STATUS:confirmed",
+				"ORGANIZER;CN=Jane:MAILTO:Jane@Example.com",
+				`ATTENDEE;CN="Doe, John";PARTSTAT=accepted:mailto:john@example.com`,
+				"ATTENDEE:guest@example.com",
+				"END:VEVENT",
+			),
+			want: []icsEvent{{
+				UID:       "e@example.com",
+				Summary:   "Weekly sync with a long title",
+				Start:     time.Date(2026, 1, 5, 2, 0, 0, 0, time.UTC),
+				End:       time.Date(2026, 1, 5, 3, 0, 0, 0, time.UTC),
+				RRule:     "FREQ=WEEKLY;BYDAY=MO",
+				ExDates:   []time.Time{time.Date(2026, 1, 12, 2, 0, 0, 0, time.UTC), time.Date(2026, 1, 19, 2, 0, 0, 0, time.UTC)},
+				Status:    "CONFIRMED",
+				Organizer: "jane@example.com",
+				Attendees: []icsAttendee{
+					{Email: "john@example.com", Name: "Doe, John", PartStat: "ACCEPTED"},
+					{Email: "guest@example.com"},
+				},
+			}},
+		},
+		{
+			name: "changed occurrence and nested alarm",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"UID:f@example.com",
+				"RECURRENCE-ID;TZID=Asia/Jakarta:20260112T090000",
+				"DTSTART;TZID=Asia/Jakarta:20260112T100000",
+				"DTEND;TZID=Asia/Jakarta:20260112T110000",
+				"BEGIN:VALARM",
+				"DESCRIPTION:Reminder",
+				"TRIGGER:-PT15M",
+				"END:VALARM",
+				"END:VEVENT",
+			),
+			want: []icsEvent{{
+				UID:          "f@example.com",
+				Start:        time.Date(2026, 1, 12, 10, 0, 0, 0, jakarta),
+				End:          time.Date(2026, 1, 12, 11, 0, 0, 0, jakarta),
+				RecurrenceID: timePtr(time.Date(2026, 1, 12, 9, 0, 0, 0, jakarta)),
+			}},
+		},
+		{
+			name: "events without a start are skipped",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"UID:g@example.com",
+				"SUMMARY:No start",
+				"END:VEVENT",
+			),
+			want: nil,
+		},
+		{
+			name:    "not a calendar",
+			input:   "BEGIN:VCARD\r\nEND:VCARD\r\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty file",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name: "invalid start",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"DTSTART:tomorrow",
+				"END:VEVENT",
+			),
+			wantErr: true,
+		},
+		{
+			name: "invalid duration",
+			input: icsCalendar(
+				"BEGIN:VEVENT",
+				"DTSTART:20260105T020000Z",
+				"DURATION:1H",
+				"END:VEVENT",
+			),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseICS(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseICS returned %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseICS returned error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseICS returned %d events, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("event %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -1,29 +1,181 @@
 package services
 
 import (
+	"errors"
 	"task-management/models"
 	"task-management/repositories"
+	"time"
+
+	"gorm.io/gorm"
 )
 
+var ErrWIPLimitExceeded = errors.New("WIP limit exceeded for this status")
+
+// ErrRankTaken is returned when a concurrent move placed another issue between
+// the same neighbours; the client retries with the neighbours it reloads
+var ErrRankTaken = errors.New("another issue was just moved to this place, reload and try again")
+
+// rankAttempts bounds the retries when a concurrent create takes the rank
+// computed for a new issue
+const rankAttempts = 3
+
 type IssueService struct {
-	issueRepo  *repositories.IssueRepository
-	statusRepo *repositories.StatusRepository
+	issueRepo      *repositories.IssueRepository
+	statusRepo     *repositories.StatusRepository
+	wipLimitRepo   *repositories.WIPLimitRepository
+	sprintRepo     *repositories.SprintRepository
+	teamRepo       *repositories.TeamRepository
+	milestoneRepo  *repositories.MilestoneRepository
+	projectRepo    *repositories.ProjectRepository
+	labelRepo      *repositories.LabelRepository
+	renderService  *RenderService
+	holidayService *HolidayService
 }
 
-func NewIssueService(issueRepo *repositories.IssueRepository, statusRepo *repositories.StatusRepository) *IssueService {
+func NewIssueService(
+	issueRepo *repositories.IssueRepository,
+	statusRepo *repositories.StatusRepository,
+	wipLimitRepo *repositories.WIPLimitRepository,
+	sprintRepo *repositories.SprintRepository,
+	teamRepo *repositories.TeamRepository,
+	milestoneRepo *repositories.MilestoneRepository,
+	projectRepo *repositories.ProjectRepository,
+	labelRepo *repositories.LabelRepository,
+	renderService *RenderService,
+	holidayService *HolidayService,
+) *IssueService {
 	return &IssueService{
-		issueRepo:  issueRepo,
-		statusRepo: statusRepo,
+		issueRepo:      issueRepo,
+		statusRepo:     statusRepo,
+		wipLimitRepo:   wipLimitRepo,
+		sprintRepo:     sprintRepo,
+		teamRepo:       teamRepo,
+		milestoneRepo:  milestoneRepo,
+		projectRepo:    projectRepo,
+		labelRepo:      labelRepo,
+		renderService:  renderService,
+		holidayService: holidayService,
+	}
+}
+
+// checkLinks verifies that the milestone and project of the issue belong to
+// the organization of its team, and a team milestone to the team itself
+func (s *IssueService) checkLinks(issue *models.Issue) error {
+	if issue.MilestoneID == nil && issue.ProjectID == nil {
+		return nil
 	}
+	team, err := s.teamRepo.FindByID(issue.TeamID)
+	if err != nil {
+		return errors.New("team not found")
+	}
+	if issue.MilestoneID != nil {
+		milestone, err := s.milestoneRepo.FindByID(*issue.MilestoneID)
+		if err != nil || milestone.OrganizationID != team.OrganizationID ||
+			(milestone.TeamID != nil && *milestone.TeamID != team.ID) {
+			return errors.New("milestone not found")
+		}
+	}
+	if issue.ProjectID != nil {
+		project, err := s.projectRepo.FindByID(*issue.ProjectID)
+		if err != nil || project.OrganizationID != team.OrganizationID {
+			return errors.New("project not found")
+		}
+	}
+	return nil
 }
 
-func (s *IssueService) Create(issue *models.Issue, createdBy uint) error {
+// WIPCheck describes a status column that is at or over its WIP limit
+type WIPCheck struct {
+	StatusID  uint  `json:"status_id"`
+	MaxIssues int   `json:"max_issues"`
+	Count     int64 `json:"count"`
+	IsStrict  bool  `json:"is_strict"`
+}
+
+// MoveRequest positions an issue between two neighbours on the board.
+// AfterID is the issue directly above, BeforeID the issue directly below.
+type MoveRequest struct {
+	StatusID *uint `json:"status_id"`
+	AfterID  *uint `json:"after_id"`
+	BeforeID *uint `json:"before_id"`
+}
+
+func (s *IssueService) Create(issue *models.Issue, labelIDs []uint, createdBy uint) error {
 	issue.CreatedBy = createdBy
+	// Labels come as IDs, and are set once the issue exists
+	issue.Labels = nil
 
-	if err := s.issueRepo.Create(issue); err != nil {
+	if err := s.checkLinks(issue); err != nil {
+		return err
+	}
+	var labels []models.Label
+	if len(labelIDs) > 0 {
+		team, err := s.teamRepo.FindByID(issue.TeamID)
+		if err != nil {
+			return errors.New("team not found")
+		}
+		labels, err = s.labelRepo.FindByIDs(team.OrganizationID, labelIDs)
+		if err != nil {
+			return err
+		}
+		if len(labels) != len(labelIDs) {
+			return errors.New("unknown label")
+		}
+	}
+	if issue.SprintID != nil {
+		sprint, err := s.sprintRepo.FindByID(*issue.SprintID)
+		if err != nil || sprint.TeamID != issue.TeamID || sprint.State == models.SprintClosed {
+			return errors.New("sprint not found or already closed")
+		}
+	}
+
+	var err error
+	issue.DescriptionHTML, err = s.renderService.RenderForTeam(issue.Description, issue.TeamID)
+	if err != nil {
 		return err
 	}
 
+	// New issues go to the bottom of the backlog. Ranks are unique per team,
+	// so a rank taken by a concurrent create is computed again.
+	for attempt := 1; ; attempt++ {
+		lastRank, err := s.issueRepo.FindLastRank(issue.TeamID)
+		if err != nil {
+			return err
+		}
+		issue.Rank, err = rankBetween(lastRank, "")
+		if err != nil {
+			return err
+		}
+		err = s.issueRepo.Create(issue)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, gorm.ErrDuplicatedKey) || attempt == rankAttempts {
+			return err
+		}
+	}
+
+	if len(labels) > 0 {
+		if err := s.labelRepo.ReplaceIssueLabels(issue.ID, labels); err != nil {
+			return err
+		}
+		issue.Labels = labels
+	}
+
+	// Creating an issue directly in a sprint counts as a scope change
+	if issue.SprintID != nil {
+		change := &models.SprintScopeChange{
+			SprintID:   *issue.SprintID,
+			IssueID:    issue.ID,
+			ChangeType: models.ScopeAdded,
+			Reason:     "Created in sprint",
+			ChangedBy:  &createdBy,
+		}
+		if err := s.sprintRepo.CreateScopeChange(change); err != nil {
+			return err
+		}
+	}
+
 	// Log activity
 	activity := &models.IssueActivity{
 		IssueID:      issue.ID,
@@ -34,26 +186,110 @@ func (s *IssueService) Create(issue *models.Issue, createdBy uint) error {
 	return s.issueRepo.CreateActivity(activity)
 }
 
+// GetByID returns an issue, rendering and caching its description when it
+// was written before descriptions were rendered
 func (s *IssueService) GetByID(id uint) (*models.Issue, error) {
-	return s.issueRepo.FindByID(id)
+	issue, err := s.issueRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if issue.DescriptionHTML == "" && issue.Description != "" {
+		rendered, err := s.renderService.Render(issue.Description, issue.Team.OrganizationID)
+		if err == nil && s.issueRepo.UpdateDescriptionHTML(issue.ID, rendered) == nil {
+			issue.DescriptionHTML = rendered
+		}
+	}
+	return issue, nil
 }
 
 func (s *IssueService) GetByTeam(teamID uint) ([]models.Issue, error) {
 	return s.issueRepo.FindByTeam(teamID)
 }
 
+// issueSortColumns maps the sort keys accepted by issue queries to columns
+var issueSortColumns = map[string]string{
+	"rank":       "rank",
+	"title":      "title",
+	"priority":   "priority",
+	"deadline":   "deadline",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"estimate":   "estimate_points",
+}
+
+// validateIssueQuery checks the filter dates and sort options of an issue query
+func validateIssueQuery(filter *models.IssueFilter, sortBy, sortOrder string) error {
+	if _, err := parseOptionalDate(filter.DeadlineFrom, "deadline_from"); err != nil {
+		return err
+	}
+	if _, err := parseOptionalDate(filter.DeadlineTo, "deadline_to"); err != nil {
+		return err
+	}
+	if sortBy != "" {
+		if _, ok := issueSortColumns[sortBy]; !ok {
+			return errors.New("invalid sort field")
+		}
+	}
+	if sortOrder != "" && sortOrder != "asc" && sortOrder != "desc" {
+		return errors.New("sort order must be asc or desc")
+	}
+	return nil
+}
+
+// Search lists a team's issues matching the filter, sorted by sortBy (rank by default)
+func (s *IssueService) Search(teamID uint, filter *models.IssueFilter, sortBy, sortOrder string) ([]models.Issue, error) {
+	if err := validateIssueQuery(filter, sortBy, sortOrder); err != nil {
+		return nil, err
+	}
+	column, ok := issueSortColumns[sortBy]
+	if !ok {
+		column = "rank"
+	}
+	return s.issueRepo.Search(teamID, filter, column, sortOrder == "desc")
+}
+
 func (s *IssueService) Update(issue *models.Issue) error {
+	// Rank is only changed through Move, sprint membership through the sprint endpoints
+	existing, err := s.issueRepo.FindByID(issue.ID)
+	if err != nil {
+		return err
+	}
+	issue.Rank = existing.Rank
+	issue.SprintID = existing.SprintID
+	// Labels are managed through their own endpoint
+	issue.Labels = nil
+	if err := s.checkLinks(issue); err != nil {
+		return err
+	}
+	// The rendered description is always produced server-side
+	issue.DescriptionHTML, err = s.renderService.RenderForTeam(issue.Description, existing.TeamID)
+	if err != nil {
+		return err
+	}
 	return s.issueRepo.Update(issue)
 }
 
+// DeadlineInBusinessDays returns the date the given number of business days
+// after today, by the calendar of the team's organization
+func (s *IssueService) DeadlineInBusinessDays(teamID uint, days int) (time.Time, error) {
+	return s.holidayService.AddTeamBusinessDays(teamID, calendarDay(time.Now()), days)
+}
+
 func (s *IssueService) Delete(id uint) error {
 	return s.issueRepo.Delete(id)
 }
 
-func (s *IssueService) UpdateStatus(issueID, newStatusID, userID uint) error {
+// UpdateStatus changes the status of an issue. A non-nil WIPCheck is returned
+// when the target column is over a non-strict WIP limit.
+func (s *IssueService) UpdateStatus(issueID, newStatusID, userID uint) (*WIPCheck, error) {
 	issue, err := s.issueRepo.FindByID(issueID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	warning, err := s.checkWIPLimit(issue, newStatusID)
+	if err != nil {
+		return warning, err
 	}
 
 	oldStatusID := issue.StatusID
@@ -61,9 +297,115 @@ func (s *IssueService) UpdateStatus(issueID, newStatusID, userID uint) error {
 	// Update status
 	issue.StatusID = &newStatusID
 	if err := s.issueRepo.Update(issue); err != nil {
-		return err
+		return nil, err
+	}
+
+	return warning, s.logStatusChange(issueID, oldStatusID, newStatusID, userID)
+}
+
+// Move reorders an issue in the backlog and optionally moves it to another
+// status column. Only the moved issue's row is updated.
+func (s *IssueService) Move(issueID, userID uint, req *MoveRequest) (*WIPCheck, error) {
+	issue, err := s.issueRepo.FindByID(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	var warning *WIPCheck
+	var newStatusID *uint
+	if req.StatusID != nil && (issue.StatusID == nil || *issue.StatusID != *req.StatusID) {
+		warning, err = s.checkWIPLimit(issue, *req.StatusID)
+		if err != nil {
+			return warning, err
+		}
+		newStatusID = req.StatusID
+	}
+
+	// The neighbours' ranks stay the same, so computing the rank again would
+	// collide again
+	rank, err := s.moveRank(issue, req)
+	if err != nil {
+		return nil, err
+	}
+	err = s.issueRepo.UpdatePosition(issueID, rank, newStatusID)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrRankTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if newStatusID != nil {
+		return warning, s.logStatusChange(issueID, issue.StatusID, *newStatusID, userID)
+	}
+	return nil, nil
+}
+
+// moveRank returns the rank between the neighbours of a move, or the issue's
+// own rank when it keeps its place
+func (s *IssueService) moveRank(issue *models.Issue, req *MoveRequest) (string, error) {
+	if req.AfterID == nil && req.BeforeID == nil {
+		return issue.Rank, nil
+	}
+	prevRank, err := s.neighbourRank(issue.TeamID, req.AfterID)
+	if err != nil {
+		return "", err
+	}
+	nextRank, err := s.neighbourRank(issue.TeamID, req.BeforeID)
+	if err != nil {
+		return "", err
+	}
+	rank, err := rankBetween(prevRank, nextRank)
+	if err != nil {
+		return "", errors.New("after_id must be ranked above before_id")
+	}
+	return rank, nil
+}
+
+func (s *IssueService) neighbourRank(teamID uint, issueID *uint) (string, error) {
+	if issueID == nil {
+		return "", nil
+	}
+	neighbour, err := s.issueRepo.FindByID(*issueID)
+	if err != nil {
+		return "", errors.New("neighbour issue not found")
+	}
+	if neighbour.TeamID != teamID {
+		return "", errors.New("neighbour issue belongs to another team")
+	}
+	return neighbour.Rank, nil
+}
+
+// checkWIPLimit verifies that moving the issue into statusID respects the
+// team's WIP limit. Strict limits return ErrWIPLimitExceeded.
+func (s *IssueService) checkWIPLimit(issue *models.Issue, statusID uint) (*WIPCheck, error) {
+	limit, err := s.wipLimitRepo.FindByTeamAndStatus(issue.TeamID, statusID)
+	if err != nil {
+		return nil, nil // No limit configured
+	}
+
+	count, err := s.issueRepo.CountByStatus(issue.TeamID, statusID, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if count < int64(limit.MaxIssues) {
+		return nil, nil
 	}
 
+	check := &WIPCheck{
+		StatusID:  statusID,
+		MaxIssues: limit.MaxIssues,
+		Count:     count,
+		IsStrict:  limit.IsStrict,
+	}
+	if limit.IsStrict {
+		return check, ErrWIPLimitExceeded
+	}
+	return check, nil
+}
+
+func (s *IssueService) logStatusChange(issueID uint, oldStatusID *uint, newStatusID, userID uint) error {
 	// Log status change
 	statusLog := &models.IssueStatusLog{
 		IssueID:      issueID,
@@ -125,9 +467,3 @@ func (s *IssueService) Resume(issueID, userID uint) error {
 func (s *IssueService) GetActivities(issueID uint) ([]models.IssueActivity, error) {
 	return s.issueRepo.GetActivities(issueID)
 }
-
-func (s *IssueService) LogWork(issueID, userID uint, log *models.IssueWorkLog) error {
-	log.IssueID = issueID
-	log.UserID = userID
-	return s.issueRepo.CreateWorkLog(log)
-}
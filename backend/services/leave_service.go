@@ -0,0 +1,273 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+// activeLeave are the statuses of leave that still takes the days
+var activeLeave = []models.LeaveStatus{models.LeavePending, models.LeaveApproved}
+
+var leaveTypes = map[models.LeaveType]bool{
+	models.LeaveVacation: true,
+	models.LeaveSick:     true,
+	models.LeavePersonal: true,
+	models.LeaveUnpaid:   true,
+}
+
+type LeaveService struct {
+	leaveRepo           *repositories.LeaveRepository
+	teamRepo            *repositories.TeamRepository
+	userRepo            *repositories.UserRepository
+	notificationService *NotificationService
+	holidayService      *HolidayService
+}
+
+func NewLeaveService(
+	leaveRepo *repositories.LeaveRepository,
+	teamRepo *repositories.TeamRepository,
+	userRepo *repositories.UserRepository,
+	notificationService *NotificationService,
+	holidayService *HolidayService,
+) *LeaveService {
+	return &LeaveService{
+		leaveRepo:           leaveRepo,
+		teamRepo:            teamRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		holidayService:      holidayService,
+	}
+}
+
+type LeaveInput struct {
+	Type      models.LeaveType `json:"type" binding:"required"`
+	StartDate string           `json:"start_date" binding:"required"`
+	EndDate   string           `json:"end_date" binding:"required"`
+	Reason    string           `json:"reason"`
+}
+
+type MemberAbsence struct {
+	UserID   uint   `json:"user_id"`
+	FullName string `json:"full_name"`
+	// DaysAway counts the business days of approved leave within the range
+	DaysAway int                   `json:"days_away"`
+	Leave    []models.LeaveRequest `json:"leave"`
+}
+
+type TeamAbsences struct {
+	TeamID  uint            `json:"team_id"`
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Members []MemberAbsence `json:"members"`
+}
+
+func formatLeaveDates(leave *models.LeaveRequest) string {
+	if leave.StartDate.Equal(leave.EndDate) {
+		return leave.StartDate.Format("Jan 2")
+	}
+	return fmt.Sprintf("%s to %s", leave.StartDate.Format("Jan 2"), leave.EndDate.Format("Jan 2"))
+}
+
+// Request files a pending leave request and notifies the managers of the
+// user's teams
+func (s *LeaveService) Request(userID uint, input *LeaveInput) (*models.LeaveRequest, error) {
+	if !leaveTypes[input.Type] {
+		return nil, errors.New("type must be vacation, sick, personal or unpaid")
+	}
+	start, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start_date format (use YYYY-MM-DD)")
+	}
+	end, err := time.Parse("2006-01-02", input.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end_date format (use YYYY-MM-DD)")
+	}
+	if end.Before(start) {
+		return nil, errors.New("end date must not be before start date")
+	}
+
+	overlapping, err := s.leaveRepo.FindOverlapping([]uint{userID}, activeLeave, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(overlapping) > 0 {
+		return nil, errors.New("leave overlaps an existing request")
+	}
+
+	leave := &models.LeaveRequest{
+		UserID:    userID,
+		Type:      input.Type,
+		StartDate: start,
+		EndDate:   end,
+		Reason:    strings.TrimSpace(input.Reason),
+		Status:    models.LeavePending,
+	}
+	if err := s.leaveRepo.Create(leave); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	managerIDs, err := s.teamRepo.FindManagerIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	message := fmt.Sprintf("%s requested %s leave for %s", user.FullName, leave.Type, formatLeaveDates(leave))
+	for _, managerID := range managerIDs {
+		if err := s.notificationService.Notify(&models.Notification{
+			UserID:  managerID,
+			Type:    models.NotificationLeaveRequested,
+			ActorID: &userID,
+			Message: message,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return s.leaveRepo.FindByID(leave.ID)
+}
+
+func (s *LeaveService) GetByID(id uint) (*models.LeaveRequest, error) {
+	return s.leaveRepo.FindByID(id)
+}
+
+func (s *LeaveService) GetForUser(userID uint) ([]models.LeaveRequest, error) {
+	return s.leaveRepo.FindByUser(userID)
+}
+
+// CanReview reports whether the reviewer manages one of the user's teams
+func (s *LeaveService) CanReview(reviewerID, userID uint) bool {
+	managerIDs, err := s.teamRepo.FindManagerIDs(userID)
+	if err != nil {
+		return false
+	}
+	for _, managerID := range managerIDs {
+		if managerID == reviewerID {
+			return true
+		}
+	}
+	return false
+}
+
+// Review approves or rejects a pending leave request and notifies its owner
+func (s *LeaveService) Review(leave *models.LeaveRequest, reviewerID uint, status models.LeaveStatus, comment string) error {
+	if leave.Status != models.LeavePending {
+		return errors.New("only pending leave can be reviewed")
+	}
+	if leave.UserID == reviewerID {
+		return errors.New("you cannot review your own leave")
+	}
+	comment = strings.TrimSpace(comment)
+	if status == models.LeaveRejected && comment == "" {
+		return errors.New("a comment is required when rejecting leave")
+	}
+
+	now := time.Now()
+	leave.Status = status
+	leave.ReviewedBy = &reviewerID
+	leave.ReviewedAt = &now
+	leave.ReviewComment = comment
+	if err := s.leaveRepo.Save(leave); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Your %s leave for %s was %s", leave.Type, formatLeaveDates(leave), status)
+	if comment != "" {
+		message += ": " + comment
+	}
+	return s.notificationService.Notify(&models.Notification{
+		UserID:  leave.UserID,
+		Type:    models.NotificationLeaveReviewed,
+		ActorID: &reviewerID,
+		Message: message,
+	})
+}
+
+// Cancel withdraws pending leave, or approved leave that has not ended yet
+func (s *LeaveService) Cancel(leave *models.LeaveRequest) error {
+	switch leave.Status {
+	case models.LeavePending:
+	case models.LeaveApproved:
+		if calendarDay(leave.EndDate).Before(calendarDay(time.Now())) {
+			return errors.New("leave that has ended cannot be cancelled")
+		}
+	default:
+		return errors.New("only pending or approved leave can be cancelled")
+	}
+	leave.Status = models.LeaveCancelled
+	return s.leaveRepo.Save(leave)
+}
+
+// ApprovedBetween lists the approved leave of the users overlapping the range
+func (s *LeaveService) ApprovedBetween(userIDs []uint, from, to time.Time) ([]models.LeaveRequest, error) {
+	return s.leaveRepo.FindOverlapping(userIDs, []models.LeaveStatus{models.LeaveApproved}, from, to)
+}
+
+// GetTeamAbsences lists the pending and approved leave of each team member
+// overlapping the range
+func (s *LeaveService) GetTeamAbsences(teamID uint, from, to time.Time) (*TeamAbsences, error) {
+	from, to = calendarDay(from), calendarDay(to)
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, errors.New("team not found")
+	}
+	calendar, err := s.holidayService.BusinessCalendar(team.OrganizationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.teamRepo.GetMembers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	leaves, err := s.leaveRepo.FindOverlapping(userIDs, activeLeave, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint][]models.LeaveRequest)
+	for _, leave := range leaves {
+		byUser[leave.UserID] = append(byUser[leave.UserID], leave)
+	}
+
+	absences := &TeamAbsences{
+		TeamID:  teamID,
+		From:    from.Format("2006-01-02"),
+		To:      to.Format("2006-01-02"),
+		Members: make([]MemberAbsence, 0, len(members)),
+	}
+	for i := range members {
+		user := &members[i].User
+		row := MemberAbsence{
+			UserID:   members[i].UserID,
+			FullName: user.FullName,
+			Leave:    byUser[members[i].UserID],
+		}
+		if row.Leave == nil {
+			row.Leave = []models.LeaveRequest{}
+		}
+		for j := range row.Leave {
+			if row.Leave[j].Status != models.LeaveApproved {
+				continue
+			}
+			for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+				if row.Leave[j].Covers(day) && calendar.IsBusinessDay(day) {
+					row.DaysAway++
+				}
+			}
+		}
+		absences.Members = append(absences.Members, row)
+	}
+	return absences, nil
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// markdownLinker resolves issue references and mentions while rendering.
+// Unresolved references are rendered as plain text.
+type markdownLinker struct {
+	issueURL   func(id uint) (string, bool)
+	mentionURL func(token string) (string, bool)
+}
+
+// renderMarkdown converts a Markdown subset to HTML. Raw HTML in the input is
+// always escaped and only http, https and mailto links are emitted, so the
+// output is safe to insert into a page.
+//
+// Supported: headings, paragraphs with hard line breaks, fenced code blocks,
+// block quotes, bullet, numbered and task lists, horizontal rules, inline code,
+// bold, italic, strikethrough, links, bare URLs, #123 issue references and
+// @mentions.
+func renderMarkdown(text string, linker *markdownLinker) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\x00", "")
+	r := &markdownRenderer{linker: linker}
+	return r.blocks(strings.Split(text, "\n"), 0)
+}
+
+type markdownRenderer struct {
+	linker *markdownLinker
+}
+
+const maxQuoteDepth = 5
+
+var (
+	mdHeading     = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	mdRule        = regexp.MustCompile(`^\s*([-*_])(\s*([-*_]))\s*([-*_])[-*_\s]*$`)
+	mdFence       = regexp.MustCompile("^\\s*```\\s*([A-Za-z0-9_+-]*)")
+	mdBullet      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdNumbered    = regexp.MustCompile(`^\s*\d{1,9}[.)]\s+(.*)$`)
+	mdTask        = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdQuote       = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	mdCodeSpan    = regexp.MustCompile("`([^`]+)`")
+	mdLink        = regexp.MustCompile(`\[([^\[\]]+)\]\(\s*([^()\s]+)\s*\)`)
+	mdBareURL     = regexp.MustCompile("https?://[^\\s<>\"\x00]+")
+	mdIssueRef    = regexp.MustCompile(`(^|[^\w&#/])#(\d+)\b`)
+	mdBold        = regexp.MustCompile(`\*\*([^*]+?)\*\*|__([^_]+?)__`)
+	mdItalic      = regexp.MustCompile(`\*([^*\s][^*]*?)\*|\b_([^_\s][^_]*?)_\b`)
+	mdStrike      = regexp.MustCompile(`~~([^~]+?)~~`)
+	mdPlaceholder = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+func (r *markdownRenderer) blocks(lines []string, depth int) string {
+	var b strings.Builder
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		rendered := make([]string, len(paragraph))
+		for i, line := range paragraph {
+			rendered[i] = r.inline(strings.TrimSpace(line))
+		}
+		b.WriteString("<p>" + strings.Join(rendered, "<br>\n") + "</p>\n")
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			flush()
+			var code []string
+			for i++; i < len(lines) && !mdFence.MatchString(lines[i]); i++ {
+				code = append(code, lines[i])
+			}
+			class := ""
+			if m[1] != "" {
+				class = ` class="language-` + m[1] + `"`
+			}
+			b.WriteString("<pre><code" + class + ">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			level := len(m[1])
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, r.inline(m[2]), level)
+			continue
+		}
+
+		if mdRule.MatchString(line) {
+			flush()
+			b.WriteString("<hr>\n")
+			continue
+		}
+
+		if mdQuote.MatchString(line) {
+			flush()
+			var quoted []string
+			for ; i < len(lines); i++ {
+				m := mdQuote.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				quoted = append(quoted, m[1])
+			}
+			i--
+			if depth >= maxQuoteDepth {
+				b.WriteString("<blockquote><p>" + r.inline(strings.Join(quoted, " ")) + "</p></blockquote>\n")
+			} else {
+				b.WriteString("<blockquote>\n" + r.blocks(quoted, depth+1) + "</blockquote>\n")
+			}
+			continue
+		}
+
+		if mdBullet.MatchString(line) || mdNumbered.MatchString(line) {
+			flush()
+			pattern, tag := mdBullet, "ul"
+			if !mdBullet.MatchString(line) {
+				pattern, tag = mdNumbered, "ol"
+			}
+			b.WriteString("<" + tag + ">\n")
+			for ; i < len(lines); i++ {
+				m := pattern.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				b.WriteString("<li>" + r.listItem(m[1]) + "</li>\n")
+			}
+			i--
+			b.WriteString("</" + tag + ">\n")
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+	flush()
+
+	return b.String()
+}
+
+func (r *markdownRenderer) listItem(content string) string {
+	if m := mdTask.FindStringSubmatch(content); m != nil {
+		checked := ""
+		if m[1] != " " {
+			checked = " checked"
+		}
+		return `<input type="checkbox" disabled` + checked + `> ` + r.inline(m[2])
+	}
+	return r.inline(content)
+}
+
+// inline renders a single line. Elements that must not be touched by
+// emphasis (code, links, references) are swapped for placeholders first.
+func (r *markdownRenderer) inline(text string) string {
+	var held []string
+	hold := func(fragment string) string {
+		held = append(held, fragment)
+		return "\x00" + strconv.Itoa(len(held)-1) + "\x00"
+	}
+
+	text = mdCodeSpan.ReplaceAllStringFunc(text, func(match string) string {
+		code := mdCodeSpan.FindStringSubmatch(match)[1]
+		return hold("<code>" + html.EscapeString(code) + "</code>")
+	})
+
+	text = mdLink.ReplaceAllStringFunc(text, func(match string) string {
+		m := mdLink.FindStringSubmatch(match)
+		if !isSafeURL(m[2]) {
+			return match
+		}
+		return hold(linkTag(m[2], r.emphasis(html.EscapeString(m[1]))))
+	})
+
+	text = mdBareURL.ReplaceAllStringFunc(text, func(match string) string {
+		link := strings.TrimRight(match, ".,;:!?)]'")
+		if !isSafeURL(link) {
+			return match
+		}
+		return hold(linkTag(link, html.EscapeString(link))) + match[len(link):]
+	})
+
+	if r.linker != nil && r.linker.mentionURL != nil {
+		text = mentionPattern.ReplaceAllStringFunc(text, func(match string) string {
+			m := mentionPattern.FindStringSubmatch(match)
+			token := strings.TrimRight(m[1], ".-")
+			href, ok := r.linker.mentionURL(strings.ToLower(token))
+			if !ok {
+				return match
+			}
+			prefix := match[:len(match)-len(m[1])-1]
+			suffix := m[1][len(token):]
+			return prefix + hold(`<a href="`+html.EscapeString(href)+`" class="mention">@`+html.EscapeString(token)+`</a>`) + suffix
+		})
+	}
+
+	if r.linker != nil && r.linker.issueURL != nil {
+		text = mdIssueRef.ReplaceAllStringFunc(text, func(match string) string {
+			m := mdIssueRef.FindStringSubmatch(match)
+			id, err := strconv.ParseUint(m[2], 10, 32)
+			if err != nil {
+				return match
+			}
+			href, ok := r.linker.issueURL(uint(id))
+			if !ok {
+				return match
+			}
+			return m[1] + hold(`<a href="`+html.EscapeString(href)+`" class="issue-ref">#`+m[2]+`</a>`)
+		})
+	}
+
+	text = r.emphasis(html.EscapeString(text))
+
+	// Held fragments can hold earlier ones, e.g. code in link text, so
+	// placeholders are expanded until none are left
+	for mdPlaceholder.MatchString(text) {
+		text = mdPlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+			i, _ := strconv.Atoi(mdPlaceholder.FindStringSubmatch(match)[1])
+			return held[i]
+		})
+	}
+	return text
+}
+
+// emphasis applies bold, italic and strikethrough to already escaped text
+func (r *markdownRenderer) emphasis(escaped string) string {
+	escaped = mdBold.ReplaceAllString(escaped, "<strong>$1$2</strong>")
+	escaped = mdStrike.ReplaceAllString(escaped, "<del>$1</del>")
+	escaped = mdItalic.ReplaceAllString(escaped, "<em>$1$2</em>")
+	return escaped
+}
+
+func linkTag(href, content string) string {
+	return `<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer" target="_blank">` + content + `</a>`
+}
+
+// isSafeURL only allows absolute http(s) and mailto links
+func isSafeURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	}
+	return false
+}
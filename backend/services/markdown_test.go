@@ -0,0 +1,53 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownInline(t *testing.T) {
+	linker := &markdownLinker{
+		issueURL: func(id uint) (string, bool) {
+			return "/issues/7", id == 7
+		},
+		mentionURL: func(token string) (string, bool) {
+			return "/users/alice", token == "alice"
+		},
+	}
+	link := func(href, content string) string {
+		return `<a href="` + href + `" rel="nofollow noopener noreferrer" target="_blank">` + content + `</a>`
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"emphasis", "**bold**, *italic* and ~~gone~~", "<strong>bold</strong>, <em>italic</em> and <del>gone</del>"},
+		{"raw HTML is escaped", "<script>alert(1)</script>", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"code span", "run `rm -rf *bin*`", "run <code>rm -rf *bin*</code>"},
+		{"link", "[docs](https://x)", link("https://x", "docs")},
+		{"unsafe link", "[click](javascript:alert(1))", "[click](javascript:alert(1))"},
+		{"emphasis in link text", "[**docs**](https://x)", link("https://x", "<strong>docs</strong>")},
+		{"code in link text", "[`code`](https://x)", link("https://x", "<code>code</code>")},
+		{"code in link text with more", "[see `a` and `b`](https://x)", link("https://x", "see <code>a</code> and <code>b</code>")},
+		{"bare URL", "see https://x.test/a.", "see " + link("https://x.test/a", "https://x.test/a") + "."},
+		{"bare URL before code", "https://x.test/`code`", link("https://x.test/", "https://x.test/") + "<code>code</code>"},
+		{"bare URL after a link", "[a](https://y)https://x", link("https://y", "a") + link("https://x", "https://x")},
+		{"issue reference", "fixes #7, not #8", `fixes <a href="/issues/7" class="issue-ref">#7</a>, not #8`},
+		{"mention", "thanks @alice and @bob", `thanks <a href="/users/alice" class="mention">@alice</a> and @bob`},
+		{"no mention in link text", "[@alice](https://x)", link("https://x", "@alice")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderMarkdown(tt.text, linker)
+			want := "<p>" + tt.want + "</p>\n"
+			if got != want {
+				t.Errorf("renderMarkdown(%q) =\n%q\nwant\n%q", tt.text, got, want)
+			}
+			if strings.Contains(got, "\x00") {
+				t.Errorf("renderMarkdown(%q) left a placeholder in %q", tt.text, got)
+			}
+		})
+	}
+}
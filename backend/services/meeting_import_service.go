@@ -0,0 +1,382 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MeetingImportService struct {
+	meetingRepo *repositories.MeetingRepository
+	userRepo    *repositories.UserRepository
+	teamRepo    *repositories.TeamRepository
+}
+
+func NewMeetingImportService(
+	meetingRepo *repositories.MeetingRepository,
+	userRepo *repositories.UserRepository,
+	teamRepo *repositories.TeamRepository,
+) *MeetingImportService {
+	return &MeetingImportService{
+		meetingRepo: meetingRepo,
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+	}
+}
+
+// MeetingImportItem reports what happened to one event of an imported file
+type MeetingImportItem struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	MeetingID uint   `json:"meeting_id,omitempty"`
+	// Reason explains why the event was skipped
+	Reason             string   `json:"reason,omitempty"`
+	Warnings           []string `json:"warnings,omitempty"`
+	UnmatchedAttendees []string `json:"unmatched_attendees,omitempty"`
+}
+
+type MeetingImportResult struct {
+	Imported []MeetingImportItem `json:"imported"`
+	Updated  []MeetingImportItem `json:"updated"`
+	// Cancelled lists the meetings deleted because their event was cancelled
+	Cancelled []MeetingImportItem `json:"cancelled"`
+	Skipped   []MeetingImportItem `json:"skipped"`
+}
+
+var icsAttendeeStatus = map[string]models.AttendeeStatus{
+	"ACCEPTED": models.AttendeeStatusAccepted,
+	"DECLINED": models.AttendeeStatusDeclined,
+}
+
+// icsRecurrence maps an RRULE to the recurrence of a meeting starting at the
+// given local time. Rules it cannot express return nil and a warning, and the
+// event is imported as a single meeting.
+func icsRecurrence(rrule string, start time.Time) (*RecurrenceInput, string) {
+	unsupported := "recurrence " + rrule + " is not supported; imported as a single meeting"
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rrule, ";") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			parts[key] = value
+		}
+	}
+
+	input := &RecurrenceInput{IsRecurring: true}
+	var pattern models.RecurringPattern
+	switch parts["FREQ"] {
+	case "DAILY":
+		pattern = models.RecurringDaily
+	case "WEEKLY":
+		pattern = models.RecurringWeekly
+	case "MONTHLY":
+		pattern = models.RecurringMonthly
+	default:
+		return nil, unsupported
+	}
+	input.RecurringPattern = &pattern
+
+	for key, value := range parts {
+		switch key {
+		case "FREQ", "WKST":
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, unsupported
+			}
+			input.Interval = interval
+		case "BYDAY":
+			// Weekdays with an ordinal, such as 2TU, are not supported
+			if pattern != models.RecurringWeekly {
+				return nil, unsupported
+			}
+			input.ByDay = strings.Split(value, ",")
+		case "BYMONTHDAY":
+			if pattern != models.RecurringMonthly || value != strconv.Itoa(start.Day()) {
+				return nil, unsupported
+			}
+		case "UNTIL":
+			until, allDay, err := parseICSTime(icsProperty{Value: value})
+			if err != nil {
+				return nil, unsupported
+			}
+			if !allDay {
+				until = until.In(start.Location())
+			}
+			input.Until = until.Format("2006-01-02")
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, unsupported
+			}
+			input.Count = &count
+		default:
+			return nil, unsupported
+		}
+	}
+	return input, ""
+}
+
+// icsExceptions maps the cancelled occurrences (EXDATE) of a recurring event
+// and its changed occurrences (RECURRENCE-ID) to exceptions of its meeting.
+// Occurrences the meeting does not have are left out with a warning.
+func icsExceptions(meeting *models.Meeting, event *icsEvent, overrides []*icsEvent) ([]models.MeetingException, []string) {
+	if !meeting.IsRecurring {
+		if len(event.ExDates) > 0 || len(overrides) > 0 {
+			return nil, []string{"changed and cancelled occurrences are ignored for single meetings"}
+		}
+		return nil, nil
+	}
+
+	zone := meeting.Zone()
+	var exceptions []models.MeetingException
+	var warnings []string
+	byDate := make(map[time.Time]int)
+	add := func(original time.Time, exception models.MeetingException) {
+		date := calendarDay(original.In(zone))
+		if !isOccurrenceDate(meeting, date) {
+			warnings = append(warnings, "there is no occurrence on "+date.Format("2006-01-02")+" to change or cancel")
+			return
+		}
+		exception.OccurrenceDate = date
+		// A changed occurrence replaces an EXDATE on the same date
+		if i, ok := byDate[date]; ok {
+			exceptions[i] = exception
+			return
+		}
+		byDate[date] = len(exceptions)
+		exceptions = append(exceptions, exception)
+	}
+
+	for _, exDate := range event.ExDates {
+		add(exDate, models.MeetingException{Cancelled: true})
+	}
+	for _, override := range overrides {
+		if override.Status == "CANCELLED" {
+			add(*override.RecurrenceID, models.MeetingException{Cancelled: true})
+			continue
+		}
+		if override.AllDay || !override.End.After(override.Start) || override.End.Sub(override.Start) >= 24*time.Hour {
+			date := calendarDay(override.RecurrenceID.In(zone)).Format("2006-01-02")
+			warnings = append(warnings, "the changed occurrence on "+date+" must end after it starts and last less than 24 hours; left unchanged")
+			continue
+		}
+		title := strings.TrimSpace(override.Summary)
+		if title == "" {
+			title = meeting.Title
+		}
+		description, location := override.Description, override.Location
+		start, end := override.Start, override.End
+		add(*override.RecurrenceID, models.MeetingException{
+			Title:       &title,
+			Description: &description,
+			Location:    &location,
+			StartsAt:    &start,
+			EndsAt:      &end,
+		})
+	}
+	return exceptions, warnings
+}
+
+// Import creates the team's meetings from the events of an iCalendar file,
+// with the given user as organizer. Events are matched on UID, so importing a
+// file again updates the meetings imported from it, or deletes them when their
+// event was cancelled. Only the user who imported a meeting, or a team
+// manager, can change it that way. Attendees are matched to users of the
+// team's organization by email. Meetings keep the timezone of their event, or
+// take that of their creator when it is given in UTC. Cancelled (EXDATE) and
+// changed (RECURRENCE-ID) occurrences of recurring events replace the
+// meeting's changes to single occurrences. The meetings are saved
+// in one transaction, so a failed import changes nothing.
+func (s *MeetingImportService) Import(teamID, importedBy uint, r io.Reader) (*MeetingImportResult, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, errors.New("team not found")
+	}
+	importer, err := s.userRepo.FindByID(importedBy)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	events, err := parseICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.FindByOrganization(team.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	byEmail := make(map[string]*models.User)
+	for i := range users {
+		byEmail[strings.ToLower(users[i].Email)] = &users[i]
+	}
+	isManager := false
+	if member, err := s.teamRepo.GetMemberRole(teamID, importedBy); err == nil {
+		isManager = member.Role == models.RoleManager
+	}
+
+	result := &MeetingImportResult{
+		Imported:  []MeetingImportItem{},
+		Updated:   []MeetingImportItem{},
+		Cancelled: []MeetingImportItem{},
+		Skipped:   []MeetingImportItem{},
+	}
+	// Items are reported once their meeting, at the same index of imports,
+	// is saved
+	var imports []repositories.ImportedMeeting
+	var items []MeetingImportItem
+	var lists []*[]MeetingImportItem
+
+	// Changed occurrences are imported with the recurring event they share
+	// their UID with
+	recurring := make(map[string]bool)
+	overrides := make(map[string][]*icsEvent)
+	for i := range events {
+		if events[i].UID == "" {
+			continue
+		}
+		if events[i].RecurrenceID == nil {
+			recurring[events[i].UID] = true
+		} else {
+			overrides[events[i].UID] = append(overrides[events[i].UID], &events[i])
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range events {
+		event := &events[i]
+		if event.RecurrenceID != nil && recurring[event.UID] {
+			continue
+		}
+		item := MeetingImportItem{UID: event.UID, Title: strings.TrimSpace(event.Summary)}
+		if item.Title == "" {
+			item.Title = "Meeting"
+		}
+
+		switch {
+		case event.UID == "":
+			item.Reason = "event has no UID"
+		case event.RecurrenceID != nil:
+			item.Reason = "changed occurrence without its recurring event in the file"
+		case seen[event.UID]:
+			item.Reason = "duplicate UID in the file"
+		}
+		if event.UID != "" {
+			seen[event.UID] = true
+		}
+		if item.Reason != "" {
+			result.Skipped = append(result.Skipped, item)
+			continue
+		}
+
+		meeting, err := s.meetingRepo.FindByExternalUID(teamID, event.UID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		updating := err == nil
+
+		switch {
+		case updating && meeting.CreatedBy != importer.ID && !isManager:
+			item.Reason = "the meeting with this UID was imported by someone else"
+		case event.Status == "CANCELLED" && updating:
+			imports = append(imports, repositories.ImportedMeeting{Meeting: meeting, Delete: true})
+			items = append(items, item)
+			lists = append(lists, &result.Cancelled)
+			continue
+		case event.Status == "CANCELLED":
+			item.Reason = "event is cancelled"
+		case event.AllDay:
+			item.Reason = "all-day events are not meetings"
+		case !event.End.After(event.Start) || event.End.Sub(event.Start) >= 24*time.Hour:
+			item.Reason = "meetings must end after they start and last less than 24 hours"
+		}
+		if item.Reason != "" {
+			result.Skipped = append(result.Skipped, item)
+			continue
+		}
+
+		creator := importer
+		if updating {
+			if creator, err = s.userRepo.FindByID(meeting.CreatedBy); err != nil {
+				creator = importer
+			}
+		} else {
+			uid := event.UID
+			meeting = &models.Meeting{TeamID: teamID, CreatedBy: importer.ID, ExternalUID: &uid}
+		}
+
+		zone := event.Start.Location()
+		if zone == time.UTC {
+			zone = userLocation(creator)
+		}
+		meeting.Title = item.Title
+		meeting.Description = event.Description
+		meeting.Location = event.Location
+		meeting.StartsAt = event.Start
+		meeting.EndsAt = event.End
+		meeting.Timezone = zone.String()
+		recurrence := &RecurrenceInput{}
+		if event.RRule != "" {
+			input, warning := icsRecurrence(event.RRule, event.Start.In(zone))
+			if input != nil {
+				recurrence = input
+			} else {
+				item.Warnings = append(item.Warnings, warning)
+			}
+		}
+		if err := recurrence.Apply(meeting); err != nil {
+			item.Warnings = append(item.Warnings, "recurrence "+event.RRule+" is invalid ("+err.Error()+"); imported as a single meeting")
+			recurrence = &RecurrenceInput{}
+			_ = recurrence.Apply(meeting)
+		}
+
+		var attendees []models.MeetingAttendee
+		invited := make(map[uint]bool)
+		if organizer, ok := byEmail[event.Organizer]; ok {
+			attendees = append(attendees, models.MeetingAttendee{UserID: organizer.ID, Status: models.AttendeeStatusAccepted})
+			invited[organizer.ID] = true
+		}
+		for _, attendee := range event.Attendees {
+			user, ok := byEmail[attendee.Email]
+			if !ok {
+				if attendee.Email != "" {
+					item.UnmatchedAttendees = append(item.UnmatchedAttendees, attendee.Email)
+				}
+				continue
+			}
+			if invited[user.ID] {
+				continue
+			}
+			status, ok := icsAttendeeStatus[attendee.PartStat]
+			if !ok {
+				status = models.AttendeeStatusPending
+			}
+			attendees = append(attendees, models.MeetingAttendee{UserID: user.ID, Status: status})
+			invited[user.ID] = true
+		}
+
+		exceptions, warnings := icsExceptions(meeting, event, overrides[event.UID])
+		item.Warnings = append(item.Warnings, warnings...)
+
+		imports = append(imports, repositories.ImportedMeeting{Meeting: meeting, Attendees: attendees, Exceptions: exceptions})
+		items = append(items, item)
+		if updating {
+			lists = append(lists, &result.Updated)
+		} else {
+			lists = append(lists, &result.Imported)
+		}
+	}
+
+	if err := s.meetingRepo.SaveImports(imports); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].MeetingID = imports[i].Meeting.ID
+		*lists[i] = append(*lists[i], items[i])
+	}
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+// Which occurrences of a recurring meeting an update or deletion applies to
+const (
+	MeetingScopeThis      = "this"
+	MeetingScopeFollowing = "following"
+	MeetingScopeAll       = "all"
+)
+
+type MeetingService struct {
+	meetingRepo *repositories.MeetingRepository
+	userRepo    *repositories.UserRepository
+}
+
+func NewMeetingService(meetingRepo *repositories.MeetingRepository, userRepo *repositories.UserRepository) *MeetingService {
+	return &MeetingService{meetingRepo: meetingRepo, userRepo: userRepo}
+}
+
+// UserLocation is the timezone meetings are scheduled in and shown to the
+// user in, UTC for unknown users
+func (s *MeetingService) UserLocation(userID uint) *time.Location {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return time.UTC
+	}
+	return userLocation(user)
+}
+
+// occurrenceScope checks the scope and, for single occurrences and the ones
+// following them, that the meeting has an occurrence on the date. It returns
+// the dates of the series up to the occurrence. Meetings that do not recur
+// are always updated as a whole.
+func occurrenceScope(meeting *models.Meeting, scope string, occurrenceDate *time.Time) (string, []time.Time, error) {
+	switch scope {
+	case "", MeetingScopeAll:
+		return MeetingScopeAll, nil, nil
+	case MeetingScopeThis, MeetingScopeFollowing:
+	default:
+		return "", nil, errors.New("scope must be this, following or all")
+	}
+	if !meeting.IsRecurring {
+		return MeetingScopeAll, nil, nil
+	}
+	if occurrenceDate == nil {
+		return "", nil, errors.New("occurrence_date is required to change this or following occurrences")
+	}
+
+	dates := meetingDates(meeting, *occurrenceDate)
+	if len(dates) == 0 || !dates[len(dates)-1].Equal(calendarDay(*occurrenceDate)) {
+		return "", nil, errors.New("occurrence_date is not an occurrence of this meeting")
+	}
+	// Changing the first occurrence and the ones following it changes them all
+	if scope == MeetingScopeFollowing && len(dates) == 1 {
+		return MeetingScopeAll, dates, nil
+	}
+	return scope, dates, nil
+}
+
+// copyMeetingDetails sets the details and recurrence of a meeting to those of
+// another
+func copyMeetingDetails(meeting, from *models.Meeting) {
+	meeting.Title = from.Title
+	meeting.Description = from.Description
+	meeting.StartsAt = from.StartsAt
+	meeting.EndsAt = from.EndsAt
+	meeting.Timezone = from.Timezone
+	meeting.Location = from.Location
+	meeting.IsRecurring = from.IsRecurring
+	meeting.RecurringPattern = from.RecurringPattern
+	meeting.RecurrenceInterval = from.RecurrenceInterval
+	meeting.RecurrenceByDay = from.RecurrenceByDay
+	meeting.RecurrenceUntil = from.RecurrenceUntil
+	meeting.RecurrenceCount = from.RecurrenceCount
+}
+
+// Update changes a meeting, one of its occurrences, or an occurrence and the
+// ones following it, which continue as a new meeting. It returns the meeting
+// holding the change.
+func (s *MeetingService) Update(meeting, changes *models.Meeting, scope string, occurrenceDate *time.Time) (*models.Meeting, error) {
+	scope, dates, err := occurrenceScope(meeting, scope, occurrenceDate)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scope {
+	case MeetingScopeThis:
+		exception := &models.MeetingException{
+			MeetingID:      meeting.ID,
+			OccurrenceDate: dates[len(dates)-1],
+			Title:          &changes.Title,
+			Description:    &changes.Description,
+			Location:       &changes.Location,
+			StartsAt:       &changes.StartsAt,
+			EndsAt:         &changes.EndsAt,
+		}
+		if err := s.meetingRepo.SaveException(exception); err != nil {
+			return nil, err
+		}
+		return s.meetingRepo.FindByID(meeting.ID)
+
+	case MeetingScopeFollowing:
+		split := dates[len(dates)-1]
+		if previous, _ := occurrenceTimes(meeting, dates[len(dates)-2]); !changes.StartsAt.After(previous) {
+			return nil, errors.New("the meeting must start after the previous occurrence")
+		}
+		next := &models.Meeting{TeamID: meeting.TeamID, CreatedBy: meeting.CreatedBy}
+		copyMeetingDetails(next, changes)
+		// A count keeps counting from the first occurrence of the series
+		if next.RecurrenceCount != nil {
+			remaining := *next.RecurrenceCount - (len(dates) - 1)
+			if remaining < 1 {
+				return nil, errors.New("recurrence_count ends the series before occurrence_date")
+			}
+			next.RecurrenceCount = &remaining
+		}
+
+		until := split.AddDate(0, 0, -1)
+		meeting.RecurrenceUntil = &until
+		meeting.RecurrenceCount = nil
+		if err := s.meetingRepo.SplitSeries(meeting, next, meeting.Attendees); err != nil {
+			return nil, err
+		}
+		return s.meetingRepo.FindByID(next.ID)
+	}
+
+	copyMeetingDetails(meeting, changes)
+	// Changes to occurrences the new schedule no longer has are dropped
+	var stale []uint
+	for _, exception := range meeting.Exceptions {
+		if !meeting.IsRecurring || !isOccurrenceDate(meeting, exception.OccurrenceDate) {
+			stale = append(stale, exception.ID)
+		}
+	}
+	if err := s.meetingRepo.Update(meeting, stale); err != nil {
+		return nil, err
+	}
+	return s.meetingRepo.FindByID(meeting.ID)
+}
+
+// Delete deletes a meeting, cancels one of its occurrences, or ends its series
+// before an occurrence
+func (s *MeetingService) Delete(meeting *models.Meeting, scope string, occurrenceDate *time.Time) error {
+	scope, dates, err := occurrenceScope(meeting, scope, occurrenceDate)
+	if err != nil {
+		return err
+	}
+
+	switch scope {
+	case MeetingScopeThis:
+		return s.meetingRepo.SaveException(&models.MeetingException{
+			MeetingID:      meeting.ID,
+			OccurrenceDate: dates[len(dates)-1],
+			Cancelled:      true,
+		})
+	case MeetingScopeFollowing:
+		until := dates[len(dates)-1].AddDate(0, 0, -1)
+		meeting.RecurrenceUntil = &until
+		meeting.RecurrenceCount = nil
+		return s.meetingRepo.EndSeries(meeting)
+	}
+	return s.meetingRepo.Delete(meeting.ID)
+}
+
+// GetOccurrences lists the occurrences of the team's meetings on the dates of
+// the range in the timezone
+func (s *MeetingService) GetOccurrences(teamID uint, from, to time.Time, loc *time.Location) ([]MeetingOccurrence, error) {
+	start, end, err := dayRange(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+	meetings, err := s.meetingRepo.FindByDateRange(teamID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return ExpandMeetings(meetings, from, to, loc)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"task-management/models"
+	"time"
+)
+
+// MeetingTimesInput is when a meeting takes place, as sent by clients: a date
+// and wall-clock times in an IANA timezone
+type MeetingTimesInput struct {
+	MeetingDate string `json:"meeting_date" binding:"required"`
+	StartTime   string `json:"start_time" binding:"required"`
+	// An end time at or before the start time ends the meeting the next day
+	EndTime string `json:"end_time" binding:"required"`
+	// Timezone defaults to the timezone of the user scheduling the meeting
+	Timezone string `json:"timezone"`
+}
+
+// Apply validates the times and sets them on the meeting as instants, in the
+// given timezone when the input has none. Times skipped when the clocks go
+// forward move forward by the change.
+func (in *MeetingTimesInput) Apply(meeting *models.Meeting, defaultZone *time.Location) error {
+	date, err := time.Parse("2006-01-02", in.MeetingDate)
+	if err != nil {
+		return errors.New("invalid meeting_date format")
+	}
+	startMinutes, err := parseClock(in.StartTime)
+	if err != nil {
+		return errors.New("start_time must be formatted as HH:MM")
+	}
+	endMinutes, err := parseClock(in.EndTime)
+	if err != nil {
+		return errors.New("end_time must be formatted as HH:MM")
+	}
+	if endMinutes == startMinutes {
+		return errors.New("end_time must differ from start_time")
+	}
+
+	zone := defaultZone
+	if in.Timezone != "" {
+		// Local would be the server's timezone
+		loc, err := time.LoadLocation(in.Timezone)
+		if err != nil || in.Timezone == "Local" {
+			return errors.New("invalid timezone")
+		}
+		zone = loc
+	}
+
+	endDay := date.Day()
+	if endMinutes < startMinutes {
+		endDay++
+	}
+	meeting.StartsAt = wallTime(date.Year(), date.Month(), date.Day(), 0, startMinutes, 0, zone)
+	meeting.EndsAt = wallTime(date.Year(), date.Month(), endDay, 0, endMinutes, 0, zone)
+	meeting.Timezone = zone.String()
+	if !meeting.EndsAt.After(meeting.StartsAt) {
+		return errors.New("meeting ends before it starts once the clocks change")
+	}
+	return nil
+}
+
+// wallTime is time.Date, except that a time skipped when the clocks go forward
+// moves forward by the change, as calendar clients do, rather than back
+func wallTime(year int, month time.Month, day, hour, min, sec int, zone *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, min, sec, 0, zone)
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	if t.Hour() == wall.Hour() && t.Minute() == wall.Minute() && t.Second() == wall.Second() {
+		return t
+	}
+	// t is before the change, so its offset is the one the time was skipped from
+	_, offset := t.Zone()
+	return wall.Add(-time.Duration(offset) * time.Second).In(zone)
+}
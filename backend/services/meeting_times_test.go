@@ -0,0 +1,127 @@
+package services
+
+import (
+	"task-management/models"
+	"testing"
+	"time"
+)
+
+func TestMeetingTimesInputApply(t *testing.T) {
+	jakarta := loadLocation(t, "Asia/Jakarta")
+	newYork := loadLocation(t, "America/New_York")
+	at := func(year int, month time.Month, d, hour, min int) time.Time {
+		return time.Date(year, month, d, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name         string
+		input        MeetingTimesInput
+		defaultZone  *time.Location
+		wantStart    time.Time
+		wantEnd      time.Time
+		wantTimezone string
+		wantErr      string
+	}{
+		{
+			name:        "in the default timezone",
+			input:       MeetingTimesInput{MeetingDate: "2026-01-05", StartTime: "09:00", EndTime: "10:30"},
+			defaultZone: jakarta,
+			wantStart:   at(2026, 1, 5, 2, 0), wantEnd: at(2026, 1, 5, 3, 30), wantTimezone: "Asia/Jakarta",
+		},
+		{
+			name: "in the given timezone",
+			input: MeetingTimesInput{
+				MeetingDate: "2026-01-05", StartTime: "09:00", EndTime: "10:00", Timezone: "America/New_York",
+			},
+			defaultZone: jakarta,
+			wantStart:   at(2026, 1, 5, 14, 0), wantEnd: at(2026, 1, 5, 15, 0), wantTimezone: "America/New_York",
+		},
+		{
+			name:        "crossing midnight",
+			input:       MeetingTimesInput{MeetingDate: "2026-01-05", StartTime: "23:00", EndTime: "01:00"},
+			defaultZone: time.UTC,
+			wantStart:   at(2026, 1, 5, 23, 0), wantEnd: at(2026, 1, 6, 1, 0), wantTimezone: "UTC",
+		},
+		{
+			name:        "crossing midnight at the end of the month",
+			input:       MeetingTimesInput{MeetingDate: "2026-01-31", StartTime: "22:00", EndTime: "00:00"},
+			defaultZone: time.UTC,
+			wantStart:   at(2026, 1, 31, 22, 0), wantEnd: at(2026, 2, 1, 0, 0), wantTimezone: "UTC",
+		},
+		{
+			// 02:30 does not exist on 8 March and moves forward to 03:30 EDT
+			name:        "start skipped by the clocks going forward",
+			input:       MeetingTimesInput{MeetingDate: "2026-03-08", StartTime: "02:30", EndTime: "04:00"},
+			defaultZone: newYork,
+			wantStart:   at(2026, 3, 8, 7, 30), wantEnd: at(2026, 3, 8, 8, 0), wantTimezone: "America/New_York",
+		},
+		{
+			name:        "ends before it starts once the clocks go forward",
+			input:       MeetingTimesInput{MeetingDate: "2026-03-08", StartTime: "02:30", EndTime: "03:10"},
+			defaultZone: newYork,
+			wantErr:     "meeting ends before it starts once the clocks change",
+		},
+		{
+			name:        "invalid date",
+			input:       MeetingTimesInput{MeetingDate: "2026-02-30", StartTime: "09:00", EndTime: "10:00"},
+			defaultZone: time.UTC,
+			wantErr:     "invalid meeting_date format",
+		},
+		{
+			name:        "invalid start time",
+			input:       MeetingTimesInput{MeetingDate: "2026-01-05", StartTime: "25:99", EndTime: "10:00"},
+			defaultZone: time.UTC,
+			wantErr:     "start_time must be formatted as HH:MM",
+		},
+		{
+			name:        "invalid end time",
+			input:       MeetingTimesInput{MeetingDate: "2026-01-05", StartTime: "09:00", EndTime: "10am"},
+			defaultZone: time.UTC,
+			wantErr:     "end_time must be formatted as HH:MM",
+		},
+		{
+			name:        "equal times",
+			input:       MeetingTimesInput{MeetingDate: "2026-01-05", StartTime: "09:00", EndTime: "09:00"},
+			defaultZone: time.UTC,
+			wantErr:     "end_time must differ from start_time",
+		},
+		{
+			name: "unknown timezone",
+			input: MeetingTimesInput{
+				MeetingDate: "2026-01-05", StartTime: "09:00", EndTime: "10:00", Timezone: "Mars/Olympus_Mons",
+			},
+			defaultZone: time.UTC,
+			wantErr:     "invalid timezone",
+		},
+		{
+			name: "server timezone",
+			input: MeetingTimesInput{
+				MeetingDate: "2026-01-05", StartTime: "09:00", EndTime: "10:00", Timezone: "Local",
+			},
+			defaultZone: time.UTC,
+			wantErr:     "invalid timezone",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meeting := &models.Meeting{}
+			err := tt.input.Apply(meeting, tt.defaultZone)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Apply returned error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply returned error: %v", err)
+			}
+			if !meeting.StartsAt.Equal(tt.wantStart) || !meeting.EndsAt.Equal(tt.wantEnd) {
+				t.Errorf("meeting is %s to %s, want %s to %s",
+					meeting.StartsAt.UTC(), meeting.EndsAt.UTC(), tt.wantStart, tt.wantEnd)
+			}
+			if meeting.Timezone != tt.wantTimezone {
+				t.Errorf("Timezone = %q, want %q", meeting.Timezone, tt.wantTimezone)
+			}
+		})
+	}
+}
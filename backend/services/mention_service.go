@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+)
+
+type MentionService struct {
+	mentionRepo         *repositories.MentionRepository
+	issueRepo           *repositories.IssueRepository
+	userRepo            *repositories.UserRepository
+	teamRepo            *repositories.TeamRepository
+	notificationService *NotificationService
+}
+
+func NewMentionService(
+	mentionRepo *repositories.MentionRepository,
+	issueRepo *repositories.IssueRepository,
+	userRepo *repositories.UserRepository,
+	teamRepo *repositories.TeamRepository,
+	notificationService *NotificationService,
+) *MentionService {
+	return &MentionService{
+		mentionRepo:         mentionRepo,
+		issueRepo:           issueRepo,
+		userRepo:            userRepo,
+		teamRepo:            teamRepo,
+		notificationService: notificationService,
+	}
+}
+
+// mentionPattern matches @jane.doe@example.com or @jane.doe. The mention must
+// not be glued to a preceding word, so plain email addresses are ignored.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}|[A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// ParseMentions returns the distinct lowercased emails and handles mentioned in text
+func ParseMentions(text string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// Sentence punctuation is not part of the handle
+		token := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// mentionHandle is the part of an email before the @, used as the user's handle
+func mentionHandle(email string) string {
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	return local
+}
+
+// resolveMentions maps mention tokens to users of the organization
+func (s *MentionService) resolveMentions(orgID uint, tokens []string) (map[uint]*models.User, error) {
+	resolved := make(map[uint]*models.User)
+	if len(tokens) == 0 {
+		return resolved, nil
+	}
+
+	users, err := s.userRepo.FindByOrganization(orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range matchMentions(users, tokens) {
+		resolved[user.ID] = user
+	}
+	return resolved, nil
+}
+
+// matchMentions maps each token that names a user by email or handle to that
+// user. Handles shared by several users are ambiguous and left unmatched.
+func matchMentions(users []models.User, tokens []string) map[string]*models.User {
+	byEmail := make(map[string]*models.User, len(users))
+	byHandle := make(map[string]*models.User, len(users))
+	ambiguous := make(map[string]bool)
+	for i := range users {
+		user := &users[i]
+		byEmail[strings.ToLower(user.Email)] = user
+		handle := mentionHandle(user.Email)
+		if _, exists := byHandle[handle]; exists {
+			ambiguous[handle] = true
+		}
+		byHandle[handle] = user
+	}
+
+	matched := make(map[string]*models.User)
+	for _, token := range tokens {
+		if user, ok := byEmail[token]; ok {
+			matched[token] = user
+			continue
+		}
+		if user, ok := byHandle[token]; ok && !ambiguous[token] {
+			matched[token] = user
+		}
+	}
+	return matched
+}
+
+// SyncMentions brings the stored mentions of an issue description (commentID
+// nil) or a comment in line with its current text. Only newly mentioned users
+// are notified. Users outside the issue's team are recorded as flagged
+// mentions but not notified.
+func (s *MentionService) SyncMentions(issueID uint, commentID *uint, text string, actorID uint) ([]models.Mention, error) {
+	issue, err := s.issueRepo.FindByID(issueID)
+	if err != nil {
+		return nil, errors.New("issue not found")
+	}
+
+	mentioned, err := s.resolveMentions(issue.Team.OrganizationID, ParseMentions(text))
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.mentionRepo.FindBySource(issueID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []uint
+	kept := make(map[uint]bool)
+	for _, mention := range existing {
+		if _, ok := mentioned[mention.UserID]; ok {
+			kept[mention.UserID] = true
+		} else {
+			removed = append(removed, mention.ID)
+		}
+	}
+	if err := s.mentionRepo.DeleteByIDs(removed); err != nil {
+		return nil, err
+	}
+
+	var actorName string
+	if actor, err := s.userRepo.FindByID(actorID); err == nil {
+		actorName = actor.FullName
+	}
+
+	for userID := range mentioned {
+		if kept[userID] {
+			continue
+		}
+
+		_, memberErr := s.teamRepo.GetMemberRole(issue.TeamID, userID)
+		mention := &models.Mention{
+			IssueID:     issueID,
+			CommentID:   commentID,
+			UserID:      userID,
+			MentionedBy: actorID,
+			OutsideTeam: memberErr != nil,
+		}
+		if err := s.mentionRepo.Create(mention); err != nil {
+			return nil, err
+		}
+		if mention.OutsideTeam {
+			continue
+		}
+
+		where := fmt.Sprintf("issue #%d %q", issue.ID, issue.Title)
+		if commentID != nil {
+			where = "a comment on " + where
+		}
+		if err := s.notificationService.Notify(&models.Notification{
+			UserID:    userID,
+			Type:      models.NotificationMention,
+			IssueID:   &issue.ID,
+			CommentID: commentID,
+			ActorID:   &actorID,
+			Message:   fmt.Sprintf("%s mentioned you in %s", actorName, where),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.mentionRepo.FindBySource(issueID, commentID)
+}
+
+func (s *MentionService) GetByIssue(issueID uint) ([]models.Mention, error) {
+	return s.mentionRepo.FindByIssue(issueID)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+type MilestoneService struct {
+	milestoneRepo *repositories.MilestoneRepository
+	teamRepo      *repositories.TeamRepository
+}
+
+func NewMilestoneService(milestoneRepo *repositories.MilestoneRepository, teamRepo *repositories.TeamRepository) *MilestoneService {
+	return &MilestoneService{
+		milestoneRepo: milestoneRepo,
+		teamRepo:      teamRepo,
+	}
+}
+
+type MilestoneRequest struct {
+	TeamID      *uint  `json:"team_id"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	TargetDate  string `json:"target_date" binding:"required"`
+	Released    bool   `json:"released"`
+}
+
+type MilestoneProgress struct {
+	MilestoneID     uint                          `json:"milestone_id"`
+	TargetDate      string                        `json:"target_date"`
+	Total           int                           `json:"total"`
+	ByCategory      map[models.StatusCategory]int `json:"by_category"`
+	PercentComplete float64                       `json:"percent_complete"`
+	AtRisk          bool                          `json:"at_risk"`
+	AtRiskIssues    []AtRiskIssue                 `json:"at_risk_issues"`
+}
+
+type AtRiskIssue struct {
+	IssueID  uint      `json:"issue_id"`
+	Title    string    `json:"title"`
+	Deadline time.Time `json:"deadline"`
+}
+
+func (s *MilestoneService) applyRequest(milestone *models.Milestone, req *MilestoneRequest) error {
+	targetDate, err := time.Parse("2006-01-02", req.TargetDate)
+	if err != nil {
+		return errors.New("invalid target_date format (use YYYY-MM-DD)")
+	}
+
+	if req.TeamID != nil {
+		team, err := s.teamRepo.FindByID(*req.TeamID)
+		if err != nil || team.OrganizationID != milestone.OrganizationID {
+			return errors.New("team not found")
+		}
+	}
+
+	milestone.TeamID = req.TeamID
+	milestone.Name = req.Name
+	milestone.Description = req.Description
+	milestone.TargetDate = targetDate
+
+	if req.Released && milestone.ReleasedAt == nil {
+		now := time.Now()
+		milestone.ReleasedAt = &now
+	} else if !req.Released {
+		milestone.ReleasedAt = nil
+	}
+	return nil
+}
+
+func (s *MilestoneService) Create(req *MilestoneRequest, orgID, createdBy uint) (*models.Milestone, error) {
+	milestone := &models.Milestone{
+		OrganizationID: orgID,
+		CreatedBy:      createdBy,
+	}
+	if err := s.applyRequest(milestone, req); err != nil {
+		return nil, err
+	}
+	if err := s.milestoneRepo.Create(milestone); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+func (s *MilestoneService) GetByID(id uint) (*models.Milestone, error) {
+	return s.milestoneRepo.FindByID(id)
+}
+
+func (s *MilestoneService) GetByOrganization(orgID uint, teamID *uint) ([]models.Milestone, error) {
+	return s.milestoneRepo.FindByOrganization(orgID, teamID)
+}
+
+func (s *MilestoneService) GetIssues(id uint) ([]models.Issue, error) {
+	return s.milestoneRepo.FindIssues(id)
+}
+
+func (s *MilestoneService) Update(id uint, req *MilestoneRequest) (*models.Milestone, error) {
+	milestone, err := s.milestoneRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("milestone not found")
+	}
+	if err := s.applyRequest(milestone, req); err != nil {
+		return nil, err
+	}
+	if err := s.milestoneRepo.Update(milestone); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+func (s *MilestoneService) Delete(id uint) error {
+	return s.milestoneRepo.Delete(id)
+}
+
+// GetProgress counts the milestone's issues per status category. The
+// milestone is at risk when an open issue is due after the target date.
+func (s *MilestoneService) GetProgress(id uint) (*MilestoneProgress, error) {
+	milestone, err := s.milestoneRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("milestone not found")
+	}
+
+	issues, err := s.milestoneRepo.FindIssues(id)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &MilestoneProgress{
+		MilestoneID: milestone.ID,
+		TargetDate:  milestone.TargetDate.Format("2006-01-02"),
+		Total:       len(issues),
+		ByCategory: map[models.StatusCategory]int{
+			models.CategoryTodo:       0,
+			models.CategoryInProgress: 0,
+			models.CategoryDone:       0,
+		},
+		AtRiskIssues: []AtRiskIssue{},
+	}
+
+	for _, issue := range issues {
+		category := issueCategory(&issue)
+		progress.ByCategory[category]++
+
+		if category != models.CategoryDone && issue.Deadline != nil && issue.Deadline.After(milestone.TargetDate) {
+			progress.AtRiskIssues = append(progress.AtRiskIssues, AtRiskIssue{
+				IssueID:  issue.ID,
+				Title:    issue.Title,
+				Deadline: *issue.Deadline,
+			})
+		}
+	}
+
+	if progress.Total > 0 {
+		progress.PercentComplete = float64(progress.ByCategory[models.CategoryDone]) * 100 / float64(progress.Total)
+	}
+	progress.AtRisk = len(progress.AtRiskIssues) > 0
+
+	return progress, nil
+}
+
+// GetReleaseNotes renders the milestone's completed issues as Markdown,
+// grouped by label. Issues with several labels are listed under each one.
+func (s *MilestoneService) GetReleaseNotes(id uint) (string, error) {
+	milestone, err := s.milestoneRepo.FindByID(id)
+	if err != nil {
+		return "", errors.New("milestone not found")
+	}
+
+	issues, err := s.milestoneRepo.FindIssues(id)
+	if err != nil {
+		return "", err
+	}
+
+	const unlabeled = "Other"
+	groups := make(map[string][]models.Issue)
+	for _, issue := range issues {
+		if issueCategory(&issue) != models.CategoryDone {
+			continue
+		}
+		if len(issue.Labels) == 0 {
+			groups[unlabeled] = append(groups[unlabeled], issue)
+			continue
+		}
+		for _, label := range issue.Labels {
+			groups[label.Name] = append(groups[label.Name], issue)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		if name != unlabeled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := groups[unlabeled]; ok {
+		names = append(names, unlabeled)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", milestone.Name)
+	if milestone.ReleasedAt != nil {
+		fmt.Fprintf(&b, "Released: %s\n\n", milestone.ReleasedAt.Format("2006-01-02"))
+	} else {
+		fmt.Fprintf(&b, "Target date: %s\n\n", milestone.TargetDate.Format("2006-01-02"))
+	}
+	if milestone.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", milestone.Description)
+	}
+
+	if len(names) == 0 {
+		b.WriteString("_No completed issues._\n")
+		return b.String(), nil
+	}
+
+	for _, name := range names {
+		fmt.Fprintf(&b, "## %s\n\n", name)
+		for _, issue := range groups[name] {
+			fmt.Fprintf(&b, "- %s (#%d)\n", escapeMarkdownLine(issue.Title), issue.ID)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String(), nil
+}
+
+func issueCategory(issue *models.Issue) models.StatusCategory {
+	if issue.Status == nil {
+		return models.CategoryTodo
+	}
+	if issue.Status.IsFinal {
+		return models.CategoryDone
+	}
+	if issue.Status.Category == "" {
+		return models.CategoryInProgress
+	}
+	return issue.Status.Category
+}
+
+func escapeMarkdownLine(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	replacer := strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`")
+	return replacer.Replace(s)
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"task-management/models"
+	"task-management/repositories"
+)
+
+type NotificationService struct {
+	notificationRepo *repositories.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo *repositories.NotificationRepository) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+type NotificationList struct {
+	Notifications []models.Notification `json:"notifications"`
+	UnreadCount   int64                 `json:"unread_count"`
+}
+
+// Notify creates an in-app notification. Users are never notified of their own actions.
+func (s *NotificationService) Notify(notification *models.Notification) error {
+	if notification.ActorID != nil && *notification.ActorID == notification.UserID {
+		return nil
+	}
+	return s.notificationRepo.Create(notification)
+}
+
+func (s *NotificationService) GetForUser(userID uint, unreadOnly bool, limit int) (*NotificationList, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	notifications, err := s.notificationRepo.FindByUser(userID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationList{Notifications: notifications, UnreadCount: unread}, nil
+}
+
+func (s *NotificationService) MarkRead(id, userID uint) error {
+	return s.notificationRepo.MarkRead(id, userID)
+}
+
+func (s *NotificationService) MarkAllRead(userID uint) error {
+	return s.notificationRepo.MarkAllRead(userID)
+}
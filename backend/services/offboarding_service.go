@@ -0,0 +1,360 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OffboardingService struct {
+	offboardingRepo *repositories.OffboardingRepository
+	teamRepo        *repositories.TeamRepository
+	assignmentRepo  *repositories.AssignmentRepository
+	timerRepo       *repositories.TimerRepository
+	meetingRepo     *repositories.MeetingRepository
+	userRepo        *repositories.UserRepository
+}
+
+func NewOffboardingService(
+	offboardingRepo *repositories.OffboardingRepository,
+	teamRepo *repositories.TeamRepository,
+	assignmentRepo *repositories.AssignmentRepository,
+	timerRepo *repositories.TimerRepository,
+	meetingRepo *repositories.MeetingRepository,
+	userRepo *repositories.UserRepository,
+) *OffboardingService {
+	return &OffboardingService{
+		offboardingRepo: offboardingRepo,
+		teamRepo:        teamRepo,
+		assignmentRepo:  assignmentRepo,
+		timerRepo:       timerRepo,
+		meetingRepo:     meetingRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// OffboardingPreview is the work a member leaves behind in a team: active
+// assignments on its issues, timers on them and upcoming team meetings
+type OffboardingPreview struct {
+	TeamID      uint                     `json:"team_id"`
+	UserID      uint                     `json:"user_id"`
+	Assignments []models.IssueAssignment `json:"assignments"`
+	Timers      []models.WorkTimer       `json:"timers"`
+	Meetings    []models.Meeting         `json:"meetings"`
+}
+
+// AssignmentHandover overrides where one assignment goes; a nil UserID
+// unassigns it
+type AssignmentHandover struct {
+	AssignmentID uint  `json:"assignment_id" binding:"required"`
+	UserID       *uint `json:"user_id"`
+}
+
+type OffboardingRequest struct {
+	// ReassignTo takes over the assignments not listed in Assignments; when
+	// nil they are unassigned
+	ReassignTo   *uint                `json:"reassign_to"`
+	Assignments  []AssignmentHandover `json:"assignments"`
+	HandoverNote string               `json:"handover_note"`
+	// DiscardTimers deletes running work instead of logging it
+	DiscardTimers bool `json:"discard_timers"`
+}
+
+type OffboardingResult struct {
+	Reassigned      int `json:"reassigned"`
+	Unassigned      int `json:"unassigned"`
+	TimersStopped   int `json:"timers_stopped"`
+	TimersDiscarded int `json:"timers_discarded"`
+	MeetingsLeft    int `json:"meetings_left"`
+}
+
+// Preview lists what offboarding the member would hand over
+func (s *OffboardingService) Preview(teamID, userID uint) (*OffboardingPreview, error) {
+	if _, err := s.teamRepo.GetMemberRole(teamID, userID); err != nil {
+		return nil, errors.New("user is not a member of this team")
+	}
+
+	preview := &OffboardingPreview{
+		TeamID:      teamID,
+		UserID:      userID,
+		Assignments: []models.IssueAssignment{},
+		Timers:      []models.WorkTimer{},
+	}
+
+	assignments, err := s.assignmentRepo.FindActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range assignments {
+		// Deleted issues are not preloaded
+		if assignment.Issue.ID != 0 && assignment.Issue.TeamID == teamID {
+			preview.Assignments = append(preview.Assignments, assignment)
+		}
+	}
+
+	timers, err := s.timerRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, timer := range withElapsed(timers) {
+		// Deleted issues are not preloaded
+		if timer.Issue != nil && timer.Issue.TeamID == teamID {
+			preview.Timers = append(preview.Timers, timer)
+		}
+	}
+
+	preview.Meetings, err = s.meetingRepo.FindUpcomingByAttendee(teamID, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// handoverTargets resolves who takes over each of the assignments, checking
+// that they are other members of the team
+func (s *OffboardingService) handoverTargets(teamID, userID uint, assignments []models.IssueAssignment, req *OffboardingRequest) (map[uint]*models.User, error) {
+	members, err := s.teamRepo.GetMembers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint]*models.User)
+	for i := range members {
+		if members[i].UserID != userID {
+			byUser[members[i].UserID] = &members[i].User
+		}
+	}
+	target := func(id *uint) (*models.User, error) {
+		if id == nil {
+			return nil, nil
+		}
+		user, ok := byUser[*id]
+		if !ok {
+			return nil, fmt.Errorf("user %d is not another member of this team", *id)
+		}
+		return user, nil
+	}
+
+	fallback, err := target(req.ReassignTo)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[uint]*models.User)
+	for _, assignment := range assignments {
+		targets[assignment.ID] = fallback
+	}
+	for _, handover := range req.Assignments {
+		if _, ok := targets[handover.AssignmentID]; !ok {
+			return nil, fmt.Errorf("assignment %d is not an open assignment of this member", handover.AssignmentID)
+		}
+		if targets[handover.AssignmentID], err = target(handover.UserID); err != nil {
+			return nil, err
+		}
+	}
+	return targets, nil
+}
+
+// splitSeries keeps the user in the past occurrences of a recurring meeting
+// that already started, continuing the series without them from the first
+// occurrence today or later. It returns nil when the user can just be removed
+// from the meeting.
+func (s *OffboardingService) splitSeries(meeting *models.Meeting, userID uint, now time.Time) (*repositories.SeriesSplit, error) {
+	if !meeting.IsRecurring {
+		return nil, nil
+	}
+	split, past, ok := nextMeetingDate(meeting, now.In(meeting.Zone()))
+	if !ok || past == 0 {
+		return nil, nil
+	}
+
+	full, err := s.meetingRepo.FindByID(meeting.ID)
+	if err != nil {
+		return nil, err
+	}
+	next := &models.Meeting{TeamID: full.TeamID, CreatedBy: full.CreatedBy}
+	copyMeetingDetails(next, full)
+	next.StartsAt, next.EndsAt = occurrenceTimes(full, split)
+	// A count keeps counting from the first occurrence of the series
+	if next.RecurrenceCount != nil {
+		remaining := *next.RecurrenceCount - past
+		next.RecurrenceCount = &remaining
+	}
+
+	var attendees []models.MeetingAttendee
+	for _, attendee := range full.Attendees {
+		if attendee.UserID != userID {
+			attendees = append(attendees, attendee)
+		}
+	}
+
+	until := split.AddDate(0, 0, -1)
+	full.RecurrenceUntil = &until
+	full.RecurrenceCount = nil
+	return &repositories.SeriesSplit{Ended: full, Next: next, Attendees: attendees}, nil
+}
+
+// Offboard removes a member from a team, handing over their work in one
+// transaction: assignments on the team's issues are reassigned or unassigned,
+// timers on them are stopped (logging the time) or discarded, and they are
+// removed from upcoming team meetings. Every assignment and work log change is
+// recorded in the issue activity log.
+func (s *OffboardingService) Offboard(teamID, userID uint, req *OffboardingRequest, offboardedBy uint) (*OffboardingResult, error) {
+	preview, err := s.Preview(teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	targets, err := s.handoverTargets(teamID, userID, preview.Assignments, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := calendarDay(now)
+	o := &repositories.Offboarding{
+		TeamID:   teamID,
+		UserID:   userID,
+		ByUserID: offboardedBy,
+		Ended:    preview.Assignments,
+		Timers:   preview.Timers,
+	}
+	result := &OffboardingResult{MeetingsLeft: len(preview.Meetings)}
+
+	// Someone already holding the role on the issue keeps it, and the
+	// assignment is only ended
+	type roleHolder struct {
+		issueID uint
+		role    models.AssignmentRole
+		userID  uint
+	}
+	taken := make(map[roleHolder]bool)
+	for _, assignment := range preview.Assignments {
+		active, err := s.assignmentRepo.FindActiveByIssue(assignment.IssueID, assignment.Role)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range active {
+			taken[roleHolder{other.IssueID, other.Role, other.UserID}] = true
+		}
+	}
+
+	for _, assignment := range preview.Assignments {
+		target := targets[assignment.ID]
+		if target == nil || taken[roleHolder{assignment.IssueID, assignment.Role, target.ID}] {
+			o.Replacements = append(o.Replacements, nil)
+			continue
+		}
+		startDate := assignment.StartDate
+		if today.After(startDate) {
+			startDate = today
+		}
+		endDate := assignment.EndDate
+		if endDate.Before(startDate) {
+			endDate = startDate
+		}
+		o.Replacements = append(o.Replacements, &models.IssueAssignment{
+			IssueID:      assignment.IssueID,
+			UserID:       target.ID,
+			Role:         assignment.Role,
+			StartDate:    startDate,
+			EndDate:      endDate,
+			Estimate:     assignment.Estimate,
+			AssignedBy:   &offboardedBy,
+			IsActive:     true,
+			HandoverNote: req.HandoverNote,
+		})
+		taken[roleHolder{assignment.IssueID, assignment.Role, target.ID}] = true
+	}
+
+	for i := range preview.Timers {
+		if req.DiscardTimers {
+			o.WorkLogs = append(o.WorkLogs, nil)
+			continue
+		}
+		o.WorkLogs = append(o.WorkLogs, timerWorkLog(&preview.Timers[i], user, now))
+	}
+
+	for i := range preview.Meetings {
+		split, err := s.splitSeries(&preview.Meetings[i], userID, now)
+		if err != nil {
+			return nil, err
+		}
+		if split != nil {
+			o.SeriesSplits = append(o.SeriesSplits, *split)
+		} else {
+			o.MeetingIDs = append(o.MeetingIDs, preview.Meetings[i].ID)
+		}
+	}
+
+	o.Activities = func() ([]*models.IssueActivity, error) {
+		var activities []*models.IssueActivity
+		for i := range o.Ended {
+			ended := &o.Ended[i]
+			replacement := o.Replacements[i]
+			var activity *models.IssueActivity
+			var err error
+			if replacement == nil {
+				description := fmt.Sprintf("Unassigned %s as %s when they left the team", user.FullName, ended.Role)
+				activity, err = assignmentActivity(models.ActivityUnassigned, ended, offboardedBy, description, map[string]interface{}{
+					"offboarding": true,
+				})
+			} else {
+				description := fmt.Sprintf("Reassigned from %s to %s as %s when they left the team", user.FullName, targets[ended.ID].FullName, ended.Role)
+				activity, err = assignmentActivity(models.ActivityReassigned, replacement, offboardedBy, description, map[string]interface{}{
+					"previous_assignment_id": ended.ID,
+					"previous_user_id":       ended.UserID,
+					"handover_note":          req.HandoverNote,
+					"offboarding":            true,
+				})
+			}
+			if err != nil {
+				return nil, err
+			}
+			activities = append(activities, activity)
+		}
+
+		for _, workLog := range o.WorkLogs {
+			if workLog == nil {
+				continue
+			}
+			description := fmt.Sprintf("Logged %s from a timer stopped when %s left the team", formatMinutes(workLog.MinutesSpent), user.FullName)
+			activity, err := workLogActivity(models.ActivityWorkLogged, workLog, offboardedBy, description, map[string]interface{}{
+				"offboarding": true,
+			})
+			if err != nil {
+				return nil, err
+			}
+			activities = append(activities, activity)
+		}
+		return activities, nil
+	}
+
+	if err := s.offboardingRepo.Apply(o); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("assignments changed during offboarding, preview again")
+		}
+		return nil, err
+	}
+
+	for _, replacement := range o.Replacements {
+		if replacement == nil {
+			result.Unassigned++
+		} else {
+			result.Reassigned++
+		}
+	}
+	for _, workLog := range o.WorkLogs {
+		if workLog != nil {
+			result.TimersStopped++
+		} else if req.DiscardTimers {
+			result.TimersDiscarded++
+		}
+	}
+	return result, nil
+}
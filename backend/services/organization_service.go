@@ -13,7 +13,14 @@ func NewOrganizationService(orgRepo *repositories.OrganizationRepository) *Organ
 	return &OrganizationService{orgRepo: orgRepo}
 }
 
+// Create adds an organization. Working days default to Monday to Friday.
 func (s *OrganizationService) Create(org *models.Organization) error {
+	if org.WorkingDays == nil {
+		org.WorkingDays = defaultWorkingDays
+	}
+	if err := ValidateWorkingDays(org.WorkingDays); err != nil {
+		return err
+	}
 	return s.orgRepo.Create(org)
 }
 
@@ -25,7 +32,18 @@ func (s *OrganizationService) GetAll() ([]models.Organization, error) {
 	return s.orgRepo.FindAll()
 }
 
+// Update saves the organization. Working days are kept when not given.
 func (s *OrganizationService) Update(org *models.Organization) error {
+	if org.WorkingDays == nil {
+		existing, err := s.orgRepo.FindByID(org.ID)
+		if err != nil {
+			return err
+		}
+		org.WorkingDays = existing.WorkingDays
+	}
+	if err := ValidateWorkingDays(org.WorkingDays); err != nil {
+		return err
+	}
 	return s.orgRepo.Update(org)
 }
 
@@ -14,6 +14,11 @@ func NewPermissionService(teamRepo *repositories.TeamRepository) *PermissionServ
 	return &PermissionService{teamRepo: teamRepo}
 }
 
+// ManagedTeamIDs returns the teams the user is a manager of
+func (s *PermissionService) ManagedTeamIDs(userID uint) ([]uint, error) {
+	return s.teamRepo.FindManagedTeamIDs(userID)
+}
+
 // HasTeamAccess checks if a user has the required role or higher in a team
 func (s *PermissionService) HasTeamAccess(userID, teamID uint, requiredRole string) (bool, error) {
 	member, err := s.teamRepo.GetMemberRole(teamID, userID)
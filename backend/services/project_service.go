@@ -0,0 +1,230 @@
+package services
+
+import (
+	"errors"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+type ProjectService struct {
+	projectRepo *repositories.ProjectRepository
+	teamRepo    *repositories.TeamRepository
+	userRepo    *repositories.UserRepository
+}
+
+func NewProjectService(
+	projectRepo *repositories.ProjectRepository,
+	teamRepo *repositories.TeamRepository,
+	userRepo *repositories.UserRepository,
+) *ProjectService {
+	return &ProjectService{
+		projectRepo: projectRepo,
+		teamRepo:    teamRepo,
+		userRepo:    userRepo,
+	}
+}
+
+type ProjectRequest struct {
+	Name        string               `json:"name" binding:"required"`
+	Description string               `json:"description"`
+	OwnerID     uint                 `json:"owner_id"`
+	StartDate   string               `json:"start_date"`
+	TargetDate  string               `json:"target_date"`
+	Status      models.ProjectStatus `json:"status"`
+	Health      models.ProjectHealth `json:"health"`
+}
+
+type ProjectRollup struct {
+	ProjectID     uint                            `json:"project_id"`
+	TotalIssues   int64                           `json:"total_issues"`
+	ByCategory    map[string]int64                `json:"by_category"`
+	ByTeam        []repositories.ProjectTeamCount `json:"by_team"`
+	LoggedMinutes int64                           `json:"logged_minutes"`
+	OverdueIssues int64                           `json:"overdue_issues"`
+}
+
+// ProjectIssues is the viewer-specific issue list of a project
+type ProjectIssues struct {
+	Issues       []models.Issue `json:"issues"`
+	HiddenIssues int64          `json:"hidden_issues"`
+}
+
+var validProjectStatuses = map[models.ProjectStatus]bool{
+	models.ProjectPlanned:   true,
+	models.ProjectActive:    true,
+	models.ProjectOnHold:    true,
+	models.ProjectCompleted: true,
+	models.ProjectCancelled: true,
+}
+
+var validProjectHealth = map[models.ProjectHealth]bool{
+	models.HealthOnTrack:  true,
+	models.HealthAtRisk:   true,
+	models.HealthOffTrack: true,
+}
+
+func parseOptionalDate(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errors.New("invalid " + field + " format (use YYYY-MM-DD)")
+	}
+	return &date, nil
+}
+
+func (s *ProjectService) applyRequest(project *models.Project, req *ProjectRequest) error {
+	startDate, err := parseOptionalDate(req.StartDate, "start_date")
+	if err != nil {
+		return err
+	}
+	targetDate, err := parseOptionalDate(req.TargetDate, "target_date")
+	if err != nil {
+		return err
+	}
+	if startDate != nil && targetDate != nil && targetDate.Before(*startDate) {
+		return errors.New("target date must be after start date")
+	}
+
+	if req.OwnerID != 0 {
+		owner, err := s.userRepo.FindByID(req.OwnerID)
+		if err != nil || owner.OrganizationID != project.OrganizationID {
+			return errors.New("owner not found")
+		}
+		project.OwnerID = req.OwnerID
+	}
+
+	if req.Status != "" {
+		if !validProjectStatuses[req.Status] {
+			return errors.New("invalid project status")
+		}
+		project.Status = req.Status
+	}
+	if req.Health != "" {
+		if !validProjectHealth[req.Health] {
+			return errors.New("invalid project health")
+		}
+		project.Health = req.Health
+	}
+
+	project.Name = req.Name
+	project.Description = req.Description
+	project.StartDate = startDate
+	project.TargetDate = targetDate
+	return nil
+}
+
+func (s *ProjectService) Create(req *ProjectRequest, orgID, createdBy uint) (*models.Project, error) {
+	project := &models.Project{
+		OrganizationID: orgID,
+		OwnerID:        createdBy,
+		Status:         models.ProjectPlanned,
+		Health:         models.HealthOnTrack,
+		CreatedBy:      createdBy,
+	}
+	if err := s.applyRequest(project, req); err != nil {
+		return nil, err
+	}
+	if err := s.projectRepo.Create(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (s *ProjectService) GetByID(id uint) (*models.Project, error) {
+	return s.projectRepo.FindByID(id)
+}
+
+func (s *ProjectService) GetByOrganization(orgID uint) ([]models.Project, error) {
+	return s.projectRepo.FindByOrganization(orgID)
+}
+
+// Update changes a project; only its owner or creator may do so
+func (s *ProjectService) Update(id, userID uint, req *ProjectRequest) (*models.Project, error) {
+	project, err := s.projectRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("project not found")
+	}
+	if project.OwnerID != userID && project.CreatedBy != userID {
+		return nil, errors.New("only the project owner can edit this project")
+	}
+	if err := s.applyRequest(project, req); err != nil {
+		return nil, err
+	}
+	if err := s.projectRepo.Update(project); err != nil {
+		return nil, err
+	}
+	return s.projectRepo.FindByID(id)
+}
+
+func (s *ProjectService) Delete(id, userID uint) error {
+	project, err := s.projectRepo.FindByID(id)
+	if err != nil {
+		return errors.New("project not found")
+	}
+	if project.OwnerID != userID && project.CreatedBy != userID {
+		return errors.New("only the project owner can delete this project")
+	}
+	return s.projectRepo.Delete(id)
+}
+
+// GetIssues lists the project's issues from the teams the viewer belongs to.
+// Issues of other teams are only reported as a count.
+func (s *ProjectService) GetIssues(projectID, viewerID uint) (*ProjectIssues, error) {
+	teamIDs, err := s.teamRepo.FindTeamIDsByUser(viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := s.projectRepo.FindIssues(projectID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.projectRepo.CountIssues(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectIssues{
+		Issues:       issues,
+		HiddenIssues: total - int64(len(issues)),
+	}, nil
+}
+
+func (s *ProjectService) GetRollup(projectID uint) (*ProjectRollup, error) {
+	rollup := &ProjectRollup{
+		ProjectID: projectID,
+		ByCategory: map[string]int64{
+			string(models.CategoryTodo):       0,
+			string(models.CategoryInProgress): 0,
+			string(models.CategoryDone):       0,
+		},
+	}
+
+	counts, err := s.projectRepo.CountByCategory(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		rollup.ByCategory[count.Category] += count.Count
+		rollup.TotalIssues += count.Count
+	}
+
+	if rollup.ByTeam, err = s.projectRepo.CountByTeam(projectID); err != nil {
+		return nil, err
+	}
+	if rollup.LoggedMinutes, err = s.projectRepo.SumLoggedMinutes(projectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if rollup.OverdueIssues, err = s.projectRepo.CountOverdue(projectID, today); err != nil {
+		return nil, err
+	}
+
+	return rollup, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"strings"
+)
+
+// rankDigits is the alphabet used for backlog ranks. Ranks are compared
+// byte-wise (the column uses the "C" collation), so the digits must be in
+// ascending ASCII order.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// rankBetween returns a rank that sorts strictly between prev and next.
+// An empty prev means "before everything", an empty next means "after
+// everything". Ranks never end with the zero digit, which guarantees there
+// is always room for another rank in between.
+func rankBetween(prev, next string) (string, error) {
+	if next != "" && prev >= next {
+		return "", errors.New("invalid rank range")
+	}
+	return rankMidpoint(prev, next), nil
+}
+
+func rankMidpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix and find the midpoint of the remainder
+		n := 0
+		for n < len(b) && rankDigitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + rankMidpoint(safeSlice(a, n), b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(rankDigits, a[0])
+	}
+	digitB := len(rankDigits)
+	if b != "" {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB+1)/2])
+	}
+
+	// Digits are consecutive
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(rankDigits[digitA]) + rankMidpoint(safeSlice(a, 1), "")
+}
+
+func rankDigitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return rankDigits[0]
+}
+
+func safeSlice(s string, i int) string {
+	if i >= len(s) {
+		return ""
+	}
+	return s[i:]
+}
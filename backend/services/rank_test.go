@@ -0,0 +1,94 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next string
+		want       string
+	}{
+		{"empty backlog", "", "", "i"},
+		{"after last", "i", "", "r"},
+		{"before first", "", "i", "9"},
+		{"between far apart", "a", "z", "n"},
+		{"between consecutive digits", "a", "b", "ai"},
+		{"after a longer rank", "ai", "b", "ar"},
+		{"before a longer rank", "a", "ai", "a9"},
+		{"common prefix", "abc", "abz", "abo"},
+		{"next with more digits", "a", "b5", "b"},
+		{"last digit", "z", "", "zi"},
+		{"first digit", "", "1", "0i"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rankBetween(tt.prev, tt.next)
+			if err != nil {
+				t.Fatalf("rankBetween(%q, %q) returned error: %v", tt.prev, tt.next, err)
+			}
+			if got != tt.want {
+				t.Errorf("rankBetween(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+			}
+			if got <= tt.prev || (tt.next != "" && got >= tt.next) {
+				t.Errorf("rankBetween(%q, %q) = %q is not between them", tt.prev, tt.next, got)
+			}
+		})
+	}
+}
+
+func TestRankBetweenInvalidRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next string
+	}{
+		{"equal", "i", "i"},
+		{"reversed", "r", "i"},
+		{"prefix after", "ia", "i"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := rankBetween(tt.prev, tt.next); err == nil {
+				t.Errorf("rankBetween(%q, %q) = %q, want an error", tt.prev, tt.next, got)
+			}
+		})
+	}
+}
+
+// Repeatedly inserting at the same place must keep finding room
+func TestRankBetweenRepeated(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next string
+		// moveNext inserts each rank above the previous one
+		moveNext bool
+	}{
+		{"at the top", "", "i", true},
+		{"at the bottom", "i", "", false},
+		{"after the first", "i", "r", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev, next := tt.prev, tt.next
+			for i := 0; i < 200; i++ {
+				rank, err := rankBetween(prev, next)
+				if err != nil {
+					t.Fatalf("insert %d: rankBetween(%q, %q) returned error: %v", i, prev, next, err)
+				}
+				if rank <= prev || (next != "" && rank >= next) {
+					t.Fatalf("insert %d: rankBetween(%q, %q) = %q is not between them", i, prev, next, rank)
+				}
+				if strings.HasSuffix(rank, "0") {
+					t.Fatalf("insert %d: rank %q ends with the zero digit", i, rank)
+				}
+				if tt.moveNext {
+					next = rank
+				} else {
+					prev = rank
+				}
+			}
+		})
+	}
+}
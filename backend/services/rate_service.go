@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrRateNotAllowed is returned when the caller does not manage everyone a
+// rate applies to
+var ErrRateNotAllowed = errors.New("you can only set rates for people in teams you manage")
+
+type RateService struct {
+	rateRepo *repositories.RateRepository
+	userRepo *repositories.UserRepository
+	teamRepo *repositories.TeamRepository
+}
+
+func NewRateService(rateRepo *repositories.RateRepository, userRepo *repositories.UserRepository, teamRepo *repositories.TeamRepository) *RateService {
+	return &RateService{
+		rateRepo: rateRepo,
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+	}
+}
+
+type RateRequest struct {
+	UserID        *uint            `json:"user_id"`
+	Role          *models.TeamRole `json:"role"`
+	Rate          float64          `json:"rate"`
+	EffectiveFrom string           `json:"effective_from" binding:"required"`
+}
+
+var teamRoles = map[models.TeamRole]bool{
+	models.RoleManager:     true,
+	models.RoleAssistant:   true,
+	models.RoleMember:      true,
+	models.RoleStakeholder: true,
+}
+
+// Create adds a rate. Rates are not edited; a new rate with a later
+// effective_from replaces the previous one from that date.
+func (s *RateService) Create(req *RateRequest, orgID, createdBy uint) (*models.HourlyRate, error) {
+	if (req.UserID == nil) == (req.Role == nil) {
+		return nil, errors.New("set either user_id or role")
+	}
+	if req.Rate < 0 {
+		return nil, errors.New("rate cannot be negative")
+	}
+	if req.Role != nil && !teamRoles[*req.Role] {
+		return nil, errors.New("invalid role")
+	}
+	if req.UserID != nil {
+		user, err := s.userRepo.FindByID(*req.UserID)
+		if err != nil || user.OrganizationID != orgID {
+			return nil, errors.New("user not found")
+		}
+	}
+	if err := s.CheckWriter(req.UserID, orgID, createdBy); err != nil {
+		return nil, err
+	}
+	effectiveFrom, err := time.Parse("2006-01-02", req.EffectiveFrom)
+	if err != nil {
+		return nil, errors.New("invalid effective_from format (use YYYY-MM-DD)")
+	}
+
+	rate := &models.HourlyRate{
+		OrganizationID: orgID,
+		UserID:         req.UserID,
+		Role:           req.Role,
+		Rate:           req.Rate,
+		EffectiveFrom:  effectiveFrom,
+		CreatedBy:      createdBy,
+	}
+	if err := s.rateRepo.Create(rate); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("a rate starting on that date already exists")
+		}
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *RateService) GetByOrganization(orgID uint) ([]models.HourlyRate, error) {
+	return s.rateRepo.FindByOrganization(orgID)
+}
+
+func (s *RateService) GetByID(id uint) (*models.HourlyRate, error) {
+	return s.rateRepo.FindByID(id)
+}
+
+func (s *RateService) Delete(id uint) error {
+	return s.rateRepo.Delete(id)
+}
+
+// CheckWriter checks that the caller may set or delete a rate. A user's rate
+// needs a manager of one of the user's teams. A role rate applies across the
+// organization, so it needs a manager of every team in it.
+func (s *RateService) CheckWriter(userID *uint, orgID, callerID uint) error {
+	managed, err := s.teamRepo.FindManagedTeamIDs(callerID)
+	if err != nil {
+		return err
+	}
+	isManaged := make(map[uint]bool, len(managed))
+	for _, teamID := range managed {
+		isManaged[teamID] = true
+	}
+
+	if userID != nil {
+		teamIDs, err := s.teamRepo.FindTeamIDsByUser(*userID)
+		if err != nil {
+			return err
+		}
+		for _, teamID := range teamIDs {
+			if isManaged[teamID] {
+				return nil
+			}
+		}
+		return ErrRateNotAllowed
+	}
+
+	teams, err := s.teamRepo.FindByOrganization(orgID)
+	if err != nil {
+		return err
+	}
+	for _, team := range teams {
+		if !isManaged[team.ID] {
+			return ErrRateNotAllowed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"task-management/models"
+	"time"
+)
+
+// maxOccurrenceDays bounds the date range recurring meetings are expanded over
+const maxOccurrenceDays = 366
+
+// weekdayCodes are the iCalendar weekday codes, in week order from Monday
+var weekdayCodes = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// weekdayOffset is the number of days from Monday to the weekday of the code
+func weekdayOffset(code string) (int, bool) {
+	for i, c := range weekdayCodes {
+		if c == code {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func weekdayCode(date time.Time) string {
+	return weekdayCodes[(int(date.Weekday())+6)%7]
+}
+
+// RecurrenceInput is how a meeting repeats, as sent by clients
+type RecurrenceInput struct {
+	IsRecurring      bool                     `json:"is_recurring"`
+	RecurringPattern *models.RecurringPattern `json:"recurring_pattern"`
+	// Interval repeats the meeting every N days, weeks or months; default 1
+	Interval int `json:"recurrence_interval"`
+	// ByDay lists the weekdays of weekly meetings, e.g. ["MO", "WE"]
+	ByDay []string `json:"recurrence_by_day"`
+	// Until (YYYY-MM-DD) and Count end the series; at most one can be set
+	Until string `json:"recurrence_until"`
+	Count *int   `json:"recurrence_count"`
+}
+
+// Apply validates the recurrence and sets it on the meeting, whose times must
+// already be set
+func (in *RecurrenceInput) Apply(meeting *models.Meeting) error {
+	meeting.IsRecurring = in.IsRecurring
+	meeting.RecurringPattern = nil
+	meeting.RecurrenceInterval = 1
+	meeting.RecurrenceByDay = ""
+	meeting.RecurrenceUntil = nil
+	meeting.RecurrenceCount = nil
+	if !in.IsRecurring {
+		return nil
+	}
+
+	if in.RecurringPattern == nil {
+		return errors.New("recurring_pattern is required for recurring meetings")
+	}
+	switch *in.RecurringPattern {
+	case models.RecurringDaily, models.RecurringWeekly, models.RecurringMonthly:
+	default:
+		return errors.New("recurring_pattern must be DAILY, WEEKLY or MONTHLY")
+	}
+	pattern := *in.RecurringPattern
+	meeting.RecurringPattern = &pattern
+
+	if in.Interval < 0 {
+		return errors.New("recurrence_interval must be positive")
+	}
+	if in.Interval > 0 {
+		meeting.RecurrenceInterval = in.Interval
+	}
+
+	if len(in.ByDay) > 0 {
+		if pattern != models.RecurringWeekly {
+			return errors.New("recurrence_by_day only applies to weekly meetings")
+		}
+		days := make(map[string]bool)
+		for _, day := range in.ByDay {
+			code := strings.ToUpper(strings.TrimSpace(day))
+			if _, ok := weekdayOffset(code); !ok {
+				return errors.New("recurrence_by_day must contain MO, TU, WE, TH, FR, SA or SU")
+			}
+			days[code] = true
+		}
+		var codes []string
+		for _, code := range weekdayCodes {
+			if days[code] {
+				codes = append(codes, code)
+			}
+		}
+		meeting.RecurrenceByDay = strings.Join(codes, ",")
+	}
+
+	if in.Until != "" && in.Count != nil {
+		return errors.New("recurrence_until and recurrence_count cannot both be set")
+	}
+	if in.Until != "" {
+		until, err := time.Parse("2006-01-02", in.Until)
+		if err != nil {
+			return errors.New("invalid recurrence_until date format")
+		}
+		if until.Before(meetingDay(meeting)) {
+			return errors.New("recurrence_until must not be before the meeting date")
+		}
+		meeting.RecurrenceUntil = &until
+	}
+	if in.Count != nil {
+		if *in.Count < 1 {
+			return errors.New("recurrence_count must be positive")
+		}
+		count := *in.Count
+		meeting.RecurrenceCount = &count
+	}
+	return nil
+}
+
+// recurrenceDays returns the weekdays of a weekly meeting as offsets from
+// Monday, in order
+func recurrenceDays(meeting *models.Meeting) []int {
+	var offsets []int
+	for _, code := range strings.Split(meeting.RecurrenceByDay, ",") {
+		if offset, ok := weekdayOffset(code); ok {
+			offsets = append(offsets, offset)
+		}
+	}
+	if len(offsets) == 0 {
+		offset, _ := weekdayOffset(weekdayCode(meetingDay(meeting)))
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
+
+// meetingDay is the date of a meeting's first occurrence in its timezone
+func meetingDay(meeting *models.Meeting) time.Time {
+	return calendarDay(meeting.StartsAt.In(meeting.Zone()))
+}
+
+// occurrenceTimes returns when the occurrence of a meeting on the date, in its
+// timezone, was scheduled to start and end by the series. Occurrences keep the
+// wall-clock times of the first one, so across DST changes they move in UTC.
+func occurrenceTimes(meeting *models.Meeting, date time.Time) (time.Time, time.Time) {
+	zone := meeting.Zone()
+	first, last := meeting.StartsAt.In(zone), meeting.EndsAt.In(zone)
+	days := int(calendarDay(last).Sub(calendarDay(first)).Hours() / 24)
+	start := wallTime(date.Year(), date.Month(), date.Day(), first.Hour(), first.Minute(), first.Second(), zone)
+	end := wallTime(date.Year(), date.Month(), date.Day()+days, last.Hour(), last.Minute(), last.Second(), zone)
+	// The end time can be skipped when the clocks go forward
+	if !end.After(start) {
+		end = start.Add(meeting.EndsAt.Sub(meeting.StartsAt))
+	}
+	return start, end
+}
+
+// meetingDates returns the dates of a meeting's occurrences in its timezone,
+// before changes to single occurrences, from its first through to the given
+// date. Occurrences are counted from the first one, so that a count ends the
+// series on the same date whatever the range.
+func meetingDates(meeting *models.Meeting, to time.Time) []time.Time {
+	start := meetingDay(meeting)
+	to = calendarDay(to)
+	if !meeting.IsRecurring || meeting.RecurringPattern == nil {
+		if start.After(to) {
+			return nil
+		}
+		return []time.Time{start}
+	}
+
+	if meeting.RecurrenceUntil != nil && calendarDay(*meeting.RecurrenceUntil).Before(to) {
+		to = calendarDay(*meeting.RecurrenceUntil)
+	}
+	interval := meeting.RecurrenceInterval
+	if interval < 1 {
+		interval = 1
+	}
+	var dates []time.Time
+	full := func() bool {
+		return meeting.RecurrenceCount != nil && len(dates) >= *meeting.RecurrenceCount
+	}
+
+	switch *meeting.RecurringPattern {
+	case models.RecurringDaily:
+		for day := start; !day.After(to) && !full(); day = day.AddDate(0, 0, interval) {
+			dates = append(dates, day)
+		}
+	case models.RecurringWeekly:
+		days := recurrenceDays(meeting)
+		for monday := weekStart(start); !monday.After(to) && !full(); monday = monday.AddDate(0, 0, 7*interval) {
+			for _, offset := range days {
+				day := monday.AddDate(0, 0, offset)
+				if day.Before(start) {
+					continue
+				}
+				if day.After(to) || full() {
+					break
+				}
+				dates = append(dates, day)
+			}
+		}
+	case models.RecurringMonthly:
+		// Months without the day of the month, such as February for the 30th,
+		// are skipped
+		for months := 0; !full(); months += interval {
+			first := time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+			if first.After(to) {
+				break
+			}
+			day := first.AddDate(0, 0, start.Day()-1)
+			if day.Month() != first.Month() || day.After(to) {
+				continue
+			}
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+// isOccurrenceDate reports whether the series of a meeting has an occurrence
+// on the date, in its timezone
+func isOccurrenceDate(meeting *models.Meeting, date time.Time) bool {
+	dates := meetingDates(meeting, date)
+	return len(dates) > 0 && dates[len(dates)-1].Equal(calendarDay(date))
+}
+
+// nextMeetingDate returns the date of the first occurrence of a recurring
+// meeting on or after the date, in its timezone, and the number of occurrences
+// before it. It returns false when the series ends before the date.
+func nextMeetingDate(meeting *models.Meeting, date time.Time) (time.Time, int, bool) {
+	date = calendarDay(date)
+	interval := meeting.RecurrenceInterval
+	if interval < 1 {
+		interval = 1
+	}
+	// Every pattern repeats within interval+1 years
+	dates := meetingDates(meeting, date.AddDate(interval+1, 0, 0))
+	for i, day := range dates {
+		if !day.Before(date) {
+			return day, i, true
+		}
+	}
+	return time.Time{}, 0, false
+}
+
+// MeetingOccurrence is one occurrence of a meeting, with the changes made to
+// it alone applied
+type MeetingOccurrence struct {
+	models.Meeting
+	// OccurrenceDate is the date the occurrence was scheduled on by the
+	// series, which identifies it when it is changed or cancelled
+	OccurrenceDate string `json:"occurrence_date"`
+	Modified       bool   `json:"modified"`
+}
+
+// applyException returns the occurrence of the meeting on the date with its
+// exception, if any, applied
+func applyException(meeting *models.Meeting, date time.Time, exception *models.MeetingException) MeetingOccurrence {
+	occurrence := MeetingOccurrence{
+		Meeting:        *meeting,
+		OccurrenceDate: date.Format("2006-01-02"),
+	}
+	occurrence.StartsAt, occurrence.EndsAt = occurrenceTimes(meeting, date)
+	occurrence.Exceptions = nil
+	if exception == nil || exception.Cancelled {
+		return occurrence
+	}
+
+	occurrence.Modified = true
+	if exception.Title != nil {
+		occurrence.Title = *exception.Title
+	}
+	if exception.Description != nil {
+		occurrence.Description = *exception.Description
+	}
+	if exception.Location != nil {
+		occurrence.Location = *exception.Location
+	}
+	if exception.StartsAt != nil && exception.EndsAt != nil {
+		occurrence.StartsAt, occurrence.EndsAt = *exception.StartsAt, *exception.EndsAt
+	}
+	return occurrence
+}
+
+// meetingOccurrences expands a meeting into its occurrences taking place in
+// the time from up to to, leaving out cancelled ones. A changed occurrence is
+// in the range when its new time is.
+func meetingOccurrences(meeting *models.Meeting, from, to time.Time) []MeetingOccurrence {
+	exceptions := make(map[time.Time]*models.MeetingException)
+	// Occurrences moved into the range from after it are expanded too
+	last := calendarDay(to.In(meeting.Zone()))
+	for i := range meeting.Exceptions {
+		date := calendarDay(meeting.Exceptions[i].OccurrenceDate)
+		exceptions[date] = &meeting.Exceptions[i]
+		if date.After(last) {
+			last = date
+		}
+	}
+
+	var occurrences []MeetingOccurrence
+	for _, date := range meetingDates(meeting, last) {
+		exception := exceptions[date]
+		if exception != nil && exception.Cancelled {
+			continue
+		}
+		occurrence := applyException(meeting, date, exception)
+		if !occurrence.StartsAt.Before(to) || !occurrence.EndsAt.After(from) {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
+
+// dayRange checks a range of dates and returns the time from the start of the
+// first day up to the end of the last, in the timezone
+func dayRange(from, to time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	from, to = calendarDay(from), calendarDay(to)
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("end_date must not be before start_date")
+	}
+	if to.Sub(from) > maxOccurrenceDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("date range cannot exceed 366 days")
+	}
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc)
+	return start, end, nil
+}
+
+// ExpandMeetings lists the occurrences of the meetings taking place on the
+// dates of the range in the timezone, ordered by start
+func ExpandMeetings(meetings []models.Meeting, from, to time.Time, loc *time.Location) ([]MeetingOccurrence, error) {
+	start, end, err := dayRange(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := []MeetingOccurrence{}
+	for i := range meetings {
+		occurrences = append(occurrences, meetingOccurrences(&meetings[i], start, end)...)
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartsAt.Before(occurrences[j].StartsAt)
+	})
+	return occurrences, nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"reflect"
+	"task-management/models"
+	"testing"
+	"time"
+)
+
+// testMeeting is a meeting of the given times in the zone, repeating with the
+// pattern unless it is empty
+func testMeeting(start, end time.Time, pattern models.RecurringPattern) *models.Meeting {
+	meeting := &models.Meeting{
+		StartsAt:           start,
+		EndsAt:             end,
+		Timezone:           start.Location().String(),
+		RecurrenceInterval: 1,
+	}
+	if pattern != "" {
+		meeting.IsRecurring = true
+		meeting.RecurringPattern = &pattern
+	}
+	return meeting
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func days(year int, month time.Month, ds ...int) []time.Time {
+	var dates []time.Time
+	for _, d := range ds {
+		dates = append(dates, day(year, month, d))
+	}
+	return dates
+}
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s is not available", name)
+	}
+	return loc
+}
+
+func TestMeetingDates(t *testing.T) {
+	jakarta := loadLocation(t, "Asia/Jakarta")
+	count := func(n int) *int { return &n }
+	until := func(year int, month time.Month, d int) *time.Time {
+		date := day(year, month, d)
+		return &date
+	}
+
+	// 2026-01-05 is a Monday
+	monday := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		start    time.Time
+		pattern  models.RecurringPattern
+		interval int
+		byDay    string
+		until    *time.Time
+		count    *int
+		to       time.Time
+		want     []time.Time
+	}{
+		{name: "single meeting", start: monday, to: day(2026, 1, 31), want: days(2026, 1, 5)},
+		{name: "single meeting after the range", start: monday, to: day(2026, 1, 4), want: nil},
+		{name: "recurring after the range", start: monday, pattern: models.RecurringDaily, to: day(2026, 1, 4), want: nil},
+		{name: "daily", start: monday, pattern: models.RecurringDaily, to: day(2026, 1, 8), want: days(2026, 1, 5, 6, 7, 8)},
+		{
+			name: "daily every other day with a count", start: monday, pattern: models.RecurringDaily,
+			interval: 2, count: count(3), to: day(2026, 1, 31), want: days(2026, 1, 5, 7, 9),
+		},
+		{
+			name: "daily until", start: monday, pattern: models.RecurringDaily,
+			until: until(2026, 1, 7), to: day(2026, 1, 31), want: days(2026, 1, 5, 6, 7),
+		},
+		{
+			name: "weekly on the start's weekday", start: monday, pattern: models.RecurringWeekly,
+			to: day(2026, 1, 26), want: days(2026, 1, 5, 12, 19, 26),
+		},
+		{
+			name: "weekly by day with a count", start: monday, pattern: models.RecurringWeekly,
+			byDay: "MO,WE,FR", count: count(5), to: day(2026, 3, 1), want: days(2026, 1, 5, 7, 9, 12, 14),
+		},
+		{
+			name: "every other week by day", start: monday, pattern: models.RecurringWeekly,
+			interval: 2, byDay: "TU,TH", to: day(2026, 1, 25), want: days(2026, 1, 6, 8, 20, 22),
+		},
+		{
+			name: "weekly by day from midweek", start: monday.AddDate(0, 0, 2), pattern: models.RecurringWeekly,
+			byDay: "MO,WE", to: day(2026, 1, 14), want: days(2026, 1, 7, 12, 14),
+		},
+		{
+			name: "weekly count counts from the first occurrence", start: monday, pattern: models.RecurringWeekly,
+			count: count(2), to: day(2026, 1, 8), want: days(2026, 1, 5),
+		},
+		{
+			name: "monthly skips months without the day", start: time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			pattern: models.RecurringMonthly, to: day(2026, 6, 30),
+			want: []time.Time{day(2026, 1, 31), day(2026, 3, 31), day(2026, 5, 31)},
+		},
+		{
+			name: "monthly until", start: time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC),
+			pattern: models.RecurringMonthly, until: until(2026, 3, 15), to: day(2026, 12, 31),
+			want: []time.Time{day(2026, 1, 15), day(2026, 2, 15), day(2026, 3, 15)},
+		},
+		{
+			name: "every third month", start: time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC),
+			pattern: models.RecurringMonthly, interval: 3, to: day(2026, 12, 31),
+			want: []time.Time{day(2026, 1, 15), day(2026, 4, 15), day(2026, 7, 15), day(2026, 10, 15)},
+		},
+		{
+			// 20:00 UTC on the 5th is 03:00 on the 6th in Jakarta
+			name: "dates are in the meeting's timezone", start: time.Date(2026, 1, 5, 20, 0, 0, 0, time.UTC).In(jakarta),
+			pattern: models.RecurringDaily, to: day(2026, 1, 7), want: days(2026, 1, 6, 7),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meeting := testMeeting(tt.start, tt.start.Add(time.Hour), tt.pattern)
+			if tt.interval > 0 {
+				meeting.RecurrenceInterval = tt.interval
+			}
+			meeting.RecurrenceByDay = tt.byDay
+			meeting.RecurrenceUntil = tt.until
+			meeting.RecurrenceCount = tt.count
+
+			got := meetingDates(meeting, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("meetingDates = %v, want %v", got, tt.want)
+			}
+			for _, date := range tt.want {
+				if !isOccurrenceDate(meeting, date) {
+					t.Errorf("isOccurrenceDate(%s) = false, want true", date.Format("2006-01-02"))
+				}
+			}
+		})
+	}
+}
+
+func TestOccurrenceTimes(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	at := func(year int, month time.Month, d, hour, min int) time.Time {
+		return time.Date(year, month, d, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		date       time.Time
+		wantStart  time.Time
+		wantEnd    time.Time
+	}{
+		{
+			name:  "first occurrence",
+			start: time.Date(2026, 3, 2, 9, 0, 0, 0, newYork), end: time.Date(2026, 3, 2, 10, 0, 0, 0, newYork),
+			date:      day(2026, 3, 2),
+			wantStart: at(2026, 3, 2, 14, 0), wantEnd: at(2026, 3, 2, 15, 0),
+		},
+		{
+			// Clocks go forward on 8 March; the meeting stays at 09:00
+			name:  "after the clocks go forward",
+			start: time.Date(2026, 3, 2, 9, 0, 0, 0, newYork), end: time.Date(2026, 3, 2, 10, 0, 0, 0, newYork),
+			date:      day(2026, 3, 9),
+			wantStart: at(2026, 3, 9, 13, 0), wantEnd: at(2026, 3, 9, 14, 0),
+		},
+		{
+			// Clocks go back on 1 November
+			name:  "after the clocks go back",
+			start: time.Date(2026, 10, 26, 9, 0, 0, 0, newYork), end: time.Date(2026, 10, 26, 10, 0, 0, 0, newYork),
+			date:      day(2026, 11, 2),
+			wantStart: at(2026, 11, 2, 14, 0), wantEnd: at(2026, 11, 2, 15, 0),
+		},
+		{
+			// 02:30 does not exist on 8 March and moves forward by the change
+			name:  "end time skipped by the change",
+			start: time.Date(2026, 3, 1, 1, 30, 0, 0, newYork), end: time.Date(2026, 3, 1, 2, 30, 0, 0, newYork),
+			date:      day(2026, 3, 8),
+			wantStart: at(2026, 3, 8, 6, 30), wantEnd: at(2026, 3, 8, 7, 30),
+		},
+		{
+			// Both times become 03:30 EDT, so the meeting keeps its length
+			name:  "start time skipped by the change",
+			start: time.Date(2026, 3, 1, 2, 30, 0, 0, newYork), end: time.Date(2026, 3, 1, 3, 30, 0, 0, newYork),
+			date:      day(2026, 3, 8),
+			wantStart: at(2026, 3, 8, 7, 30), wantEnd: at(2026, 3, 8, 8, 30),
+		},
+		{
+			name:  "ending the next day",
+			start: time.Date(2026, 1, 5, 23, 0, 0, 0, newYork), end: time.Date(2026, 1, 6, 1, 0, 0, 0, newYork),
+			date:      day(2026, 1, 7),
+			wantStart: at(2026, 1, 8, 4, 0), wantEnd: at(2026, 1, 8, 6, 0),
+		},
+		{
+			name:  "in UTC",
+			start: at(2026, 1, 5, 9, 0), end: at(2026, 1, 5, 9, 45),
+			date:      day(2026, 6, 1),
+			wantStart: at(2026, 6, 1, 9, 0), wantEnd: at(2026, 6, 1, 9, 45),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meeting := testMeeting(tt.start, tt.end, models.RecurringDaily)
+			start, end := occurrenceTimes(meeting, tt.date)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("occurrenceTimes = %s to %s, want %s to %s",
+					start.UTC(), end.UTC(), tt.wantStart, tt.wantEnd)
+			}
+			if !end.After(start) {
+				t.Errorf("occurrence ends at %s, not after it starts at %s", end, start)
+			}
+		})
+	}
+}
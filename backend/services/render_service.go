@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"task-management/models"
+	"task-management/repositories"
+)
+
+// RenderService turns Markdown descriptions and comments into sanitized HTML
+// with links for issue references and mentions
+type RenderService struct {
+	issueRepo *repositories.IssueRepository
+	userRepo  *repositories.UserRepository
+	teamRepo  *repositories.TeamRepository
+}
+
+func NewRenderService(
+	issueRepo *repositories.IssueRepository,
+	userRepo *repositories.UserRepository,
+	teamRepo *repositories.TeamRepository,
+) *RenderService {
+	return &RenderService{
+		issueRepo: issueRepo,
+		userRepo:  userRepo,
+		teamRepo:  teamRepo,
+	}
+}
+
+// Render converts Markdown to HTML. References to issues and users outside
+// the organization stay plain text.
+func (s *RenderService) Render(text string, orgID uint) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	var users []models.User
+	tokens := ParseMentions(text)
+	if len(tokens) > 0 {
+		var err error
+		if users, err = s.userRepo.FindByOrganization(orgID); err != nil {
+			return "", err
+		}
+	}
+	mentioned := matchMentions(users, tokens)
+
+	var refs []uint
+	for _, m := range mdIssueRef.FindAllStringSubmatch(text, -1) {
+		if id, err := strconv.ParseUint(m[2], 10, 32); err == nil {
+			refs = append(refs, uint(id))
+		}
+	}
+	existing, err := s.issueRepo.FindExistingIDs(orgID, refs)
+	if err != nil {
+		return "", err
+	}
+	issues := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		issues[id] = true
+	}
+
+	return renderMarkdown(text, &markdownLinker{
+		issueURL: func(id uint) (string, bool) {
+			return fmt.Sprintf("/issues/%d", id), issues[id]
+		},
+		mentionURL: func(token string) (string, bool) {
+			user, ok := mentioned[token]
+			if !ok {
+				return "", false
+			}
+			return fmt.Sprintf("/users/%d", user.ID), true
+		},
+	}), nil
+}
+
+// RenderForTeam renders text written in a team's issue or comment
+func (s *RenderService) RenderForTeam(text string, teamID uint) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return "", err
+	}
+	return s.Render(text, team.OrganizationID)
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"task-management/repositories"
+	"time"
+)
+
+type ReportService struct {
+	reportRepo *repositories.ReportRepository
+}
+
+func NewReportService(reportRepo *repositories.ReportRepository) *ReportService {
+	return &ReportService{reportRepo: reportRepo}
+}
+
+type ReportFormat string
+
+const (
+	ReportJSON ReportFormat = "json"
+	ReportCSV  ReportFormat = "csv"
+	ReportXLSX ReportFormat = "xlsx"
+)
+
+var reportPeriods = map[string]bool{"day": true, "week": true, "month": true}
+
+// TimeReportRequest holds the raw report parameters; see NewTimeReportQuery
+type TimeReportRequest struct {
+	From      string
+	To        string
+	GroupBy   string
+	Period    string
+	UserID    *uint
+	ProjectID *uint
+	LabelID   *uint
+	Billable  *bool
+}
+
+// NewTimeReportQuery validates a report request over the given teams. Reports
+// are grouped by user unless group_by says otherwise.
+func NewTimeReportQuery(req *TimeReportRequest, teamIDs []uint) (*repositories.TimeReportQuery, error) {
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		return nil, errors.New("from is required (YYYY-MM-DD)")
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		return nil, errors.New("to is required (YYYY-MM-DD)")
+	}
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+
+	query := &repositories.TimeReportQuery{
+		TeamIDs:   teamIDs,
+		From:      from,
+		To:        to,
+		Period:    req.Period,
+		UserID:    req.UserID,
+		ProjectID: req.ProjectID,
+		LabelID:   req.LabelID,
+		Billable:  req.Billable,
+	}
+	if query.Period == "" {
+		query.Period = "month"
+	}
+	if !reportPeriods[query.Period] {
+		return nil, errors.New("period must be day, week or month")
+	}
+
+	seen := make(map[string]bool)
+	for _, dimension := range strings.Split(req.GroupBy, ",") {
+		dimension = strings.TrimSpace(dimension)
+		if dimension == "" {
+			continue
+		}
+		if !repositories.IsReportDimension(dimension) {
+			return nil, fmt.Errorf("invalid group_by dimension: %s", dimension)
+		}
+		if !seen[dimension] {
+			seen[dimension] = true
+			query.GroupBy = append(query.GroupBy, dimension)
+		}
+	}
+	if len(query.GroupBy) == 0 {
+		query.GroupBy = []string{"user"}
+	}
+	return query, nil
+}
+
+// TimeReportLine is one row of a JSON report; Group maps each dimension to
+// its value
+type TimeReportLine struct {
+	Group           map[string]interface{} `json:"group"`
+	Minutes         int64                  `json:"minutes"`
+	Hours           float64                `json:"hours"`
+	BillableMinutes int64                  `json:"billable_minutes"`
+	BillableHours   float64                `json:"billable_hours"`
+	Cost            float64                `json:"cost"`
+	BillableCost    float64                `json:"billable_cost"`
+}
+
+type TimeReport struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	GroupBy []string         `json:"group_by"`
+	Period  string           `json:"period"`
+	Rows    []TimeReportLine `json:"rows"`
+	Totals  TimeReportLine   `json:"totals"`
+}
+
+func hours(minutes int64) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func reportLine(query *repositories.TimeReportQuery, row *repositories.TimeReportRow) TimeReportLine {
+	line := TimeReportLine{
+		Minutes:         row.Minutes,
+		Hours:           hours(row.Minutes),
+		BillableMinutes: row.BillableMinutes,
+		BillableHours:   hours(row.BillableMinutes),
+		Cost:            roundMoney(row.Cost),
+		BillableCost:    roundMoney(row.BillableCost),
+	}
+	if len(row.Keys) > 0 {
+		line.Group = make(map[string]interface{}, len(row.Keys))
+		for i, dimension := range query.GroupBy {
+			key := row.Keys[i]
+			if dimension == "period" {
+				line.Group[dimension] = key.Name
+			} else {
+				line.Group[dimension] = map[string]interface{}{"id": key.ID, "name": key.Name}
+			}
+		}
+	}
+	return line
+}
+
+// GetTimeReport returns the whole report as rows and totals
+func (s *ReportService) GetTimeReport(query *repositories.TimeReportQuery) (*TimeReport, error) {
+	report := &TimeReport{
+		From:    query.From.Format("2006-01-02"),
+		To:      query.To.Format("2006-01-02"),
+		GroupBy: query.GroupBy,
+		Period:  query.Period,
+		Rows:    []TimeReportLine{},
+	}
+	err := s.reportRepo.StreamTimeReport(query, func(row *repositories.TimeReportRow) error {
+		report.Rows = append(report.Rows, reportLine(query, row))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.TimeReportTotals(query)
+	if err != nil {
+		return nil, err
+	}
+	report.Totals = reportLine(query, totals)
+	return report, nil
+}
+
+func reportHeader(query *repositories.TimeReportQuery) []interface{} {
+	var header []interface{}
+	for _, dimension := range query.GroupBy {
+		title := strings.ToUpper(dimension[:1]) + dimension[1:]
+		if dimension == "period" {
+			header = append(header, title)
+		} else {
+			header = append(header, title+" ID", title)
+		}
+	}
+	return append(header, "Minutes", "Hours", "Billable minutes", "Billable hours", "Cost", "Billable cost")
+}
+
+func reportCells(query *repositories.TimeReportQuery, row *repositories.TimeReportRow) []interface{} {
+	var cells []interface{}
+	for i, dimension := range query.GroupBy {
+		key := row.Keys[i]
+		if dimension == "period" {
+			cells = append(cells, key.Name)
+			continue
+		}
+		if key.ID != nil {
+			cells = append(cells, *key.ID, key.Name)
+		} else {
+			cells = append(cells, nil, "None")
+		}
+	}
+	return append(cells,
+		row.Minutes, hours(row.Minutes),
+		row.BillableMinutes, hours(row.BillableMinutes),
+		roundMoney(row.Cost), roundMoney(row.BillableCost))
+}
+
+// totalCells labels the totals row in the first column
+func totalCells(query *repositories.TimeReportQuery, totals *repositories.TimeReportRow) []interface{} {
+	cells := reportCells(query, &repositories.TimeReportRow{
+		Keys:            make([]repositories.TimeReportKey, len(query.GroupBy)),
+		Minutes:         totals.Minutes,
+		BillableMinutes: totals.BillableMinutes,
+		Cost:            totals.Cost,
+		BillableCost:    totals.BillableCost,
+	})
+	for i := range cells[:len(cells)-6] {
+		cells[i] = nil
+	}
+	cells[0] = "Total"
+	return cells
+}
+
+// csvSafe keeps spreadsheet apps from evaluating text as a formula
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// StreamTimeReport writes the report as CSV or XLSX while it is read from the
+// database, ending with a totals row
+func (s *ReportService) StreamTimeReport(w io.Writer, query *repositories.TimeReportQuery, format ReportFormat) error {
+	var writeRow func(cells []interface{}) error
+	var flush, finish func() error
+
+	switch format {
+	case ReportCSV:
+		cw := csv.NewWriter(w)
+		writeRow = func(cells []interface{}) error {
+			record := make([]string, len(cells))
+			for i, cell := range cells {
+				switch v := cell.(type) {
+				case nil:
+				case string:
+					record[i] = csvSafe(v)
+				default:
+					record[i] = fmt.Sprint(v)
+				}
+			}
+			return cw.Write(record)
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+		finish = flush
+	case ReportXLSX:
+		xw, err := newXLSXWriter(w, "Time report")
+		if err != nil {
+			return err
+		}
+		writeRow = xw.WriteRow
+		flush = xw.Flush
+		finish = xw.Close
+	default:
+		return errors.New("format must be json, csv or xlsx")
+	}
+
+	if err := writeRow(reportHeader(query)); err != nil {
+		return err
+	}
+	count := 0
+	err := s.reportRepo.StreamTimeReport(query, func(row *repositories.TimeReportRow) error {
+		if err := writeRow(reportCells(query, row)); err != nil {
+			return err
+		}
+		count++
+		if count%500 == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	totals, err := s.reportRepo.TimeReportTotals(query)
+	if err != nil {
+		return err
+	}
+	if err := writeRow(totalCells(query, totals)); err != nil {
+		return err
+	}
+	return finish()
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+type RoadmapService struct {
+	issueRepo      *repositories.IssueRepository
+	projectRepo    *repositories.ProjectRepository
+	teamRepo       *repositories.TeamRepository
+	dependencyRepo *repositories.DependencyRepository
+}
+
+func NewRoadmapService(
+	issueRepo *repositories.IssueRepository,
+	projectRepo *repositories.ProjectRepository,
+	teamRepo *repositories.TeamRepository,
+	dependencyRepo *repositories.DependencyRepository,
+) *RoadmapService {
+	return &RoadmapService{
+		issueRepo:      issueRepo,
+		projectRepo:    projectRepo,
+		teamRepo:       teamRepo,
+		dependencyRepo: dependencyRepo,
+	}
+}
+
+// RoadmapItem is one issue on the timeline. Dates are inclusive days.
+type RoadmapItem struct {
+	IssueID        uint                 `json:"issue_id"`
+	Title          string               `json:"title"`
+	TeamID         uint                 `json:"team_id"`
+	StatusID       *uint                `json:"status_id,omitempty"`
+	Priority       models.IssuePriority `json:"priority"`
+	AssigneeIDs    []uint               `json:"assignee_ids"`
+	DependsOn      []uint               `json:"depends_on"`
+	Scheduled      bool                 `json:"scheduled"`
+	StartDate      string               `json:"start_date,omitempty"`
+	EndDate        string               `json:"end_date,omitempty"`
+	Deadline       string               `json:"deadline,omitempty"`
+	DurationDays   int                  `json:"duration_days"`
+	EarliestStart  string               `json:"earliest_start,omitempty"`
+	EarliestFinish string               `json:"earliest_finish,omitempty"`
+	LatestStart    string               `json:"latest_start,omitempty"`
+	LatestFinish   string               `json:"latest_finish,omitempty"`
+	SlackDays      int                  `json:"slack_days"`
+	IsCritical     bool                 `json:"is_critical"`
+	MissesDeadline bool                 `json:"misses_deadline"`
+}
+
+// ScheduleConflict flags a dependent issue planned to start before its blocker ends
+type ScheduleConflict struct {
+	IssueID    uint   `json:"issue_id"`
+	BlockerID  uint   `json:"blocker_id"`
+	IssueStart string `json:"issue_start"`
+	BlockerEnd string `json:"blocker_end"`
+	Message    string `json:"message"`
+}
+
+type Roadmap struct {
+	StartDate    string             `json:"start_date,omitempty"`
+	EndDate      string             `json:"end_date,omitempty"`
+	Items        []RoadmapItem      `json:"items"`
+	CriticalPath []uint             `json:"critical_path"`
+	Conflicts    []ScheduleConflict `json:"conflicts"`
+}
+
+func (s *RoadmapService) GetDependencies(issueID uint) ([]models.IssueDependency, error) {
+	return s.dependencyRepo.FindBlockers(issueID)
+}
+
+// AddDependency records that issueID is blocked by dependsOnID, refusing
+// dependencies that would create a cycle.
+func (s *RoadmapService) AddDependency(issueID, dependsOnID, userID uint) error {
+	if issueID == dependsOnID {
+		return errors.New("an issue cannot depend on itself")
+	}
+
+	issue, err := s.issueRepo.FindByID(issueID)
+	if err != nil {
+		return errors.New("issue not found")
+	}
+	blocker, err := s.issueRepo.FindByID(dependsOnID)
+	if err != nil {
+		return errors.New("blocking issue not found")
+	}
+	if issue.Team.OrganizationID != blocker.Team.OrganizationID {
+		return errors.New("blocking issue belongs to another organization")
+	}
+
+	// Walk the blocker's own dependencies; reaching issueID means a cycle
+	visited := map[uint]bool{}
+	queue := []uint{dependsOnID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == issueID {
+			return errors.New("dependency would create a cycle")
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		next, err := s.dependencyRepo.FindBlockerIDs(current)
+		if err != nil {
+			return err
+		}
+		queue = append(queue, next...)
+	}
+
+	return s.dependencyRepo.Create(&models.IssueDependency{
+		IssueID:     issueID,
+		DependsOnID: dependsOnID,
+		CreatedBy:   &userID,
+	})
+}
+
+func (s *RoadmapService) RemoveDependency(issueID, dependsOnID uint) error {
+	return s.dependencyRepo.Delete(issueID, dependsOnID)
+}
+
+func (s *RoadmapService) GetTeamRoadmap(teamID uint) (*Roadmap, error) {
+	issues, err := s.issueRepo.FindByTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildRoadmap(issues)
+}
+
+// GetProjectRoadmap builds the timeline of a project from the issues of the
+// teams the viewer belongs to.
+func (s *RoadmapService) GetProjectRoadmap(projectID, orgID, viewerID uint) (*Roadmap, error) {
+	project, err := s.projectRepo.FindByID(projectID)
+	if err != nil || project.OrganizationID != orgID {
+		return nil, errors.New("project not found")
+	}
+
+	teamIDs, err := s.teamRepo.FindTeamIDsByUser(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := s.projectRepo.FindIssues(projectID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildRoadmap(issues)
+}
+
+func (s *RoadmapService) buildRoadmap(issues []models.Issue) (*Roadmap, error) {
+	roadmap := &Roadmap{
+		Items:        []RoadmapItem{},
+		CriticalPath: []uint{},
+		Conflicts:    []ScheduleConflict{},
+	}
+	if len(issues) == 0 {
+		return roadmap, nil
+	}
+
+	issueIDs := make([]uint, 0, len(issues))
+	for _, issue := range issues {
+		issueIDs = append(issueIDs, issue.ID)
+	}
+	dependencies, err := s.dependencyRepo.FindAmong(issueIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Planned span of each issue: active assignments, falling back to the deadline
+	type span struct{ start, end time.Time }
+	spans := make(map[uint]span)
+	items := make(map[uint]*RoadmapItem, len(issues))
+	order := make([]uint, 0, len(issues))
+
+	var origin time.Time
+	for _, issue := range issues {
+		item := &RoadmapItem{
+			IssueID:     issue.ID,
+			Title:       issue.Title,
+			TeamID:      issue.TeamID,
+			StatusID:    issue.StatusID,
+			Priority:    issue.Priority,
+			AssigneeIDs: []uint{},
+			DependsOn:   []uint{},
+		}
+		if issue.Deadline != nil {
+			item.Deadline = issue.Deadline.Format("2006-01-02")
+		}
+
+		var sp *span
+		for _, assignment := range issue.Assignments {
+			if !assignment.IsActive {
+				continue
+			}
+			item.AssigneeIDs = append(item.AssigneeIDs, assignment.UserID)
+			if sp == nil {
+				sp = &span{assignment.StartDate, assignment.EndDate}
+				continue
+			}
+			if assignment.StartDate.Before(sp.start) {
+				sp.start = assignment.StartDate
+			}
+			if assignment.EndDate.After(sp.end) {
+				sp.end = assignment.EndDate
+			}
+		}
+		if sp == nil && issue.Deadline != nil {
+			sp = &span{*issue.Deadline, *issue.Deadline}
+		}
+
+		if sp != nil {
+			item.Scheduled = true
+			item.StartDate = sp.start.Format("2006-01-02")
+			item.EndDate = sp.end.Format("2006-01-02")
+			item.DurationDays = daysBetween(sp.start, sp.end) + 1
+			spans[issue.ID] = *sp
+			if origin.IsZero() || sp.start.Before(origin) {
+				origin = sp.start
+			}
+		}
+
+		items[issue.ID] = item
+		order = append(order, issue.ID)
+	}
+
+	// Dependency graph between scheduled issues
+	successors := make(map[uint][]uint)
+	predecessors := make(map[uint][]uint)
+	for _, dep := range dependencies {
+		items[dep.IssueID].DependsOn = append(items[dep.IssueID].DependsOn, dep.DependsOnID)
+
+		issueSpan, issueScheduled := spans[dep.IssueID]
+		blockerSpan, blockerScheduled := spans[dep.DependsOnID]
+		if !issueScheduled || !blockerScheduled {
+			continue
+		}
+		successors[dep.DependsOnID] = append(successors[dep.DependsOnID], dep.IssueID)
+		predecessors[dep.IssueID] = append(predecessors[dep.IssueID], dep.DependsOnID)
+
+		if !issueSpan.start.After(blockerSpan.end) {
+			roadmap.Conflicts = append(roadmap.Conflicts, ScheduleConflict{
+				IssueID:    dep.IssueID,
+				BlockerID:  dep.DependsOnID,
+				IssueStart: issueSpan.start.Format("2006-01-02"),
+				BlockerEnd: blockerSpan.end.Format("2006-01-02"),
+				Message:    fmt.Sprintf("Issue #%d starts before its blocker #%d ends", dep.IssueID, dep.DependsOnID),
+			})
+		}
+	}
+
+	if len(spans) == 0 {
+		for _, id := range order {
+			roadmap.Items = append(roadmap.Items, *items[id])
+		}
+		return roadmap, nil
+	}
+
+	topo, err := topologicalOrder(order, spans, predecessors, successors)
+	if err != nil {
+		return nil, err
+	}
+
+	// Forward pass: an issue starts at its planned start or the day after its latest blocker finishes
+	es := make(map[uint]int, len(topo))
+	ef := make(map[uint]int, len(topo))
+	projectFinish := 0
+	for _, id := range topo {
+		start := daysBetween(origin, spans[id].start)
+		for _, pred := range predecessors[id] {
+			if ef[pred]+1 > start {
+				start = ef[pred] + 1
+			}
+		}
+		es[id] = start
+		ef[id] = start + items[id].DurationDays - 1
+		if ef[id] > projectFinish {
+			projectFinish = ef[id]
+		}
+	}
+
+	// Backward pass
+	ls := make(map[uint]int, len(topo))
+	lf := make(map[uint]int, len(topo))
+	for i := len(topo) - 1; i >= 0; i-- {
+		id := topo[i]
+		finish := projectFinish
+		for _, succ := range successors[id] {
+			if ls[succ]-1 < finish {
+				finish = ls[succ] - 1
+			}
+		}
+		lf[id] = finish
+		ls[id] = finish - items[id].DurationDays + 1
+	}
+
+	for _, id := range topo {
+		item := items[id]
+		item.EarliestStart = origin.AddDate(0, 0, es[id]).Format("2006-01-02")
+		item.EarliestFinish = origin.AddDate(0, 0, ef[id]).Format("2006-01-02")
+		item.LatestStart = origin.AddDate(0, 0, ls[id]).Format("2006-01-02")
+		item.LatestFinish = origin.AddDate(0, 0, lf[id]).Format("2006-01-02")
+		item.SlackDays = ls[id] - es[id]
+		item.IsCritical = item.SlackDays == 0
+		if item.Deadline != "" && item.EarliestFinish > item.Deadline {
+			item.MissesDeadline = true
+		}
+		if item.IsCritical {
+			roadmap.CriticalPath = append(roadmap.CriticalPath, id)
+		}
+	}
+
+	roadmap.StartDate = origin.Format("2006-01-02")
+	roadmap.EndDate = origin.AddDate(0, 0, projectFinish).Format("2006-01-02")
+	for _, id := range order {
+		roadmap.Items = append(roadmap.Items, *items[id])
+	}
+	return roadmap, nil
+}
+
+// topologicalOrder sorts the scheduled issues so that blockers come first
+func topologicalOrder[T any](order []uint, scheduled map[uint]T, predecessors, successors map[uint][]uint) ([]uint, error) {
+	inDegree := make(map[uint]int)
+	queue := []uint{}
+	for _, id := range order {
+		if _, ok := scheduled[id]; !ok {
+			continue
+		}
+		inDegree[id] = len(predecessors[id])
+		if inDegree[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+
+	result := make([]uint, 0, len(inDegree))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		result = append(result, id)
+		for _, succ := range successors[id] {
+			inDegree[succ]--
+			if inDegree[succ] == 0 {
+				queue = append(queue, succ)
+			}
+		}
+	}
+
+	if len(result) != len(inDegree) {
+		return nil, errors.New("issue dependencies contain a cycle")
+	}
+	return result, nil
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
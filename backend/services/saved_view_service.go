@@ -0,0 +1,254 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-management/models"
+	"task-management/repositories"
+)
+
+type SavedViewService struct {
+	viewRepo     *repositories.SavedViewRepository
+	issueService *IssueService
+}
+
+func NewSavedViewService(viewRepo *repositories.SavedViewRepository, issueService *IssueService) *SavedViewService {
+	return &SavedViewService{
+		viewRepo:     viewRepo,
+		issueService: issueService,
+	}
+}
+
+type SavedViewRequest struct {
+	TeamID     uint                  `json:"team_id" binding:"required"`
+	Name       string                `json:"name" binding:"required"`
+	Visibility models.ViewVisibility `json:"visibility"`
+	Filters    models.IssueFilter    `json:"filters"`
+	SortBy     string                `json:"sort_by"`
+	SortOrder  string                `json:"sort_order"`
+	GroupBy    string                `json:"group_by"`
+	Columns    []string              `json:"columns"`
+}
+
+// IssueGroup is one group of a grouped view. Issues with several assignees
+// or labels appear in each of their groups.
+type IssueGroup struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	IssueIDs []uint `json:"issue_ids"`
+}
+
+type ViewResult struct {
+	View   *models.SavedView `json:"view"`
+	Issues []models.Issue    `json:"issues"`
+	Groups []IssueGroup      `json:"groups,omitempty"`
+}
+
+var viewGroupings = map[string]bool{
+	"status":    true,
+	"priority":  true,
+	"assignee":  true,
+	"label":     true,
+	"sprint":    true,
+	"milestone": true,
+	"project":   true,
+}
+
+var viewColumns = map[string]bool{
+	"title":           true,
+	"status":          true,
+	"priority":        true,
+	"assignees":       true,
+	"labels":          true,
+	"deadline":        true,
+	"estimate_points": true,
+	"sprint":          true,
+	"milestone":       true,
+	"project":         true,
+	"created_at":      true,
+	"updated_at":      true,
+}
+
+var defaultViewColumns = models.ViewColumns{"title", "status", "priority", "assignees", "deadline"}
+
+func (s *SavedViewService) applyRequest(view *models.SavedView, req *SavedViewRequest) error {
+	if err := validateIssueQuery(&req.Filters, req.SortBy, req.SortOrder); err != nil {
+		return err
+	}
+	if req.GroupBy != "" && !viewGroupings[req.GroupBy] {
+		return errors.New("invalid group_by field")
+	}
+	for _, column := range req.Columns {
+		if !viewColumns[column] {
+			return fmt.Errorf("invalid column: %s", column)
+		}
+	}
+
+	switch req.Visibility {
+	case "":
+		view.Visibility = models.ViewPrivate
+	case models.ViewPrivate, models.ViewTeam:
+		view.Visibility = req.Visibility
+	default:
+		return errors.New("visibility must be private or team")
+	}
+	// Only shared views can be a team default
+	if view.Visibility == models.ViewPrivate {
+		view.IsDefault = false
+	}
+
+	view.Name = req.Name
+	view.Filters = req.Filters
+	view.SortBy = req.SortBy
+	if view.SortBy == "" {
+		view.SortBy = "rank"
+	}
+	view.SortOrder = req.SortOrder
+	if view.SortOrder == "" {
+		view.SortOrder = "asc"
+	}
+	view.GroupBy = req.GroupBy
+	view.Columns = req.Columns
+	if len(view.Columns) == 0 {
+		view.Columns = defaultViewColumns
+	}
+	return nil
+}
+
+func (s *SavedViewService) Create(req *SavedViewRequest, ownerID uint) (*models.SavedView, error) {
+	view := &models.SavedView{
+		TeamID:  req.TeamID,
+		OwnerID: ownerID,
+	}
+	if err := s.applyRequest(view, req); err != nil {
+		return nil, err
+	}
+	if err := s.viewRepo.Create(view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *SavedViewService) GetByID(id uint) (*models.SavedView, error) {
+	return s.viewRepo.FindByID(id)
+}
+
+// GetVisible lists the team's shared views and the user's private views for that team
+func (s *SavedViewService) GetVisible(teamID, userID uint) ([]models.SavedView, error) {
+	return s.viewRepo.FindVisible(teamID, userID)
+}
+
+func (s *SavedViewService) GetTeamDefault(teamID uint) (*models.SavedView, error) {
+	return s.viewRepo.FindTeamDefault(teamID)
+}
+
+// Update changes a view; its team cannot be changed
+func (s *SavedViewService) Update(id uint, req *SavedViewRequest) (*models.SavedView, error) {
+	view, err := s.viewRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("view not found")
+	}
+	if err := s.applyRequest(view, req); err != nil {
+		return nil, err
+	}
+	if err := s.viewRepo.Update(view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *SavedViewService) Delete(id uint) error {
+	return s.viewRepo.Delete(id)
+}
+
+func (s *SavedViewService) SetDefault(view *models.SavedView) error {
+	if view.Visibility != models.ViewTeam {
+		return errors.New("only views shared with the team can be the default")
+	}
+	return s.viewRepo.SetTeamDefault(view.TeamID, view.ID)
+}
+
+func (s *SavedViewService) ClearDefault(view *models.SavedView) error {
+	return s.viewRepo.ClearDefault(view.ID)
+}
+
+// Execute runs the view's query and groups the result when the view has a grouping
+func (s *SavedViewService) Execute(view *models.SavedView) (*ViewResult, error) {
+	issues, err := s.issueService.Search(view.TeamID, &view.Filters, view.SortBy, view.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ViewResult{View: view, Issues: issues}
+	if view.GroupBy != "" {
+		result.Groups = groupIssues(issues, view.GroupBy)
+	}
+	return result, nil
+}
+
+// groupIssues groups issues in order of first appearance, with the "none"
+// group for issues without a value last
+func groupIssues(issues []models.Issue, groupBy string) []IssueGroup {
+	groups := []IssueGroup{}
+	index := make(map[string]int)
+	none := IssueGroup{Key: "none", Name: "None", IssueIDs: []uint{}}
+
+	add := func(key, name string, issueID uint) {
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, IssueGroup{Key: key, Name: name, IssueIDs: []uint{}})
+		}
+		groups[i].IssueIDs = append(groups[i].IssueIDs, issueID)
+	}
+	addID := func(prefix string, id *uint, issueID uint) {
+		if id == nil {
+			none.IssueIDs = append(none.IssueIDs, issueID)
+			return
+		}
+		add(fmt.Sprint(*id), fmt.Sprintf("%s #%d", prefix, *id), issueID)
+	}
+
+	for _, issue := range issues {
+		switch groupBy {
+		case "status":
+			if issue.Status == nil {
+				none.IssueIDs = append(none.IssueIDs, issue.ID)
+				continue
+			}
+			add(fmt.Sprint(issue.Status.ID), issue.Status.Name, issue.ID)
+		case "priority":
+			add(string(issue.Priority), string(issue.Priority), issue.ID)
+		case "assignee":
+			assigned := false
+			for _, assignment := range issue.Assignments {
+				if assignment.IsActive {
+					add(fmt.Sprint(assignment.UserID), assignment.User.FullName, issue.ID)
+					assigned = true
+				}
+			}
+			if !assigned {
+				none.IssueIDs = append(none.IssueIDs, issue.ID)
+			}
+		case "label":
+			if len(issue.Labels) == 0 {
+				none.IssueIDs = append(none.IssueIDs, issue.ID)
+			}
+			for _, label := range issue.Labels {
+				add(fmt.Sprint(label.ID), label.Name, issue.ID)
+			}
+		case "sprint":
+			addID("Sprint", issue.SprintID, issue.ID)
+		case "milestone":
+			addID("Milestone", issue.MilestoneID, issue.ID)
+		case "project":
+			addID("Project", issue.ProjectID, issue.ID)
+		}
+	}
+
+	if len(none.IssueIDs) > 0 {
+		groups = append(groups, none)
+	}
+	return groups
+}
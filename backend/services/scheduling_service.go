@@ -0,0 +1,438 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+var ErrMeetingConflict = errors.New("attendees are already booked")
+
+// conflictHorizonDays is how far ahead occurrences of a recurring meeting are
+// checked for conflicts
+const conflictHorizonDays = 90
+
+// maxFreeSlotDays bounds the window searched for free slots
+const maxFreeSlotDays = 31
+
+// freeSlotStep rounds the start of free slots found today up to the next
+// quarter hour
+const freeSlotStep = 15 * time.Minute
+
+type SchedulingService struct {
+	meetingRepo    *repositories.MeetingRepository
+	userRepo       *repositories.UserRepository
+	teamRepo       *repositories.TeamRepository
+	leaveService   *LeaveService
+	holidayService *HolidayService
+}
+
+func NewSchedulingService(
+	meetingRepo *repositories.MeetingRepository,
+	userRepo *repositories.UserRepository,
+	teamRepo *repositories.TeamRepository,
+	leaveService *LeaveService,
+	holidayService *HolidayService,
+) *SchedulingService {
+	return &SchedulingService{
+		meetingRepo:    meetingRepo,
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		leaveService:   leaveService,
+		holidayService: holidayService,
+	}
+}
+
+// MeetingConflict is a meeting an attendee is already in at the time of
+// another. Meetings of teams the caller is not in only show the attendee as
+// busy, without MeetingID and Title.
+type MeetingConflict struct {
+	UserID         uint      `json:"user_id"`
+	FullName       string    `json:"full_name"`
+	MeetingID      uint      `json:"meeting_id,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	OccurrenceDate string    `json:"occurrence_date"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+}
+
+// AwayAttendee is an attendee's approved leave, without its type or reason
+type AwayAttendee struct {
+	UserID    uint      `json:"user_id"`
+	FullName  string    `json:"full_name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// MeetingConflicts lists the attendees' meetings overlapping a meeting and
+// their approved leave on its dates
+type MeetingConflicts struct {
+	Policy   models.MeetingConflictPolicy `json:"policy"`
+	Meetings []MeetingConflict            `json:"meetings,omitempty"`
+	Away     []AwayAttendee               `json:"away,omitempty"`
+}
+
+// Warning summarises the conflicts for API responses
+func (c *MeetingConflicts) Warning() string {
+	if len(c.Meetings) > 0 {
+		return "Some attendees are already booked"
+	}
+	return "Some attendees are on leave"
+}
+
+type FreeSlotRequest struct {
+	AttendeeIDs     []uint `json:"attendee_ids" binding:"required"`
+	DurationMinutes int    `json:"duration_minutes" binding:"required"`
+	// From and To (YYYY-MM-DD) are inclusive days in the caller's timezone
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// FreeSlot is a time when all of the attendees are working and free, at least
+// as long as the requested duration
+type FreeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// timeSpan is the time from start up to end
+type timeSpan struct {
+	start time.Time
+	end   time.Time
+}
+
+func (a timeSpan) overlaps(b timeSpan) bool {
+	return a.start.Before(b.end) && b.start.Before(a.end)
+}
+
+// intersectSpans returns the times in both lists, which must be sorted and
+// not overlap
+func intersectSpans(a, b []timeSpan) []timeSpan {
+	var result []timeSpan
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := a[i].start, a[i].end
+		if b[j].start.After(start) {
+			start = b[j].start
+		}
+		if b[j].end.Before(end) {
+			end = b[j].end
+		}
+		if start.Before(end) {
+			result = append(result, timeSpan{start, end})
+		}
+		if a[i].end.Before(b[j].end) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+// subtractSpans removes the busy times from the sorted free times
+func subtractSpans(free, busy []timeSpan) []timeSpan {
+	sort.Slice(busy, func(i, j int) bool { return busy[i].start.Before(busy[j].start) })
+	var result []timeSpan
+	for _, span := range free {
+		start := span.start
+		for _, b := range busy {
+			if !b.end.After(start) {
+				continue
+			}
+			if !b.start.Before(span.end) {
+				break
+			}
+			if b.start.After(start) {
+				result = append(result, timeSpan{start, b.start})
+			}
+			start = b.end
+		}
+		if start.Before(span.end) {
+			result = append(result, timeSpan{start, span.end})
+		}
+	}
+	return result
+}
+
+// occurrenceSpan is an occurrence of a meeting and when it takes place
+type occurrenceSpan struct {
+	occurrence MeetingOccurrence
+	span       timeSpan
+}
+
+// occurrenceSpans expands the meeting in the time from up to to
+func occurrenceSpans(meeting *models.Meeting, from, to time.Time) []occurrenceSpan {
+	var spans []occurrenceSpan
+	for _, occurrence := range meetingOccurrences(meeting, from, to) {
+		span := timeSpan{occurrence.StartsAt, occurrence.EndsAt}
+		spans = append(spans, occurrenceSpan{occurrence, span})
+	}
+	return spans
+}
+
+// isBooked reports whether the user is invited to the meeting and has not
+// declined it
+func isBooked(meeting *models.Meeting, userID uint) (*models.User, bool) {
+	for i := range meeting.Attendees {
+		attendee := &meeting.Attendees[i]
+		if attendee.UserID == userID && attendee.Status != models.AttendeeStatusDeclined {
+			return &attendee.User, true
+		}
+	}
+	return nil, false
+}
+
+// CheckConflicts finds the other meetings the attendees are in at the time of
+// the meeting, which need not be saved yet, and their approved leave on its
+// dates. Recurring meetings are checked for conflictHorizonDays. It returns
+// nil when there are none, and ErrMeetingConflict with the conflicts when the
+// team blocks them. Attendees must be in the team's organization.
+func (s *SchedulingService) CheckConflicts(meeting *models.Meeting, attendeeIDs []uint, viewerID uint) (*MeetingConflicts, error) {
+	if len(attendeeIDs) == 0 {
+		return nil, nil
+	}
+	team, err := s.teamRepo.FindByID(meeting.TeamID)
+	if err != nil {
+		return nil, errors.New("team not found")
+	}
+	for _, id := range attendeeIDs {
+		user, err := s.userRepo.FindByID(id)
+		if err != nil || user.OrganizationID != team.OrganizationID {
+			return nil, fmt.Errorf("user %d not found", id)
+		}
+	}
+
+	from, to := meeting.StartsAt, meeting.EndsAt
+	if meeting.IsRecurring {
+		to = from.AddDate(0, 0, conflictHorizonDays)
+	}
+	own := occurrenceSpans(meeting, from, to)
+	if len(own) == 0 {
+		return nil, nil
+	}
+
+	others, err := s.meetingRepo.FindByAttendees(attendeeIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	viewerTeamIDs, err := s.teamRepo.FindTeamIDsByUser(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[uint]bool)
+	for _, teamID := range viewerTeamIDs {
+		visible[teamID] = true
+	}
+
+	conflicts := &MeetingConflicts{Policy: team.MeetingConflicts}
+	for i := range others {
+		other := &others[i]
+		if meeting.ID != 0 && other.ID == meeting.ID {
+			continue
+		}
+		for _, theirs := range occurrenceSpans(other, from, to) {
+			overlaps := false
+			for _, ours := range own {
+				if ours.span.overlaps(theirs.span) {
+					overlaps = true
+					break
+				}
+			}
+			if !overlaps {
+				continue
+			}
+			for _, userID := range attendeeIDs {
+				user, ok := isBooked(other, userID)
+				if !ok {
+					continue
+				}
+				conflict := MeetingConflict{
+					UserID:         userID,
+					FullName:       user.FullName,
+					OccurrenceDate: theirs.occurrence.OccurrenceDate,
+					Start:          theirs.span.start,
+					End:            theirs.span.end,
+				}
+				if visible[other.TeamID] {
+					conflict.MeetingID, conflict.Title = other.ID, theirs.occurrence.Title
+				}
+				conflicts.Meetings = append(conflicts.Meetings, conflict)
+			}
+		}
+	}
+	sort.SliceStable(conflicts.Meetings, func(i, j int) bool {
+		return conflicts.Meetings[i].Start.Before(conflicts.Meetings[j].Start)
+	})
+
+	// Leave is checked on the dates of the meeting in its timezone
+	zone := meeting.Zone()
+	leaves, err := s.leaveService.ApprovedBetween(attendeeIDs, calendarDay(from.In(zone)), calendarDay(to.In(zone)))
+	if err != nil {
+		return nil, err
+	}
+	for i := range leaves {
+		for _, ours := range own {
+			if leaves[i].Covers(calendarDay(ours.span.start.In(zone))) {
+				conflicts.Away = append(conflicts.Away, AwayAttendee{
+					UserID:    leaves[i].UserID,
+					FullName:  leaves[i].User.FullName,
+					StartDate: leaves[i].StartDate,
+					EndDate:   leaves[i].EndDate,
+				})
+				break
+			}
+		}
+	}
+
+	if len(conflicts.Meetings) == 0 && len(conflicts.Away) == 0 {
+		return nil, nil
+	}
+	if team.MeetingConflicts == models.MeetingConflictBlock {
+		return conflicts, ErrMeetingConflict
+	}
+	return conflicts, nil
+}
+
+// UpdateConflictPolicy sets whether the team blocks meetings with conflicts
+func (s *SchedulingService) UpdateConflictPolicy(teamID uint, policy models.MeetingConflictPolicy) (*models.Team, error) {
+	if policy != models.MeetingConflictWarn && policy != models.MeetingConflictBlock {
+		return nil, errors.New("meeting_conflict_policy must be warn or block")
+	}
+	if err := s.teamRepo.UpdateMeetingConflictPolicy(teamID, policy); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.FindByID(teamID)
+}
+
+// workingSpans returns the user's working hours on the business days of
+// their organization in the range, less their approved leave
+func workingSpans(user *models.User, calendar *BusinessCalendar, leaves []models.LeaveRequest, from, to time.Time) []timeSpan {
+	loc := userLocation(user)
+	var spans []timeSpan
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !calendar.IsBusinessDay(day) {
+			continue
+		}
+		away := false
+		for i := range leaves {
+			if leaves[i].UserID == user.ID && leaves[i].Covers(day) {
+				away = true
+				break
+			}
+		}
+		if away {
+			continue
+		}
+		start, end := workdayBounds(user, time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, loc))
+		spans = append(spans, timeSpan{start, end})
+	}
+	return spans
+}
+
+// FindFreeSlots finds the times in the window when all of the attendees are
+// in their working hours, on a business day, not on leave and not in a
+// meeting they have not declined. Slots are at least the requested duration
+// and in the caller's timezone.
+func (s *SchedulingService) FindFreeSlots(req *FreeSlotRequest, userID uint) ([]FreeSlot, error) {
+	if req.DurationMinutes < 1 || req.DurationMinutes > 24*60 {
+		return nil, errors.New("duration_minutes must be between 1 and 1440")
+	}
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		return nil, errors.New("invalid from date format")
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		return nil, errors.New("invalid to date format")
+	}
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+	if to.Sub(from) >= maxFreeSlotDays*24*time.Hour {
+		return nil, errors.New("window cannot exceed 31 days")
+	}
+
+	requester, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	loc := userLocation(requester)
+	window := timeSpan{
+		start: time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc),
+		end:   time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc),
+	}
+	if now := time.Now(); now.After(window.start) {
+		window.start = now.Truncate(freeSlotStep)
+		if window.start.Before(now) {
+			window.start = window.start.Add(freeSlotStep)
+		}
+	}
+	if !window.start.Before(window.end) {
+		return []FreeSlot{}, nil
+	}
+
+	var users []*models.User
+	seen := make(map[uint]bool)
+	for _, id := range req.AttendeeIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		user, err := s.userRepo.FindByID(id)
+		if err != nil || user.OrganizationID != requester.OrganizationID {
+			return nil, fmt.Errorf("user %d not found", id)
+		}
+		users = append(users, user)
+	}
+	if len(users) == 0 {
+		return nil, errors.New("attendee_ids is required")
+	}
+	userIDs := make([]uint, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+
+	// Working days in other timezones can reach into the window from the
+	// neighbouring days
+	dayFrom, dayTo := from.AddDate(0, 0, -1), to.AddDate(0, 0, 1)
+	calendar, err := s.holidayService.BusinessCalendar(requester.OrganizationID, dayFrom, dayTo)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := s.leaveService.ApprovedBetween(userIDs, dayFrom, dayTo)
+	if err != nil {
+		return nil, err
+	}
+	meetings, err := s.meetingRepo.FindByAttendees(userIDs, window.start, window.end)
+	if err != nil {
+		return nil, err
+	}
+	busy := make(map[uint][]timeSpan)
+	for i := range meetings {
+		for _, occurrence := range occurrenceSpans(&meetings[i], window.start, window.end) {
+			for _, id := range userIDs {
+				if _, ok := isBooked(&meetings[i], id); ok {
+					busy[id] = append(busy[id], occurrence.span)
+				}
+			}
+		}
+	}
+
+	free := []timeSpan{window}
+	for _, user := range users {
+		working := workingSpans(user, calendar, leaves, dayFrom, dayTo)
+		free = intersectSpans(free, subtractSpans(working, busy[user.ID]))
+	}
+
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	slots := []FreeSlot{}
+	for _, span := range free {
+		if span.end.Sub(span.start) >= duration {
+			slots = append(slots, FreeSlot{Start: span.start.In(loc), End: span.end.In(loc)})
+		}
+	}
+	return slots, nil
+}
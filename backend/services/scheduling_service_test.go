@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// spans builds time spans from pairs of hours on 5 January 2026, in UTC
+func spans(pairs ...int) []timeSpan {
+	base := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	var result []timeSpan
+	for i := 0; i+1 < len(pairs); i += 2 {
+		result = append(result, timeSpan{
+			start: base.Add(time.Duration(pairs[i]) * time.Hour),
+			end:   base.Add(time.Duration(pairs[i+1]) * time.Hour),
+		})
+	}
+	return result
+}
+
+func equalSpans(a, b []timeSpan) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].start.Equal(b[i].start) || !a[i].end.Equal(b[i].end) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatSpans(spans []timeSpan) []string {
+	formatted := make([]string, len(spans))
+	for i, span := range spans {
+		formatted[i] = span.start.Format("15:04") + "-" + span.end.Format("15:04")
+	}
+	return formatted
+}
+
+func TestIntersectSpans(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []timeSpan
+		want []timeSpan
+	}{
+		{"empty", nil, spans(9, 17), nil},
+		{"same", spans(9, 17), spans(9, 17), spans(9, 17)},
+		{"overlapping", spans(9, 17), spans(12, 20), spans(12, 17)},
+		{"contained", spans(9, 17), spans(10, 11, 13, 15), spans(10, 11, 13, 15)},
+		{"disjoint", spans(9, 12), spans(13, 17), nil},
+		{"touching", spans(9, 12), spans(12, 17), nil},
+		{"several each", spans(8, 12, 13, 18), spans(9, 14, 16, 20), spans(9, 12, 13, 14, 16, 18)},
+		{"one spanning several", spans(0, 24), spans(1, 2, 3, 4, 5, 6), spans(1, 2, 3, 4, 5, 6)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := intersectSpans(tt.a, tt.b); !equalSpans(got, tt.want) {
+				t.Errorf("intersectSpans = %v, want %v", formatSpans(got), formatSpans(tt.want))
+			}
+			// The intersection does not depend on the order of the lists
+			if got := intersectSpans(tt.b, tt.a); !equalSpans(got, tt.want) {
+				t.Errorf("intersectSpans reversed = %v, want %v", formatSpans(got), formatSpans(tt.want))
+			}
+		})
+	}
+}
+
+func TestSubtractSpans(t *testing.T) {
+	tests := []struct {
+		name       string
+		free, busy []timeSpan
+		want       []timeSpan
+	}{
+		{"nothing busy", spans(9, 17), nil, spans(9, 17)},
+		{"nothing free", nil, spans(9, 17), nil},
+		{"busy in the middle", spans(9, 17), spans(12, 13), spans(9, 12, 13, 17)},
+		{"busy at the start", spans(9, 17), spans(8, 10), spans(10, 17)},
+		{"busy at the end", spans(9, 17), spans(16, 18), spans(9, 16)},
+		{"busy all day", spans(9, 17), spans(8, 18), nil},
+		{"busy outside", spans(9, 17), spans(6, 8, 18, 19), spans(9, 17)},
+		{"busy touching", spans(9, 17), spans(8, 9, 17, 18), spans(9, 17)},
+		{"overlapping busy", spans(9, 17), spans(10, 12, 11, 13), spans(9, 10, 13, 17)},
+		{"busy inside busy", spans(9, 17), spans(10, 14, 11, 12), spans(9, 10, 14, 17)},
+		{"unsorted busy", spans(9, 17), spans(15, 16, 10, 11), spans(9, 10, 11, 15, 16, 17)},
+		{"busy across free spans", spans(9, 12, 13, 17), spans(11, 14), spans(9, 11, 14, 17)},
+		{"adjacent busy", spans(9, 17), spans(10, 11, 11, 12), spans(9, 10, 12, 17)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subtractSpans(tt.free, tt.busy); !equalSpans(got, tt.want) {
+				t.Errorf("subtractSpans = %v, want %v", formatSpans(got), formatSpans(tt.want))
+			}
+		})
+	}
+}
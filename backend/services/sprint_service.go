@@ -0,0 +1,457 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+type SprintService struct {
+	sprintRepo *repositories.SprintRepository
+	issueRepo  *repositories.IssueRepository
+	statusRepo *repositories.StatusRepository
+	teamRepo   *repositories.TeamRepository
+}
+
+func NewSprintService(
+	sprintRepo *repositories.SprintRepository,
+	issueRepo *repositories.IssueRepository,
+	statusRepo *repositories.StatusRepository,
+	teamRepo *repositories.TeamRepository,
+) *SprintService {
+	return &SprintService{
+		sprintRepo: sprintRepo,
+		issueRepo:  issueRepo,
+		statusRepo: statusRepo,
+		teamRepo:   teamRepo,
+	}
+}
+
+type SprintRequest struct {
+	TeamID    uint   `json:"team_id"`
+	Name      string `json:"name" binding:"required"`
+	Goal      string `json:"goal"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+}
+
+type SprintCloseResult struct {
+	Sprint         *models.Sprint `json:"sprint"`
+	RolledOverIDs  []uint         `json:"rolled_over_issue_ids"`
+	RolledOverTo   *uint          `json:"rolled_over_to_sprint_id"`
+	CompletedCount int            `json:"completed_count"`
+}
+
+type BurndownPoint struct {
+	Date            string  `json:"date"`
+	ScopePoints     int     `json:"scope_points"`
+	CompletedPoints int     `json:"completed_points"`
+	RemainingPoints int     `json:"remaining_points"`
+	IdealRemaining  float64 `json:"ideal_remaining"`
+	ScopeIssues     int     `json:"scope_issues"`
+	CompletedIssues int     `json:"completed_issues"`
+}
+
+type SprintBurndown struct {
+	SprintID  uint            `json:"sprint_id"`
+	StartDate string          `json:"start_date"`
+	EndDate   string          `json:"end_date"`
+	Series    []BurndownPoint `json:"series"`
+}
+
+func parseSprintDates(req *SprintRequest) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid start_date format (use YYYY-MM-DD)")
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid end_date format (use YYYY-MM-DD)")
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, errors.New("end date must be after start date")
+	}
+	return start, end, nil
+}
+
+func (s *SprintService) Create(req *SprintRequest, createdBy uint) (*models.Sprint, error) {
+	if _, err := s.teamRepo.FindByID(req.TeamID); err != nil {
+		return nil, errors.New("team not found")
+	}
+
+	start, end, err := parseSprintDates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint := &models.Sprint{
+		TeamID:    req.TeamID,
+		Name:      req.Name,
+		Goal:      req.Goal,
+		StartDate: start,
+		EndDate:   end,
+		State:     models.SprintPlanned,
+		CreatedBy: createdBy,
+	}
+	if err := s.sprintRepo.Create(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+func (s *SprintService) GetByID(id uint) (*models.Sprint, error) {
+	return s.sprintRepo.FindByID(id)
+}
+
+func (s *SprintService) GetByTeam(teamID uint) ([]models.Sprint, error) {
+	return s.sprintRepo.FindByTeam(teamID)
+}
+
+func (s *SprintService) Update(id uint, req *SprintRequest) (*models.Sprint, error) {
+	sprint, err := s.sprintRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("sprint not found")
+	}
+	if sprint.State == models.SprintClosed {
+		return nil, errors.New("closed sprints cannot be edited")
+	}
+
+	start, end, err := parseSprintDates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint.Name = req.Name
+	sprint.Goal = req.Goal
+	sprint.StartDate = start
+	sprint.EndDate = end
+	if err := s.sprintRepo.Update(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+func (s *SprintService) Delete(id uint) error {
+	sprint, err := s.sprintRepo.FindByID(id)
+	if err != nil {
+		return errors.New("sprint not found")
+	}
+	if sprint.State != models.SprintPlanned {
+		return errors.New("only planned sprints can be deleted")
+	}
+	// Issues return to the backlog through ON DELETE SET NULL
+	return s.sprintRepo.Delete(id)
+}
+
+func (s *SprintService) Start(id uint) (*models.Sprint, error) {
+	sprint, err := s.sprintRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("sprint not found")
+	}
+	if sprint.State != models.SprintPlanned {
+		return nil, errors.New("only planned sprints can be started")
+	}
+	if active, err := s.sprintRepo.FindActiveByTeam(sprint.TeamID); err == nil {
+		return nil, fmt.Errorf("sprint %q is already active for this team", active.Name)
+	}
+
+	now := time.Now()
+	sprint.State = models.SprintActive
+	sprint.StartedAt = &now
+	if err := s.sprintRepo.Update(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// Close finishes an active sprint. Unfinished issues roll over to
+// rolloverSprintID, or to the team's next planned sprint, or back to the
+// backlog when there is none.
+func (s *SprintService) Close(id, userID uint, rolloverSprintID *uint) (*SprintCloseResult, error) {
+	sprint, err := s.sprintRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("sprint not found")
+	}
+	if sprint.State != models.SprintActive {
+		return nil, errors.New("only active sprints can be closed")
+	}
+
+	var target *models.Sprint
+	if rolloverSprintID != nil {
+		target, err = s.sprintRepo.FindByID(*rolloverSprintID)
+		if err != nil || target.TeamID != sprint.TeamID || target.ID == sprint.ID || target.State == models.SprintClosed {
+			return nil, errors.New("invalid rollover sprint")
+		}
+	} else if next, err := s.sprintRepo.FindNextPlanned(sprint.TeamID, sprint.EndDate); err == nil {
+		target = next
+	}
+
+	finalStatuses, err := s.finalStatusIDs(sprint.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SprintCloseResult{RolledOverIDs: []uint{}}
+	for _, issue := range sprint.Issues {
+		if issue.StatusID != nil && finalStatuses[*issue.StatusID] {
+			result.CompletedCount++
+			continue
+		}
+		result.RolledOverIDs = append(result.RolledOverIDs, issue.ID)
+	}
+
+	var targetID *uint
+	var changes []models.SprintScopeChange
+	if len(result.RolledOverIDs) > 0 && target != nil {
+		targetID = &target.ID
+		result.RolledOverTo = targetID
+	}
+	for _, issueID := range result.RolledOverIDs {
+		changes = append(changes, models.SprintScopeChange{
+			SprintID:   sprint.ID,
+			IssueID:    issueID,
+			ChangeType: models.ScopeRemoved,
+			Reason:     "Rolled over at sprint close",
+			ChangedBy:  &userID,
+		})
+		if target != nil {
+			changes = append(changes, models.SprintScopeChange{
+				SprintID:   target.ID,
+				IssueID:    issueID,
+				ChangeType: models.ScopeAdded,
+				Reason:     fmt.Sprintf("Rolled over from %s", sprint.Name),
+				ChangedBy:  &userID,
+			})
+		}
+	}
+
+	now := time.Now()
+	sprint.State = models.SprintClosed
+	sprint.ClosedAt = &now
+	if err := s.sprintRepo.Close(sprint, result.RolledOverIDs, targetID, changes); err != nil {
+		return nil, err
+	}
+
+	result.Sprint, _ = s.sprintRepo.FindByID(sprint.ID)
+	return result, nil
+}
+
+func (s *SprintService) AddIssue(sprintID, issueID, userID uint) error {
+	sprint, err := s.sprintRepo.FindByID(sprintID)
+	if err != nil {
+		return errors.New("sprint not found")
+	}
+	if sprint.State == models.SprintClosed {
+		return errors.New("cannot change the scope of a closed sprint")
+	}
+
+	issue, err := s.issueRepo.FindByID(issueID)
+	if err != nil {
+		return errors.New("issue not found")
+	}
+	if issue.TeamID != sprint.TeamID {
+		return errors.New("issue belongs to another team")
+	}
+	if issue.SprintID != nil && *issue.SprintID == sprintID {
+		return errors.New("issue is already in this sprint")
+	}
+
+	// Moving from another sprint removes it from that sprint's scope
+	if issue.SprintID != nil {
+		reason := fmt.Sprintf("Moved to %s", sprint.Name)
+		if err := s.recordScopeChange(*issue.SprintID, issueID, userID, models.ScopeRemoved, reason); err != nil {
+			return err
+		}
+	}
+
+	if err := s.sprintRepo.SetIssueSprint([]uint{issueID}, &sprintID); err != nil {
+		return err
+	}
+	return s.recordScopeChange(sprintID, issueID, userID, models.ScopeAdded, "")
+}
+
+func (s *SprintService) RemoveIssue(sprintID, issueID, userID uint) error {
+	sprint, err := s.sprintRepo.FindByID(sprintID)
+	if err != nil {
+		return errors.New("sprint not found")
+	}
+	if sprint.State == models.SprintClosed {
+		return errors.New("cannot change the scope of a closed sprint")
+	}
+
+	issue, err := s.issueRepo.FindByID(issueID)
+	if err != nil {
+		return errors.New("issue not found")
+	}
+	if issue.SprintID == nil || *issue.SprintID != sprintID {
+		return errors.New("issue is not in this sprint")
+	}
+
+	if err := s.sprintRepo.SetIssueSprint([]uint{issueID}, nil); err != nil {
+		return err
+	}
+	return s.recordScopeChange(sprintID, issueID, userID, models.ScopeRemoved, "")
+}
+
+func (s *SprintService) GetScopeChanges(sprintID uint) ([]models.SprintScopeChange, error) {
+	return s.sprintRepo.GetScopeChanges(sprintID)
+}
+
+// GetBurndown computes daily burndown/burnup series for a sprint from its
+// scope changes, the issues' status history and their estimate points.
+func (s *SprintService) GetBurndown(sprintID uint) (*SprintBurndown, error) {
+	sprint, err := s.sprintRepo.FindByID(sprintID)
+	if err != nil {
+		return nil, errors.New("sprint not found")
+	}
+
+	finalStatuses, err := s.finalStatusIDs(sprint.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := s.sprintRepo.FindScopeIssues(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.sprintRepo.GetScopeChanges(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	issueIDs := make([]uint, 0, len(issues))
+	for _, issue := range issues {
+		issueIDs = append(issueIDs, issue.ID)
+	}
+	statusLogs := []models.IssueStatusLog{}
+	if len(issueIDs) > 0 {
+		if statusLogs, err = s.issueRepo.GetStatusLogs(issueIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	changesByIssue := make(map[uint][]models.SprintScopeChange)
+	for _, change := range changes {
+		changesByIssue[change.IssueID] = append(changesByIssue[change.IssueID], change)
+	}
+	logsByIssue := make(map[uint][]models.IssueStatusLog)
+	for _, log := range statusLogs {
+		logsByIssue[log.IssueID] = append(logsByIssue[log.IssueID], log)
+	}
+
+	// Series runs until the end date, or today while the sprint is still running
+	last := sprint.EndDate
+	if sprint.ClosedAt == nil {
+		today := time.Now()
+		today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, sprint.EndDate.Location())
+		if today.Before(last) {
+			last = today
+		}
+	}
+
+	totalDays := int(sprint.EndDate.Sub(sprint.StartDate).Hours()/24) + 1
+	burndown := &SprintBurndown{
+		SprintID:  sprint.ID,
+		StartDate: sprint.StartDate.Format("2006-01-02"),
+		EndDate:   sprint.EndDate.Format("2006-01-02"),
+		Series:    []BurndownPoint{},
+	}
+
+	var initialScope int
+	for day := 0; !sprint.StartDate.AddDate(0, 0, day).After(last); day++ {
+		date := sprint.StartDate.AddDate(0, 0, day)
+		endOfDay := date.AddDate(0, 0, 1)
+
+		point := BurndownPoint{Date: date.Format("2006-01-02")}
+		for _, issue := range issues {
+			if !inSprintScope(issue, sprintID, changesByIssue[issue.ID], endOfDay) {
+				continue
+			}
+			points := 0
+			if issue.EstimatePoints != nil {
+				points = *issue.EstimatePoints
+			}
+			point.ScopeIssues++
+			point.ScopePoints += points
+
+			statusID := statusAt(issue, logsByIssue[issue.ID], endOfDay)
+			if statusID != nil && finalStatuses[*statusID] {
+				point.CompletedIssues++
+				point.CompletedPoints += points
+			}
+		}
+		point.RemainingPoints = point.ScopePoints - point.CompletedPoints
+
+		if day == 0 {
+			initialScope = point.ScopePoints
+		}
+		if totalDays > 1 {
+			point.IdealRemaining = float64(initialScope) * (1 - float64(day)/float64(totalDays-1))
+		}
+
+		burndown.Series = append(burndown.Series, point)
+	}
+
+	return burndown, nil
+}
+
+// inSprintScope replays the scope changes of an issue up to the given time
+func inSprintScope(issue models.Issue, sprintID uint, changes []models.SprintScopeChange, at time.Time) bool {
+	if len(changes) == 0 {
+		// Issues in the sprint without any recorded change were there from the start
+		return issue.SprintID != nil && *issue.SprintID == sprintID
+	}
+	in := false
+	for _, change := range changes {
+		if change.ChangedAt.After(at) {
+			break
+		}
+		in = change.ChangeType == models.ScopeAdded
+	}
+	return in
+}
+
+// statusAt returns the status an issue had at the given time
+func statusAt(issue models.Issue, logs []models.IssueStatusLog, at time.Time) *uint {
+	if len(logs) == 0 {
+		return issue.StatusID
+	}
+	statusID := logs[0].FromStatusID
+	for _, log := range logs {
+		if log.ChangedAt.After(at) {
+			break
+		}
+		statusID = log.ToStatusID
+	}
+	return statusID
+}
+
+func (s *SprintService) finalStatusIDs(teamID uint) (map[uint]bool, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, errors.New("team not found")
+	}
+	statuses, err := s.statusRepo.FindByOrganization(team.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	final := make(map[uint]bool)
+	for _, status := range statuses {
+		if status.IsFinal {
+			final[status.ID] = true
+		}
+	}
+	return final, nil
+}
+
+func (s *SprintService) recordScopeChange(sprintID, issueID, userID uint, changeType models.SprintScopeChangeType, reason string) error {
+	return s.sprintRepo.CreateScopeChange(&models.SprintScopeChange{
+		SprintID:   sprintID,
+		IssueID:    issueID,
+		ChangeType: changeType,
+		Reason:     reason,
+		ChangedBy:  &userID,
+	})
+}
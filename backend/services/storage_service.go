@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"mime"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -63,10 +65,11 @@ func (s *StorageService) Upload(ctx context.Context, file io.Reader, originalFil
 	storageKey := fmt.Sprintf("attachments/%s/%s%s", time.Now().Format("2006/01"), uuid.New().String(), ext)
 
 	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(s.bucketName),
-		Key:         aws.String(storageKey),
-		Body:        file,
-		ContentType: aws.String(mimeType),
+		Bucket:             aws.String(s.bucketName),
+		Key:                aws.String(storageKey),
+		Body:               file,
+		ContentType:        aws.String(mimeType),
+		ContentDisposition: aws.String(contentDisposition(originalFilename, mimeType)),
 	})
 	if err != nil {
 		return "", err
@@ -75,14 +78,73 @@ func (s *StorageService) Upload(ctx context.Context, file io.Reader, originalFil
 	return storageKey, nil
 }
 
-// GetPresignedURL generates a presigned URL for downloading a file
-func (s *StorageService) GetPresignedURL(ctx context.Context, storageKey string) (string, error) {
+// activeContentTypes can run script when opened in the browser
+var activeContentTypes = map[string]bool{
+	"text/html":             true,
+	"application/xhtml+xml": true,
+	"image/svg+xml":         true,
+	"application/xml":       true,
+	"text/xml":              true,
+}
+
+func isActiveContent(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return true
+	}
+	return activeContentTypes[strings.ToLower(mediaType)]
+}
+
+// contentDisposition forces active content to download instead of rendering
+// inline on the storage domain
+func contentDisposition(filename, mimeType string) string {
+	disposition := "inline"
+	if isActiveContent(mimeType) {
+		disposition = "attachment"
+	}
+
+	// ASCII fallback for old clients, the exact name in filename*
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, filepath.Base(filename))
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, fallback, encodeExtValue(filepath.Base(filename)))
+}
+
+// encodeExtValue percent-encodes every byte of a parameter value outside the
+// attr-char set of RFC 5987
+func encodeExtValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// GetPresignedURL generates a presigned URL for downloading a file. The
+// response headers are overridden so files uploaded before dispositions were
+// stored are served safely too.
+func (s *StorageService) GetPresignedURL(ctx context.Context, storageKey, filename, mimeType string) (string, error) {
 	presignClient := s3.NewPresignClient(s.client)
 
-	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(storageKey),
-	}, s3.WithPresignExpires(15*time.Minute))
+	input := &s3.GetObjectInput{
+		Bucket:                     aws.String(s.bucketName),
+		Key:                        aws.String(storageKey),
+		ResponseContentDisposition: aws.String(contentDisposition(filename, mimeType)),
+	}
+	if isActiveContent(mimeType) {
+		input.ResponseContentType = aws.String("application/octet-stream")
+	}
+
+	request, err := presignClient.PresignGetObject(ctx, input, s3.WithPresignExpires(15*time.Minute))
 	if err != nil {
 		return "", err
 	}
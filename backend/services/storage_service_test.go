@@ -0,0 +1,40 @@
+package services
+
+import (
+	"mime"
+	"path/filepath"
+	"testing"
+)
+
+func TestContentDisposition(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		mimeType string
+		want     string
+	}{
+		{"inline", "report.pdf", "application/pdf", `inline; filename="report.pdf"; filename*=UTF-8''report.pdf`},
+		{"active content", "page.html", "text/html; charset=utf-8", `attachment; filename="page.html"; filename*=UTF-8''page.html`},
+		{"unparsable type", "x.bin", "not a type", `attachment; filename="x.bin"; filename*=UTF-8''x.bin`},
+		{"separators", "a;b=c.txt", "text/plain", `inline; filename="a;b=c.txt"; filename*=UTF-8''a%3Bb%3Dc.txt`},
+		{"quotes and commas", `it's "a", b.txt`, "text/plain", `inline; filename="it's _a_, b.txt"; filename*=UTF-8''it%27s%20%22a%22%2C%20b.txt`},
+		{"non-ASCII", "résumé.pdf", "application/pdf", `inline; filename="r_sum_.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9.pdf`},
+		{"path", "../../etc/passwd", "text/plain", `inline; filename="passwd"; filename*=UTF-8''passwd`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := contentDisposition(tt.filename, tt.mimeType)
+			if got != tt.want {
+				t.Errorf("contentDisposition(%q, %q) = %s, want %s", tt.filename, tt.mimeType, got, tt.want)
+			}
+			// The extended filename must give back the base name
+			_, params, err := mime.ParseMediaType(got)
+			if err != nil {
+				t.Fatalf("contentDisposition(%q, %q) = %s does not parse: %v", tt.filename, tt.mimeType, got, err)
+			}
+			if want := filepath.Base(tt.filename); params["filename"] != want {
+				t.Errorf("parsed filename = %q, want %q", params["filename"], want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+type TimerService struct {
+	timerRepo           *repositories.TimerRepository
+	workLogRepo         *repositories.WorkLogRepository
+	userRepo            *repositories.UserRepository
+	notificationService *NotificationService
+	timesheetService    *TimesheetService
+	allowConcurrent     bool
+}
+
+func NewTimerService(
+	timerRepo *repositories.TimerRepository,
+	workLogRepo *repositories.WorkLogRepository,
+	userRepo *repositories.UserRepository,
+	notificationService *NotificationService,
+	timesheetService *TimesheetService,
+	allowConcurrent bool,
+) *TimerService {
+	return &TimerService{
+		timerRepo:           timerRepo,
+		workLogRepo:         workLogRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		timesheetService:    timesheetService,
+		allowConcurrent:     allowConcurrent,
+	}
+}
+
+var ErrTimerAlreadyRunning = errors.New("another timer is already running")
+
+// ErrDailyWorkLimit stops a timer from logging on a day already at
+// maxDailyWorkMinutes
+var ErrDailyWorkLimit = errors.New("the work logged on the timer's day is already at the daily limit")
+
+// withElapsed fills in the computed elapsed time of timers
+func withElapsed(timers []models.WorkTimer) []models.WorkTimer {
+	now := time.Now()
+	for i := range timers {
+		timers[i].ElapsedSeconds = int(timers[i].Elapsed(now).Seconds())
+	}
+	return timers
+}
+
+func (s *TimerService) GetForUser(userID uint) ([]models.WorkTimer, error) {
+	timers, err := s.timerRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return withElapsed(timers), nil
+}
+
+func (s *TimerService) GetByID(id uint) (*models.WorkTimer, error) {
+	timer, err := s.timerRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	timer.ElapsedSeconds = int(timer.Elapsed(time.Now()).Seconds())
+	return timer, nil
+}
+
+func (s *TimerService) checkConcurrent(userID, timerID uint) error {
+	if s.allowConcurrent {
+		return nil
+	}
+	running, err := s.timerRepo.CountRunning(userID, timerID)
+	if err != nil {
+		return err
+	}
+	if running > 0 {
+		return ErrTimerAlreadyRunning
+	}
+	return nil
+}
+
+// Start starts a timer on an issue. A user has at most one timer per issue.
+func (s *TimerService) Start(userID, issueID uint, notes string, billable bool) (*models.WorkTimer, error) {
+	if _, err := s.timerRepo.FindByUserAndIssue(userID, issueID); err == nil {
+		return nil, errors.New("a timer for this issue already exists")
+	}
+	if err := s.checkConcurrent(userID, 0); err != nil {
+		return nil, err
+	}
+
+	// The time will be logged on today's date, which must still be open
+	now := time.Now()
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.timesheetService.CheckUnlocked(userID, now.In(userLocation(user))); err != nil {
+		return nil, err
+	}
+
+	timer := &models.WorkTimer{
+		UserID:    userID,
+		IssueID:   issueID,
+		State:     models.TimerRunning,
+		StartedAt: &now,
+		Billable:  billable,
+		Notes:     notes,
+	}
+	if err := s.timerRepo.Create(timer); err != nil {
+		return nil, err
+	}
+	return s.GetByID(timer.ID)
+}
+
+func (s *TimerService) Pause(timer *models.WorkTimer) error {
+	if timer.State != models.TimerRunning {
+		return errors.New("timer is not running")
+	}
+	timer.AccumulatedSeconds = int(timer.Elapsed(time.Now()).Seconds())
+	timer.State = models.TimerPaused
+	timer.StartedAt = nil
+	timer.ElapsedSeconds = timer.AccumulatedSeconds
+	return s.timerRepo.Update(timer)
+}
+
+func (s *TimerService) Resume(timer *models.WorkTimer) error {
+	if timer.State != models.TimerPaused {
+		return errors.New("timer is not paused")
+	}
+	if err := s.checkConcurrent(timer.UserID, timer.ID); err != nil {
+		return err
+	}
+	now := time.Now()
+	timer.State = models.TimerRunning
+	timer.StartedAt = &now
+	timer.ElapsedSeconds = timer.AccumulatedSeconds
+	return s.timerRepo.Update(timer)
+}
+
+// Stop ends the timer and logs the tracked time as work on the day the timer
+// was last started, in the user's timezone
+func (s *TimerService) Stop(timer *models.WorkTimer) (*models.IssueWorkLog, error) {
+	return s.stopAt(timer, time.Now())
+}
+
+// timerWorkLog is the work log of the time tracked by a timer stopped at the
+// given time, dated on the day the timer was last started in the user's
+// timezone
+func timerWorkLog(timer *models.WorkTimer, user *models.User, at time.Time) *models.IssueWorkLog {
+	// Round to the nearest minute, logging at least one
+	minutes := int(math.Round(timer.Elapsed(at).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+
+	// A paused timer has no start; its day is the one it was created on
+	started := timer.CreatedAt
+	if timer.StartedAt != nil {
+		started = *timer.StartedAt
+	}
+	started = started.In(userLocation(user))
+	return &models.IssueWorkLog{
+		IssueID:      timer.IssueID,
+		UserID:       timer.UserID,
+		WorkDate:     time.Date(started.Year(), started.Month(), started.Day(), 0, 0, 0, 0, time.UTC),
+		MinutesSpent: minutes,
+		Billable:     timer.Billable,
+		Notes:        timer.Notes,
+	}
+}
+
+func (s *TimerService) stopAt(timer *models.WorkTimer, at time.Time) (*models.IssueWorkLog, error) {
+	user, err := s.userRepo.FindByID(timer.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	workLog := timerWorkLog(timer, user, at)
+
+	// Timers are held to the daily cap of logged work; time beyond it is not
+	// logged
+	logged, err := s.workLogRepo.SumMinutes(workLog.UserID, workLog.WorkDate, 0)
+	if err != nil {
+		return nil, err
+	}
+	remaining := maxDailyWorkMinutes - logged
+	if remaining < 1 {
+		return nil, ErrDailyWorkLimit
+	}
+	if workLog.MinutesSpent > remaining {
+		workLog.MinutesSpent = remaining
+	}
+
+	description := fmt.Sprintf("Logged %s from a timer", formatMinutes(workLog.MinutesSpent))
+	activity := func() (*models.IssueActivity, error) {
+		return workLogActivity(models.ActivityWorkLogged, workLog, timer.UserID, description, nil)
+	}
+	if err := s.timerRepo.Complete(timer, workLog, activity); err != nil {
+		return nil, err
+	}
+	return workLog, nil
+}
+
+// Discard deletes a timer without logging work
+func (s *TimerService) Discard(timer *models.WorkTimer) error {
+	return s.timerRepo.Delete(timer.ID)
+}
+
+// autoStopTime returns when a running timer left on overnight should have
+// stopped: the end of the working day it was started on, or midnight when it
+// was started after working hours. The second result is false while that day
+// is not over yet.
+func autoStopTime(user *models.User, startedAt, now time.Time) (time.Time, bool) {
+	_, end := workdayBounds(user, startedAt)
+	local := startedAt.In(end.Location())
+	nextMidnight := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, end.Location())
+
+	if now.Before(nextMidnight) {
+		return time.Time{}, false
+	}
+	if !end.After(startedAt) {
+		return nextMidnight, true
+	}
+	return end, true
+}
+
+// AutoStopOvernight stops running timers whose day is over at the end of the
+// user's working day and notifies the user. Timers whose day is already at
+// the daily limit are discarded, so that they do not keep running.
+func (s *TimerService) AutoStopOvernight() error {
+	timers, err := s.timerRepo.FindAllRunning()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range timers {
+		timer := &timers[i]
+		if timer.StartedAt == nil {
+			continue
+		}
+		user, err := s.userRepo.FindByID(timer.UserID)
+		if err != nil {
+			continue
+		}
+		stopAt, due := autoStopTime(user, *timer.StartedAt, now)
+		if !due {
+			continue
+		}
+
+		var outcome string
+		workLog, err := s.stopAt(timer, stopAt)
+		switch {
+		case err == nil:
+			outcome = formatMinutes(workLog.MinutesSpent) + " was logged"
+			if tracked := timerWorkLog(timer, user, stopAt).MinutesSpent; tracked > workLog.MinutesSpent {
+				outcome += fmt.Sprintf(" of the %s tracked, as the rest is over the daily limit", formatMinutes(tracked))
+			}
+		case errors.Is(err, ErrDailyWorkLimit):
+			if err := s.Discard(timer); err != nil {
+				log.Printf("Failed to discard timer %d: %v", timer.ID, err)
+				continue
+			}
+			outcome = "nothing was logged, as its day is already at the daily limit"
+		default:
+			log.Printf("Failed to auto-stop timer %d: %v", timer.ID, err)
+			continue
+		}
+
+		what := fmt.Sprintf("issue #%d", timer.IssueID)
+		if timer.Issue != nil {
+			what = fmt.Sprintf("issue #%d %q", timer.IssueID, timer.Issue.Title)
+		}
+		if err := s.notificationService.Notify(&models.Notification{
+			UserID:  timer.UserID,
+			Type:    models.NotificationTimerStopped,
+			IssueID: &timer.IssueID,
+			Message: fmt.Sprintf("Your timer on %s was still running and was stopped at %s; %s",
+				what, stopAt.In(userLocation(user)).Format("Jan 2 15:04"), outcome),
+		}); err != nil {
+			log.Printf("Failed to notify user %d about timer %d: %v", timer.UserID, timer.ID, err)
+		}
+	}
+	return nil
+}
+
+// StartAutoStop runs AutoStopOvernight in the background at the given interval
+func (s *TimerService) StartAutoStop(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.AutoStopOvernight(); err != nil {
+				log.Printf("Timer auto-stop failed: %v", err)
+			}
+		}
+	}()
+}
+
+// formatMinutes formats a duration in minutes as "2h 30m"
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
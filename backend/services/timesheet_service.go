@@ -0,0 +1,282 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrWeekLocked = errors.New("the timesheet for this week has been submitted or approved and is locked")
+
+type TimesheetService struct {
+	timesheetRepo       *repositories.TimesheetRepository
+	workLogRepo         *repositories.WorkLogRepository
+	timerRepo           *repositories.TimerRepository
+	teamRepo            *repositories.TeamRepository
+	userRepo            *repositories.UserRepository
+	notificationService *NotificationService
+}
+
+func NewTimesheetService(
+	timesheetRepo *repositories.TimesheetRepository,
+	workLogRepo *repositories.WorkLogRepository,
+	timerRepo *repositories.TimerRepository,
+	teamRepo *repositories.TeamRepository,
+	userRepo *repositories.UserRepository,
+	notificationService *NotificationService,
+) *TimesheetService {
+	return &TimesheetService{
+		timesheetRepo:       timesheetRepo,
+		workLogRepo:         workLogRepo,
+		timerRepo:           timerRepo,
+		teamRepo:            teamRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// TimesheetIssue holds the minutes logged on one issue for each day of the week
+type TimesheetIssue struct {
+	IssueID      uint   `json:"issue_id"`
+	Title        string `json:"title"`
+	TeamID       uint   `json:"team_id"`
+	Minutes      [7]int `json:"minutes"`
+	TotalMinutes int    `json:"total_minutes"`
+}
+
+type TimesheetDay struct {
+	Date    string `json:"date"`
+	Minutes int    `json:"minutes"`
+}
+
+// TimesheetWeek is a user's week of work logs. Status is "open" until the
+// week is submitted.
+type TimesheetWeek struct {
+	UserID       uint              `json:"user_id"`
+	WeekStart    string            `json:"week_start"`
+	WeekEnd      string            `json:"week_end"`
+	Status       string            `json:"status"`
+	Locked       bool              `json:"locked"`
+	Timesheet    *models.Timesheet `json:"timesheet,omitempty"`
+	Days         []TimesheetDay    `json:"days"`
+	Issues       []TimesheetIssue  `json:"issues"`
+	TotalMinutes int               `json:"total_minutes"`
+}
+
+type ReviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+// weekStart returns the Monday of the week containing the date
+func weekStart(date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// ParseWeek resolves a YYYY-MM-DD date to the Monday of its week. An empty
+// value means the current week in the user's timezone.
+func (s *TimesheetService) ParseWeek(value string, userID uint) (time.Time, error) {
+	if value == "" {
+		now := time.Now()
+		if user, err := s.userRepo.FindByID(userID); err == nil {
+			now = now.In(userLocation(user))
+		}
+		return weekStart(now), nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid week, expected YYYY-MM-DD")
+	}
+	return weekStart(date), nil
+}
+
+// CheckUnlocked returns ErrWeekLocked when the user's week containing the date
+// has been submitted or approved
+func (s *TimesheetService) CheckUnlocked(userID uint, date time.Time) error {
+	timesheet, err := s.timesheetRepo.FindByUserAndWeek(userID, weekStart(date))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if timesheet.IsLocked() {
+		return ErrWeekLocked
+	}
+	return nil
+}
+
+// CanReview reports whether the reviewer manages a team the user belongs to
+func (s *TimesheetService) CanReview(reviewerID, userID uint) bool {
+	teamIDs, err := s.teamRepo.FindTeamIDsByUser(userID)
+	if err != nil {
+		return false
+	}
+	for _, teamID := range teamIDs {
+		member, err := s.teamRepo.GetMemberRole(teamID, reviewerID)
+		if err == nil && member.Role == models.RoleManager {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWeek builds the user's timesheet for the week starting on the given Monday
+func (s *TimesheetService) GetWeek(userID uint, start time.Time) (*TimesheetWeek, error) {
+	end := start.AddDate(0, 0, 6)
+	logs, err := s.workLogRepo.FindByUserBetween(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	week := &TimesheetWeek{
+		UserID:    userID,
+		WeekStart: start.Format("2006-01-02"),
+		WeekEnd:   end.Format("2006-01-02"),
+		Status:    "open",
+		Days:      make([]TimesheetDay, 7),
+		Issues:    []TimesheetIssue{},
+	}
+	for i := range week.Days {
+		week.Days[i].Date = start.AddDate(0, 0, i).Format("2006-01-02")
+	}
+
+	index := make(map[uint]int)
+	for _, log := range logs {
+		day := int(log.WorkDate.Sub(start).Hours() / 24)
+		if day < 0 || day > 6 {
+			continue
+		}
+		i, ok := index[log.IssueID]
+		if !ok {
+			i = len(week.Issues)
+			index[log.IssueID] = i
+			week.Issues = append(week.Issues, TimesheetIssue{
+				IssueID: log.IssueID,
+				Title:   log.Issue.Title,
+				TeamID:  log.Issue.TeamID,
+			})
+		}
+		week.Issues[i].Minutes[day] += log.MinutesSpent
+		week.Issues[i].TotalMinutes += log.MinutesSpent
+		week.Days[day].Minutes += log.MinutesSpent
+		week.TotalMinutes += log.MinutesSpent
+	}
+
+	timesheet, err := s.timesheetRepo.FindByUserAndWeek(userID, start)
+	if err == nil {
+		week.Timesheet = timesheet
+		week.Status = string(timesheet.Status)
+		week.Locked = timesheet.IsLocked()
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return week, nil
+}
+
+// Submit hands the week in for approval, which locks its work logs. Weeks
+// with timers still going cannot be submitted, since stopping the timer would
+// add to the week.
+func (s *TimesheetService) Submit(userID uint, start time.Time) (*TimesheetWeek, error) {
+	week, err := s.GetWeek(userID, start)
+	if err != nil {
+		return nil, err
+	}
+	if week.Locked {
+		return nil, errors.New("this week has already been submitted")
+	}
+	if week.TotalMinutes == 0 {
+		return nil, errors.New("no work has been logged this week")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	timers, err := s.timerRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, timer := range timers {
+		if weekStart(timer.CreatedAt.In(userLocation(user))).Equal(start) {
+			return nil, errors.New("stop or discard the timers started this week before submitting")
+		}
+	}
+
+	timesheet := week.Timesheet
+	if timesheet == nil {
+		timesheet = &models.Timesheet{UserID: userID, WeekStart: start}
+	}
+	timesheet.Status = models.TimesheetSubmitted
+	timesheet.TotalMinutes = week.TotalMinutes
+	timesheet.SubmittedAt = time.Now()
+	// A resubmitted week is reviewed afresh
+	timesheet.ReviewedBy = nil
+	timesheet.ReviewedAt = nil
+	timesheet.ReviewComment = ""
+	timesheet.Reviewer = nil
+	if err := s.timesheetRepo.Save(timesheet); err != nil {
+		return nil, err
+	}
+	return s.GetWeek(userID, start)
+}
+
+func (s *TimesheetService) GetByID(id uint) (*models.Timesheet, error) {
+	return s.timesheetRepo.FindByID(id)
+}
+
+// Review approves or rejects a submitted timesheet and notifies its owner.
+// Rejections need a comment; rejected weeks can be corrected and resubmitted.
+func (s *TimesheetService) Review(timesheet *models.Timesheet, reviewerID uint, status models.TimesheetStatus, comment string) error {
+	if timesheet.Status != models.TimesheetSubmitted {
+		return errors.New("only submitted timesheets can be reviewed")
+	}
+	if timesheet.UserID == reviewerID {
+		return errors.New("you cannot review your own timesheet")
+	}
+	comment = strings.TrimSpace(comment)
+	if status == models.TimesheetRejected && comment == "" {
+		return errors.New("a comment is required when rejecting a timesheet")
+	}
+
+	now := time.Now()
+	timesheet.Status = status
+	timesheet.ReviewedBy = &reviewerID
+	timesheet.ReviewedAt = &now
+	timesheet.ReviewComment = comment
+	timesheet.Reviewer = nil
+	if err := s.timesheetRepo.Save(timesheet); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Your timesheet for the week of %s was %s", timesheet.WeekStart.Format("Jan 2"), status)
+	if comment != "" {
+		message += ": " + comment
+	}
+	return s.notificationService.Notify(&models.Notification{
+		UserID:  timesheet.UserID,
+		Type:    models.NotificationTimesheetReviewed,
+		ActorID: &reviewerID,
+		Message: message,
+	})
+}
+
+// GetForTeam lists the timesheets of a team's members with the given status
+// for weeks starting within the range
+func (s *TimesheetService) GetForTeam(teamID uint, status models.TimesheetStatus, from, to time.Time) ([]models.Timesheet, error) {
+	members, err := s.teamRepo.GetMembers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	return s.timesheetRepo.FindByUsers(userIDs, status, weekStart(from), to)
+}
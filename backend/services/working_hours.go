@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"task-management/models"
+	"time"
+)
+
+const clockLayout = "15:04"
+
+// userLocation loads the user's timezone, falling back to UTC when it is unknown
+func userLocation(user *models.User) *time.Location {
+	if loc, err := time.LoadLocation(user.Timezone); err == nil && user.Timezone != "" {
+		return loc
+	}
+	return time.UTC
+}
+
+// parseClock parses an HH:MM time of day into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse(clockLayout, value)
+	if err != nil {
+		return 0, errors.New("times must be formatted as HH:MM")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ValidateWorkingHours checks that a working day starts before it ends
+func ValidateWorkingHours(start, end string) error {
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return err
+	}
+	if endMinutes <= startMinutes {
+		return errors.New("workday_end must be after workday_start")
+	}
+	return nil
+}
+
+// workdayMinutes is the length of the user's working day
+func workdayMinutes(user *models.User) int {
+	start, end := workdayBounds(user, time.Now())
+	return int(end.Sub(start).Minutes())
+}
+
+// workdayBounds returns the start and end of the user's working hours on the
+// calendar day of the given moment, in the user's timezone. Invalid stored
+// hours fall back to 09:00-17:00.
+func workdayBounds(user *models.User, day time.Time) (time.Time, time.Time) {
+	loc := userLocation(user)
+	local := day.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	startMinutes, endMinutes := 9*60, 17*60
+	if ValidateWorkingHours(user.WorkdayStart, user.WorkdayEnd) == nil {
+		startMinutes, _ = parseClock(user.WorkdayStart)
+		endMinutes, _ = parseClock(user.WorkdayEnd)
+	}
+
+	start := time.Date(midnight.Year(), midnight.Month(), midnight.Day(), 0, startMinutes, 0, 0, loc)
+	end := time.Date(midnight.Year(), midnight.Month(), midnight.Day(), 0, endMinutes, 0, 0, loc)
+	return start, end
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"task-management/models"
+	"task-management/repositories"
+	"time"
+)
+
+// maxDailyWorkMinutes caps what one user can log for a single day
+const maxDailyWorkMinutes = 16 * 60
+
+type WorkLogService struct {
+	workLogRepo      *repositories.WorkLogRepository
+	timesheetService *TimesheetService
+}
+
+func NewWorkLogService(
+	workLogRepo *repositories.WorkLogRepository,
+	timesheetService *TimesheetService,
+) *WorkLogService {
+	return &WorkLogService{
+		workLogRepo:      workLogRepo,
+		timesheetService: timesheetService,
+	}
+}
+
+type WorkLogRequest struct {
+	WorkDate     string `json:"work_date" binding:"required"`
+	MinutesSpent int    `json:"minutes_spent" binding:"required"`
+	Billable     *bool  `json:"billable"`
+	Notes        string `json:"notes"`
+}
+
+// validate checks a work log before it is saved. The date may not be later
+// than today anywhere in the world, so users ahead of UTC can log their day.
+func (s *WorkLogService) validate(log *models.IssueWorkLog) error {
+	if log.MinutesSpent <= 0 {
+		return errors.New("minutes_spent must be positive")
+	}
+
+	latest := time.Now().UTC().Add(14 * time.Hour)
+	today := time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+	if log.WorkDate.After(today) {
+		return errors.New("work_date cannot be in the future")
+	}
+	if err := s.timesheetService.CheckUnlocked(log.UserID, log.WorkDate); err != nil {
+		return err
+	}
+
+	logged, err := s.workLogRepo.SumMinutes(log.UserID, log.WorkDate, log.ID)
+	if err != nil {
+		return err
+	}
+	if logged+log.MinutesSpent > maxDailyWorkMinutes {
+		return fmt.Errorf("this would bring the work logged on %s to %s, more than the %s allowed per day",
+			log.WorkDate.Format("2006-01-02"), formatMinutes(logged+log.MinutesSpent), formatMinutes(maxDailyWorkMinutes))
+	}
+	return nil
+}
+
+func (s *WorkLogService) apply(log *models.IssueWorkLog, req *WorkLogRequest) error {
+	workDate, err := time.Parse("2006-01-02", req.WorkDate)
+	if err != nil {
+		return errors.New("invalid work_date, expected YYYY-MM-DD")
+	}
+	log.WorkDate = workDate
+	log.MinutesSpent = req.MinutesSpent
+	log.Notes = strings.TrimSpace(req.Notes)
+	if req.Billable != nil {
+		log.Billable = *req.Billable
+	}
+	return s.validate(log)
+}
+
+// workLogActivity builds the activity log entry of a work log change
+func workLogActivity(activityType models.ActivityType, log *models.IssueWorkLog, userID uint, description string, extra map[string]interface{}) (*models.IssueActivity, error) {
+	metadata := map[string]interface{}{
+		"work_log_id":   log.ID,
+		"user_id":       log.UserID,
+		"work_date":     log.WorkDate.Format("2006-01-02"),
+		"minutes_spent": log.MinutesSpent,
+		"billable":      log.Billable,
+	}
+	for key, value := range extra {
+		metadata[key] = value
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	raw := string(encoded)
+
+	return &models.IssueActivity{
+		IssueID:      log.IssueID,
+		UserID:       &userID,
+		ActivityType: activityType,
+		Description:  description,
+		Metadata:     &raw,
+	}, nil
+}
+
+func (s *WorkLogService) GetByIssue(issueID uint) ([]models.IssueWorkLog, error) {
+	return s.workLogRepo.FindByIssue(issueID)
+}
+
+func (s *WorkLogService) GetByID(id uint) (*models.IssueWorkLog, error) {
+	return s.workLogRepo.FindByID(id)
+}
+
+// Create logs work by a user on an issue
+func (s *WorkLogService) Create(issueID, userID uint, req *WorkLogRequest) (*models.IssueWorkLog, error) {
+	log := &models.IssueWorkLog{IssueID: issueID, UserID: userID, Billable: true}
+	if err := s.apply(log, req); err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Logged %s on %s", formatMinutes(log.MinutesSpent), log.WorkDate.Format("2006-01-02"))
+	activity := func() (*models.IssueActivity, error) {
+		return workLogActivity(models.ActivityWorkLogged, log, userID, description, nil)
+	}
+	if err := s.workLogRepo.Create(log, activity); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// Update corrects a work log, keeping the previous values in the activity log
+func (s *WorkLogService) Update(log *models.IssueWorkLog, editorID uint, req *WorkLogRequest) error {
+	// Moving a log out of a locked week is not allowed either
+	if err := s.timesheetService.CheckUnlocked(log.UserID, log.WorkDate); err != nil {
+		return err
+	}
+
+	previousDate := log.WorkDate.Format("2006-01-02")
+	previousMinutes := log.MinutesSpent
+	if err := s.apply(log, req); err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("Corrected work log from %s on %s to %s on %s",
+		formatMinutes(previousMinutes), previousDate, formatMinutes(log.MinutesSpent), log.WorkDate.Format("2006-01-02"))
+	activity := func() (*models.IssueActivity, error) {
+		return workLogActivity(models.ActivityWorkLogUpdated, log, editorID, description, map[string]interface{}{
+			"previous_work_date":     previousDate,
+			"previous_minutes_spent": previousMinutes,
+		})
+	}
+	return s.workLogRepo.Update(log, activity)
+}
+
+func (s *WorkLogService) Delete(log *models.IssueWorkLog, userID uint) error {
+	if err := s.timesheetService.CheckUnlocked(log.UserID, log.WorkDate); err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("Deleted work log of %s on %s", formatMinutes(log.MinutesSpent), log.WorkDate.Format("2006-01-02"))
+	activity := func() (*models.IssueActivity, error) {
+		return workLogActivity(models.ActivityWorkLogDeleted, log, userID, description, nil)
+	}
+	return s.workLogRepo.Delete(log.ID, activity)
+}
@@ -0,0 +1,30 @@
+-- Migration: Add backlog rank to issues and per-team WIP limits
+-- Description: Lexicographic ranks allow drag & drop ordering with a single-row update
+
+ALTER TABLE issues ADD COLUMN rank VARCHAR(255) COLLATE "C" NOT NULL DEFAULT '';
+
+-- Backfill existing issues, newest first (matches the previous created_at DESC ordering)
+UPDATE issues SET rank = ranked.rank
+FROM (
+    SELECT id, LPAD(TO_HEX(ROW_NUMBER() OVER (PARTITION BY team_id ORDER BY created_at DESC, id)), 6, '0') || 'i' AS rank
+    FROM issues
+) AS ranked
+WHERE issues.id = ranked.id;
+
+CREATE INDEX idx_issues_team_rank ON issues(team_id, rank) WHERE deleted_at IS NULL;
+
+CREATE TABLE team_wip_limits (
+    id SERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    status_id INTEGER NOT NULL REFERENCES issue_statuses(id) ON DELETE CASCADE,
+    max_issues INTEGER NOT NULL CHECK (max_issues > 0),
+    is_strict BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(team_id, status_id)
+);
+
+CREATE TRIGGER update_team_wip_limits_updated_at BEFORE UPDATE ON team_wip_limits
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_wip_limits_team ON team_wip_limits(team_id);
@@ -0,0 +1,54 @@
+-- Migration: Create sprints and sprint_scope_changes tables
+-- Description: Team iterations with scope tracking for burndown/burnup charts
+
+DO $$ BEGIN
+    CREATE TYPE sprint_state AS ENUM ('planned', 'active', 'closed');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+DO $$ BEGIN
+    CREATE TYPE sprint_scope_change_type AS ENUM ('added', 'removed');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE sprints (
+    id SERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    goal TEXT,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    state sprint_state DEFAULT 'planned',
+    started_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_sprint_range CHECK (end_date >= start_date)
+);
+
+CREATE TRIGGER update_sprints_updated_at BEFORE UPDATE ON sprints
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Only one active sprint per team
+CREATE UNIQUE INDEX idx_sprints_team_active ON sprints(team_id) WHERE state = 'active';
+CREATE INDEX idx_sprints_team ON sprints(team_id);
+
+CREATE TABLE sprint_scope_changes (
+    id SERIAL PRIMARY KEY,
+    sprint_id INTEGER NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    issue_id INTEGER NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    change_type sprint_scope_change_type NOT NULL,
+    reason TEXT,
+    changed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_scope_changes_sprint ON sprint_scope_changes(sprint_id, changed_at);
+
+ALTER TABLE issues ADD COLUMN sprint_id INTEGER REFERENCES sprints(id) ON DELETE SET NULL;
+ALTER TABLE issues ADD COLUMN estimate_points INTEGER CHECK (estimate_points >= 0);
+
+CREATE INDEX idx_issues_sprint ON issues(sprint_id) WHERE deleted_at IS NULL;
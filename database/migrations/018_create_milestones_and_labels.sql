@@ -0,0 +1,58 @@
+-- Migration: Create milestones, labels and status categories
+-- Description: Release targets with progress tracking and label-grouped release notes
+
+-- Status categories give organization-specific workflows a common meaning
+DO $$ BEGIN
+    CREATE TYPE status_category AS ENUM ('todo', 'in_progress', 'done');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TABLE issue_statuses ADD COLUMN category status_category NOT NULL DEFAULT 'in_progress';
+
+UPDATE issue_statuses SET category = 'done' WHERE is_final = TRUE;
+UPDATE issue_statuses s SET category = 'todo'
+WHERE is_final = FALSE
+  AND position = (SELECT MIN(position) FROM issue_statuses WHERE organization_id = s.organization_id);
+
+-- Labels
+CREATE TABLE labels (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    color VARCHAR(7) DEFAULT '#6B7280',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(organization_id, name)
+);
+
+CREATE TABLE issue_labels (
+    issue_id INTEGER NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    label_id INTEGER NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (issue_id, label_id)
+);
+
+CREATE INDEX idx_issue_labels_label ON issue_labels(label_id);
+
+-- Milestones (organization-wide when team_id is NULL)
+CREATE TABLE milestones (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    team_id INTEGER REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    target_date DATE NOT NULL,
+    released_at TIMESTAMP,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_milestones_updated_at BEFORE UPDATE ON milestones
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_milestones_org ON milestones(organization_id);
+CREATE INDEX idx_milestones_team ON milestones(team_id);
+
+ALTER TABLE issues ADD COLUMN milestone_id INTEGER REFERENCES milestones(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_issues_milestone ON issues(milestone_id) WHERE deleted_at IS NULL;
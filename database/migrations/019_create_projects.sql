@@ -0,0 +1,40 @@
+-- Migration: Create projects table
+-- Description: Organization-level projects/epics grouping issues across teams
+
+DO $$ BEGIN
+    CREATE TYPE project_status AS ENUM ('planned', 'active', 'on_hold', 'completed', 'cancelled');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+DO $$ BEGIN
+    CREATE TYPE project_health AS ENUM ('on_track', 'at_risk', 'off_track');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE projects (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    owner_id INTEGER NOT NULL REFERENCES users(id),
+    start_date DATE,
+    target_date DATE,
+    status project_status DEFAULT 'planned',
+    health project_health DEFAULT 'on_track',
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_project_range CHECK (target_date IS NULL OR start_date IS NULL OR target_date >= start_date)
+);
+
+CREATE TRIGGER update_projects_updated_at BEFORE UPDATE ON projects
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_projects_org ON projects(organization_id);
+CREATE INDEX idx_projects_owner ON projects(owner_id);
+
+ALTER TABLE issues ADD COLUMN project_id INTEGER REFERENCES projects(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_issues_project ON issues(project_id) WHERE deleted_at IS NULL;
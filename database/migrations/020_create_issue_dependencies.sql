@@ -0,0 +1,15 @@
+-- Migration: Create issue_dependencies table
+-- Description: Blocker relationships between issues (source for roadmap critical path)
+
+CREATE TABLE issue_dependencies (
+    id SERIAL PRIMARY KEY,
+    issue_id INTEGER NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    depends_on_issue_id INTEGER NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(issue_id, depends_on_issue_id),
+    CONSTRAINT no_self_dependency CHECK (issue_id <> depends_on_issue_id)
+);
+
+CREATE INDEX idx_dependencies_issue ON issue_dependencies(issue_id);
+CREATE INDEX idx_dependencies_depends_on ON issue_dependencies(depends_on_issue_id);
@@ -0,0 +1,34 @@
+-- Migration: Create saved views
+-- Description: Named issue queries (filters, sort, grouping, columns), private or shared with a team
+
+DO $$ BEGIN
+    CREATE TYPE view_visibility AS ENUM ('private', 'team');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE saved_views (
+    id SERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    visibility view_visibility DEFAULT 'private',
+    filters JSONB NOT NULL DEFAULT '{}',
+    sort_by VARCHAR(50) DEFAULT 'rank',
+    sort_order VARCHAR(4) DEFAULT 'asc',
+    group_by VARCHAR(50),
+    columns JSONB NOT NULL DEFAULT '[]',
+    is_default BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT default_view_is_shared CHECK (is_default = FALSE OR visibility = 'team')
+);
+
+CREATE TRIGGER update_saved_views_updated_at BEFORE UPDATE ON saved_views
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_saved_views_team ON saved_views(team_id);
+CREATE INDEX idx_saved_views_owner ON saved_views(owner_id);
+
+-- At most one default view per team
+CREATE UNIQUE INDEX idx_saved_views_team_default ON saved_views(team_id) WHERE is_default = TRUE;
@@ -0,0 +1,38 @@
+-- Migration: Create mentions and notifications
+-- Description: @mentions in issue descriptions and comments, and in-app notifications
+
+DO $$ BEGIN
+    CREATE TYPE notification_type AS ENUM ('mention');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- comment_id is NULL for mentions in the issue description
+CREATE TABLE issue_mentions (
+    id SERIAL PRIMARY KEY,
+    issue_id INTEGER NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES issue_comments(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mentioned_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    outside_team BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_mentions_description ON issue_mentions(issue_id, user_id) WHERE comment_id IS NULL;
+CREATE UNIQUE INDEX idx_mentions_comment ON issue_mentions(comment_id, user_id) WHERE comment_id IS NOT NULL;
+CREATE INDEX idx_mentions_user ON issue_mentions(user_id);
+
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type notification_type NOT NULL,
+    issue_id INTEGER REFERENCES issues(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES issue_comments(id) ON DELETE SET NULL,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    message TEXT NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
@@ -0,0 +1,19 @@
+-- Migration: Add comment threads and reactions
+-- Description: One level of replies, resolvable threads and emoji reactions on comments
+
+ALTER TABLE issue_comments ADD COLUMN parent_id INTEGER REFERENCES issue_comments(id) ON DELETE CASCADE;
+ALTER TABLE issue_comments ADD COLUMN resolved_at TIMESTAMP;
+ALTER TABLE issue_comments ADD COLUMN resolved_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_comments_parent ON issue_comments(parent_id);
+
+CREATE TABLE comment_reactions (
+    id SERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL REFERENCES issue_comments(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(comment_id, user_id, emoji)
+);
+
+CREATE INDEX idx_comment_reactions_comment ON comment_reactions(comment_id);
@@ -0,0 +1,25 @@
+-- Migration: Add comment revisions and moderation
+-- Description: Edit history for comments and manager moderation leaving a tombstone
+
+DO $$ BEGIN
+    CREATE TYPE comment_moderation AS ENUM ('hidden', 'removed');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TABLE issue_comments ADD COLUMN edited_at TIMESTAMP;
+ALTER TABLE issue_comments ADD COLUMN moderation comment_moderation;
+ALTER TABLE issue_comments ADD COLUMN moderated_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE issue_comments ADD COLUMN moderated_at TIMESTAMP;
+ALTER TABLE issue_comments ADD COLUMN moderation_reason TEXT;
+
+-- Content of a comment before each edit
+CREATE TABLE comment_revisions (
+    id SERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL REFERENCES issue_comments(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    edited_by INTEGER NOT NULL REFERENCES users(id),
+    edited_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_comment_revisions_comment ON comment_revisions(comment_id, edited_at);
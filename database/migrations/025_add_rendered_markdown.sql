@@ -0,0 +1,5 @@
+-- Migration: Add rendered Markdown
+-- Description: Cached sanitized HTML for issue descriptions and comments
+
+ALTER TABLE issues ADD COLUMN description_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE issue_comments ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,33 @@
+-- Migration: Create work timers
+-- Description: Running timers that become work logs when stopped, and per-user working hours
+
+DO $$ BEGIN
+    CREATE TYPE timer_state AS ENUM ('running', 'paused');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'timer_stopped';
+
+-- Working hours in the user's own timezone, HH:MM
+ALTER TABLE users ADD COLUMN workday_start VARCHAR(5) NOT NULL DEFAULT '09:00';
+ALTER TABLE users ADD COLUMN workday_end VARCHAR(5) NOT NULL DEFAULT '17:00';
+
+-- started_at is the start of the current running period, NULL while paused
+CREATE TABLE work_timers (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    issue_id INTEGER NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    state timer_state NOT NULL DEFAULT 'running',
+    started_at TIMESTAMP,
+    accumulated_seconds INTEGER NOT NULL DEFAULT 0 CHECK (accumulated_seconds >= 0),
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, issue_id)
+);
+
+CREATE INDEX idx_work_timers_running ON work_timers(state) WHERE state = 'running';
+
+CREATE TRIGGER update_work_timers_updated_at BEFORE UPDATE ON work_timers
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
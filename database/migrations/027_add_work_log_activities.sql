@@ -0,0 +1,6 @@
+-- Migration: Add work log activities
+-- Description: Record created, corrected and deleted work logs in the issue activity log
+
+ALTER TYPE activity_type ADD VALUE IF NOT EXISTS 'work_logged';
+ALTER TYPE activity_type ADD VALUE IF NOT EXISTS 'work_log_updated';
+ALTER TYPE activity_type ADD VALUE IF NOT EXISTS 'work_log_deleted';
@@ -0,0 +1,31 @@
+-- Migration: Create timesheets
+-- Description: Weekly submission and manager approval of work logs
+
+DO $$ BEGIN
+    CREATE TYPE timesheet_status AS ENUM ('submitted', 'approved', 'rejected');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'timesheet_reviewed';
+
+-- week_start is the Monday of the week
+CREATE TABLE timesheets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    week_start DATE NOT NULL CHECK (EXTRACT(ISODOW FROM week_start) = 1),
+    status timesheet_status NOT NULL,
+    total_minutes INTEGER NOT NULL DEFAULT 0,
+    submitted_at TIMESTAMP NOT NULL,
+    reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    review_comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, week_start)
+);
+
+CREATE TRIGGER update_timesheets_updated_at BEFORE UPDATE ON timesheets
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_timesheets_status ON timesheets(status, week_start);
@@ -0,0 +1,21 @@
+-- Migration: Add billable time and hourly rates
+-- Description: Billable flag on work logs and timers, and rates for cost reporting
+
+ALTER TABLE issue_work_logs ADD COLUMN billable BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE work_timers ADD COLUMN billable BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- A rate applies to one user or to a team role, from effective_from on
+CREATE TABLE hourly_rates (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    role team_role,
+    rate NUMERIC(10, 2) NOT NULL CHECK (rate >= 0),
+    effective_from DATE NOT NULL,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT rate_user_or_role CHECK ((user_id IS NULL) <> (role IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_hourly_rates_user ON hourly_rates(user_id, effective_from) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_hourly_rates_role ON hourly_rates(organization_id, role, effective_from) WHERE role IS NOT NULL;
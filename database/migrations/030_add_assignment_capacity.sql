@@ -0,0 +1,9 @@
+-- Migration: Add assignment capacity
+-- Description: Estimates on assignments and an optional weekly capacity per user
+
+ALTER TABLE issue_assignments ADD COLUMN estimated_minutes INTEGER CHECK (estimated_minutes > 0);
+
+-- NULL means the user's working hours times their working days
+ALTER TABLE users ADD COLUMN weekly_capacity_minutes INTEGER CHECK (weekly_capacity_minutes > 0);
+
+CREATE INDEX idx_assignments_user_dates ON issue_assignments(user_id, start_date, end_date) WHERE is_active = TRUE;
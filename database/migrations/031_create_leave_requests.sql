@@ -0,0 +1,39 @@
+-- Migration: Create leave requests
+-- Description: Time off requested by users and approved by team managers
+
+DO $$ BEGIN
+    CREATE TYPE leave_type AS ENUM ('vacation', 'sick', 'personal', 'unpaid');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+DO $$ BEGIN
+    CREATE TYPE leave_status AS ENUM ('pending', 'approved', 'rejected', 'cancelled');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'leave_requested';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'leave_reviewed';
+
+CREATE TABLE leave_requests (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type leave_type NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT,
+    status leave_status NOT NULL DEFAULT 'pending',
+    reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    review_comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_leave_range CHECK (end_date >= start_date)
+);
+
+CREATE TRIGGER update_leave_requests_updated_at BEFORE UPDATE ON leave_requests
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_leave_requests_user_dates ON leave_requests(user_id, start_date, end_date);
+CREATE INDEX idx_leave_requests_status ON leave_requests(status);
@@ -0,0 +1,31 @@
+-- Migration: Create holiday calendars
+-- Description: Working days per organization and public holiday calendars for business-day math
+
+-- Days of the week as in Go's time.Weekday, 0 = Sunday
+ALTER TABLE organizations ADD COLUMN working_days JSONB NOT NULL DEFAULT '[1, 2, 3, 4, 5]';
+
+CREATE TABLE holiday_calendars (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_holiday_calendars_updated_at BEFORE UPDATE ON holiday_calendars
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_holiday_calendars_org ON holiday_calendars(organization_id);
+
+CREATE TABLE holidays (
+    id SERIAL PRIMARY KEY,
+    calendar_id INTEGER NOT NULL REFERENCES holiday_calendars(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    uid VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(calendar_id, date)
+);
+
+CREATE INDEX idx_holidays_date ON holidays(date);
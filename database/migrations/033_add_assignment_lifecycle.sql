@@ -0,0 +1,22 @@
+-- Migration: Add assignment lifecycle
+-- Description: Assignment roles, unassign/reassign history and single-assignee teams
+
+DO $$ BEGIN
+    CREATE TYPE assignment_role AS ENUM ('owner', 'reviewer', 'qa');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TYPE activity_type ADD VALUE IF NOT EXISTS 'unassigned';
+ALTER TYPE activity_type ADD VALUE IF NOT EXISTS 'reassigned';
+
+ALTER TABLE issue_assignments ADD COLUMN role assignment_role NOT NULL DEFAULT 'owner';
+ALTER TABLE issue_assignments ADD COLUMN unassigned_at TIMESTAMP;
+ALTER TABLE issue_assignments ADD COLUMN unassigned_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+-- Left by the previous assignee when the issue was reassigned
+ALTER TABLE issue_assignments ADD COLUMN handover_note TEXT;
+
+-- Assigning in a single-assignee team replaces the active assignee of the role
+ALTER TABLE teams ADD COLUMN single_assignee BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_assignments_issue_active ON issue_assignments(issue_id, role) WHERE is_active = TRUE;
@@ -0,0 +1,21 @@
+-- Migration: Add auto assignment
+-- Description: Per-team strategy for assigning new issues and user skills matched against labels
+
+DO $$ BEGIN
+    CREATE TYPE auto_assign_strategy AS ENUM ('none', 'round_robin', 'least_loaded', 'skills');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TABLE teams ADD COLUMN auto_assign_strategy auto_assign_strategy NOT NULL DEFAULT 'none';
+-- Last member picked by round robin
+ALTER TABLE teams ADD COLUMN auto_assign_last_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+-- Skills are organization labels; the skills strategy matches them against issue labels
+CREATE TABLE user_skills (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label_id INTEGER NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, label_id)
+);
+
+CREATE INDEX idx_user_skills_label ON user_skills(label_id);
@@ -0,0 +1,14 @@
+-- Migration: Create calendar feeds
+-- Description: Secret-token iCalendar subscription URLs per user
+
+CREATE TABLE calendar_feeds (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    -- SHA-256 of the token; the token itself is only shown when the feed is created
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    last_accessed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_calendar_feeds_user ON calendar_feeds(user_id);
@@ -0,0 +1,7 @@
+-- Migration: Add meeting external UID
+-- Description: iCalendar UID of imported meetings, so re-imports update them
+
+ALTER TABLE meetings ADD COLUMN external_uid VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_meetings_team_external_uid ON meetings(team_id, external_uid)
+    WHERE external_uid IS NOT NULL;
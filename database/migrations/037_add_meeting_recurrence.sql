@@ -0,0 +1,30 @@
+-- Migration: Add meeting recurrence rules and exceptions
+-- Description: Interval, weekdays, end date and count of recurring meetings,
+-- and changed or cancelled single occurrences
+
+ALTER TABLE meetings
+    ADD COLUMN recurrence_interval INTEGER NOT NULL DEFAULT 1 CHECK (recurrence_interval > 0),
+    -- Comma separated weekdays of weekly meetings, e.g. MO,WE,FR
+    ADD COLUMN recurrence_by_day VARCHAR(20),
+    ADD COLUMN recurrence_until DATE,
+    ADD COLUMN recurrence_count INTEGER CHECK (recurrence_count > 0);
+
+CREATE TABLE meeting_exceptions (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    -- The date the occurrence would have been on
+    occurrence_date DATE NOT NULL,
+    cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+    -- The changed occurrence; null when it is cancelled
+    title VARCHAR(500),
+    description TEXT,
+    location TEXT,
+    meeting_date DATE,
+    start_time TIME,
+    end_time TIME,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(meeting_id, occurrence_date)
+);
+
+CREATE INDEX idx_meetings_recurring ON meetings(team_id) WHERE is_recurring = TRUE;
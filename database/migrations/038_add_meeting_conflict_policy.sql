@@ -0,0 +1,10 @@
+-- Migration: Add meeting conflict policy
+-- Description: Per-team choice to warn about or block meetings whose attendees are already booked or on leave
+
+DO $$ BEGIN
+    CREATE TYPE meeting_conflict_policy AS ENUM ('warn', 'block');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TABLE teams ADD COLUMN meeting_conflict_policy meeting_conflict_policy NOT NULL DEFAULT 'warn';
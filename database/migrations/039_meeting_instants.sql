@@ -0,0 +1,50 @@
+-- Migration: Store meetings as absolute instants
+-- Description: Replace the naive meeting date and times with start and end
+-- instants and the IANA timezone their wall-clock times and recurrence follow
+
+ALTER TABLE meetings
+    ADD COLUMN timezone VARCHAR(64),
+    ADD COLUMN starts_at TIMESTAMPTZ,
+    ADD COLUMN ends_at TIMESTAMPTZ;
+
+-- Existing times were entered in the creator's timezone
+UPDATE meetings m SET timezone = COALESCE(
+    (SELECT u.timezone FROM users u
+     WHERE u.id = m.created_by AND u.timezone IN (SELECT name FROM pg_timezone_names)),
+    'UTC');
+
+-- Meetings ending at or before their start end the next day
+UPDATE meetings SET
+    starts_at = (meeting_date + start_time) AT TIME ZONE timezone,
+    ends_at = (meeting_date + end_time
+        + CASE WHEN end_time <= start_time THEN INTERVAL '1 day' ELSE INTERVAL '0' END) AT TIME ZONE timezone;
+
+ALTER TABLE meetings
+    ALTER COLUMN timezone SET NOT NULL,
+    ALTER COLUMN timezone SET DEFAULT 'UTC',
+    ALTER COLUMN starts_at SET NOT NULL,
+    ALTER COLUMN ends_at SET NOT NULL,
+    ADD CONSTRAINT meetings_ends_after_start CHECK (ends_at > starts_at);
+
+ALTER TABLE meeting_exceptions
+    ADD COLUMN starts_at TIMESTAMPTZ,
+    ADD COLUMN ends_at TIMESTAMPTZ;
+
+UPDATE meeting_exceptions e SET
+    starts_at = (e.meeting_date + e.start_time) AT TIME ZONE m.timezone,
+    ends_at = (e.meeting_date + e.end_time
+        + CASE WHEN e.end_time <= e.start_time THEN INTERVAL '1 day' ELSE INTERVAL '0' END) AT TIME ZONE m.timezone
+FROM meetings m
+WHERE m.id = e.meeting_id AND e.meeting_date IS NOT NULL;
+
+ALTER TABLE meeting_exceptions
+    DROP COLUMN meeting_date,
+    DROP COLUMN start_time,
+    DROP COLUMN end_time;
+
+ALTER TABLE meetings
+    DROP COLUMN meeting_date,
+    DROP COLUMN start_time,
+    DROP COLUMN end_time;
+
+CREATE INDEX idx_meetings_starts_at ON meetings(starts_at);
@@ -0,0 +1,15 @@
+-- Migration: Make backlog ranks unique per team
+-- Description: Concurrent creates could give two issues the same rank, leaving
+-- no rank between them to move an issue to
+
+-- Issues sharing a rank keep their order by id, after the first of them
+UPDATE issues SET rank = duplicated.rank || LPAD(TO_HEX(duplicated.position - 1), 6, '0') || 'i'
+FROM (
+    SELECT id, rank, ROW_NUMBER() OVER (PARTITION BY team_id, rank ORDER BY id) AS position
+    FROM issues
+    WHERE deleted_at IS NULL
+) AS duplicated
+WHERE issues.id = duplicated.id AND duplicated.position > 1;
+
+DROP INDEX IF EXISTS idx_issues_team_rank;
+CREATE UNIQUE INDEX idx_issues_team_rank ON issues(team_id, rank) WHERE deleted_at IS NULL;
@@ -30,13 +30,13 @@ INSERT INTO team_members (team_id, user_id, role) VALUES
 (2, 4, 'member');       -- Developer 2 in Frontend
 
 -- Insert workflow statuses for Organization (shared by all teams)
-INSERT INTO issue_statuses (organization_id, name, position, is_final, color) VALUES 
-(1, 'WAITING', 1, false, '#9CA3AF'),
-(1, 'IN_PROGRESS', 2, false, '#3B82F6'),
-(1, 'QA', 3, false, '#F59E0B'),
-(1, 'READY_TO_DEPLOY', 4, false, '#8B5CF6'),
-(1, 'DONE', 5, true, '#10B981'),
-(1, 'HOLD', 6, false, '#EF4444');
+INSERT INTO issue_statuses (organization_id, name, position, is_final, category, color) VALUES 
+(1, 'WAITING', 1, false, 'todo', '#9CA3AF'),
+(1, 'IN_PROGRESS', 2, false, 'in_progress', '#3B82F6'),
+(1, 'QA', 3, false, 'in_progress', '#F59E0B'),
+(1, 'READY_TO_DEPLOY', 4, false, 'in_progress', '#8B5CF6'),
+(1, 'DONE', 5, true, 'done', '#10B981'),
+(1, 'HOLD', 6, false, 'in_progress', '#EF4444');
 
 -- Insert demo issues
 INSERT INTO issues (team_id, status_id, title, description, priority, created_by) VALUES 